@@ -0,0 +1,164 @@
+package augment
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+)
+
+// Augmentation : 1枚の画像に対するデータ拡張のIF
+type Augmentation interface {
+	// Apply : 乱数生成器rndを利用して変換した画像を取得する（元の画像は変更しない）
+	Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel
+}
+
+// Pipeline : 複数のデータ拡張を順に適用する
+type Pipeline struct {
+	augmentations []Augmentation
+}
+
+// NewPipeline : 指定した順に適用するPipelineを取得
+func NewPipeline(augmentations ...Augmentation) *Pipeline {
+	return &Pipeline{augmentations: augmentations}
+}
+
+// Add : データ拡張を末尾に追加する
+func (p *Pipeline) Add(augmentation Augmentation) {
+	p.augmentations = append(p.augmentations, augmentation)
+}
+
+// Apply : 各データ拡張を順に適用した画像を取得する
+func (p *Pipeline) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	for _, augmentation := range p.augmentations {
+		img = augmentation.Apply(img, rnd)
+	}
+	return img
+}
+
+// ApplyImage : image.Imageにデータ拡張を適用する
+// グレースケールの画像は1チャネル、それ以外はRGBの3チャネルとして変換する
+func ApplyImage(augmentation Augmentation, img image.Image, rnd *rand.Rand) image.Image {
+	return ToImage(augmentation.Apply(FromImage(img), rnd))
+}
+
+// FromImage : image.Imageを0〜255の画素値のImageWithChannelに変換する
+// グレースケールの画像は1チャネル、それ以外はRGBの3チャネルとする
+func FromImage(img image.Image) neuralNetwork.ImageWithChannel {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	gray := img.ColorModel() == color.GrayModel
+	channel := 3
+	if gray {
+		channel = 1
+	}
+	iwc := newImageWithChannel(channel, h, w)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			px := img.At(bounds.Min.X+x, bounds.Min.Y+y)
+			if gray {
+				iwc[0][y][x] = float64(color.GrayModel.Convert(px).(color.Gray).Y)
+				continue
+			}
+			r, g, b, _ := px.RGBA()
+			iwc[0][y][x] = float64(r >> 8)
+			iwc[1][y][x] = float64(g >> 8)
+			iwc[2][y][x] = float64(b >> 8)
+		}
+	}
+	return iwc
+}
+
+// ToImage : 0〜255の画素値のImageWithChannelをimage.Imageに変換する
+// 1チャネルの場合は*image.Gray、3チャネルの場合は*image.RGBAとする
+func ToImage(iwc neuralNetwork.ImageWithChannel) image.Image {
+	c, h, w := dims(iwc)
+	if c != 1 && c != 3 {
+		panic("image.Imageに変換できるのは1チャネルまたは3チャネルの画像のみです")
+	}
+	rect := image.Rect(0, 0, w, h)
+	if c == 1 {
+		img := image.NewGray(rect)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				img.SetGray(x, y, color.Gray{toByte(iwc[0][y][x])})
+			}
+		}
+		return img
+	}
+	img := image.NewRGBA(rect)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{toByte(iwc[0][y][x]), toByte(iwc[1][y][x]), toByte(iwc[2][y][x]), 255})
+		}
+	}
+	return img
+}
+
+// toByte : 画素値を0〜255に丸める
+func toByte(v float64) uint8 {
+	return uint8(math.Min(math.Max(math.Round(v), 0), 255))
+}
+
+// dims : チャネル数・高さ・幅を取得
+func dims(iwc neuralNetwork.ImageWithChannel) (c int, h int, w int) {
+	c = len(iwc)
+	if c > 0 {
+		h = len(iwc[0])
+	}
+	if h > 0 {
+		w = len(iwc[0][0])
+	}
+	return c, h, w
+}
+
+// newImageWithChannel : 全ての画素値が0の画像を作成
+func newImageWithChannel(c int, h int, w int) neuralNetwork.ImageWithChannel {
+	return neuralNetwork.NewImageWithChannel(make([]float64, c*h*w), w, h, c)
+}
+
+// flatten : 画像を(チャネル, 高さ, 幅)の順に並べた画素値に変換
+func flatten(iwc neuralNetwork.ImageWithChannel) []float64 {
+	c, h, w := dims(iwc)
+	x := make([]float64, 0, c*h*w)
+	for _, img := range iwc {
+		for _, row := range img {
+			x = append(x, row...)
+		}
+	}
+	return x
+}
+
+// remap : 変換後の各画素(x, y)に対応する変換前の位置を、バイリニア補間で取得した画像を作成
+// 変換前の画像の範囲外は0とする
+func remap(iwc neuralNetwork.ImageWithChannel, source func(x int, y int) (sx float64, sy float64)) neuralNetwork.ImageWithChannel {
+	c, h, w := dims(iwc)
+	dst := newImageWithChannel(c, h, w)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sx, sy := source(x, y)
+			for ch := 0; ch < c; ch++ {
+				dst[ch][y][x] = bilinear(iwc[ch], sx, sy)
+			}
+		}
+	}
+	return dst
+}
+
+// bilinear : 位置(x, y)の値を周囲4画素から補間して取得（範囲外の画素は0とする）
+func bilinear(img neuralNetwork.Image, x float64, y float64) float64 {
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	return pixel(img, x0, y0)*(1-fx)*(1-fy) + pixel(img, x0+1, y0)*fx*(1-fy) +
+		pixel(img, x0, y0+1)*(1-fx)*fy + pixel(img, x0+1, y0+1)*fx*fy
+}
+
+// pixel : 画素値を取得（範囲外の場合は0）
+func pixel(img neuralNetwork.Image, x int, y int) float64 {
+	if y < 0 || y >= len(img) || x < 0 || x >= len(img[y]) {
+		return 0
+	}
+	return img[y][x]
+}
@@ -0,0 +1,76 @@
+package augment
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPipeline(t *testing.T) {
+	Convey("Given : 左右反転と平行移動のPipelineが与えられた時", t, func() {
+		img := sequenceImage(1, 3, 4)
+		pipeline := NewPipeline(NewRandomHorizontalFlip(1))
+		pipeline.Add(NewRandomShift(0, 1))
+		Convey("When : 同じシードの乱数で、Pipelineと個別のデータ拡張をそれぞれ適用する", func() {
+			actual := pipeline.Apply(img, rand.New(util.NewSource(1)))
+			rnd := rand.New(util.NewSource(1))
+			expected := NewRandomShift(0, 1).Apply(NewRandomHorizontalFlip(1).Apply(img, rnd), rnd)
+			Convey("Then : 同じ結果となり、元の画像は変更されないこと", func() {
+				So(actual, ShouldResemble, expected)
+				So(img, ShouldResemble, sequenceImage(1, 3, 4))
+			})
+		})
+	})
+}
+
+func TestImageConversion(t *testing.T) {
+	Convey("Given : グレースケールの画像が与えられた時", t, func() {
+		gray := image.NewGray(image.Rect(0, 0, 3, 2))
+		for i := range gray.Pix {
+			gray.Pix[i] = uint8(i * 40)
+		}
+		Convey("When : ImageWithChannelに変換する", func() {
+			iwc := FromImage(gray)
+			Convey("Then : 1チャネルの画素値となり、image.Imageに戻せること", func() {
+				So(len(iwc), ShouldEqual, 1)
+				So(iwc[0][1], ShouldResemble, []float64{120, 160, 200})
+				back, ok := ToImage(iwc).(*image.Gray)
+				So(ok, ShouldBeTrue)
+				So(back.Pix, ShouldResemble, gray.Pix)
+			})
+		})
+		Convey("When : 左右反転をimage.Imageに適用する", func() {
+			flipped := ApplyImage(NewRandomHorizontalFlip(1), gray, rand.New(util.NewSource(1)))
+			Convey("Then : 反転した画像が取得できること", func() {
+				So(flipped.At(0, 0), ShouldResemble, color.Gray{80})
+				So(flipped.At(2, 1), ShouldResemble, color.Gray{120})
+			})
+		})
+	})
+
+	Convey("Given : RGBの画像が与えられた時", t, func() {
+		rgba := image.NewRGBA(image.Rect(0, 0, 2, 2))
+		rgba.SetRGBA(1, 0, color.RGBA{10, 20, 30, 255})
+		Convey("When : ImageWithChannelに変換する", func() {
+			iwc := FromImage(rgba)
+			Convey("Then : 3チャネルの画素値となり、image.Imageに戻せること", func() {
+				So(len(iwc), ShouldEqual, 3)
+				So(iwc[2][0][1], ShouldEqual, 30)
+				So(ToImage(iwc).At(1, 0), ShouldResemble, color.RGBA{10, 20, 30, 255})
+			})
+		})
+		Convey("Then : 2チャネルの画像はimage.Imageに変換できないこと", func() {
+			So(func() { ToImage(sequenceImage(2, 2, 2)) }, ShouldPanic)
+		})
+	})
+}
+
+// sequenceImage : 画素値が0, 1, 2, ...と並ぶ画像を作成
+func sequenceImage(c int, h int, w int) neuralNetwork.ImageWithChannel {
+	return neuralNetwork.NewImageWithChannel(util.CreateFloatArrayByStep(c*h*w, 0, 1), w, h, c)
+}
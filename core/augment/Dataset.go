@@ -0,0 +1,111 @@
+package augment
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/goMLLibrary/core/dataset"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Dataset : 元のデータセットから取得した画像に、取得する度にデータ拡張を適用するデータセット
+// 拡張した画像はファイルなどに保存せず、ミニバッチの作成時に生成する
+// 正解データはそのまま利用する
+type Dataset struct {
+	ds           dataset.Dataset
+	shape        neuralNetwork.Shape
+	augmentation Augmentation
+
+	mutex  sync.Mutex
+	rnd    *rand.Rand
+	source *util.SplitMix64Source
+}
+
+// DatasetOption : Datasetのオプション
+type DatasetOption func(*Dataset)
+
+// NewDataset : Datasetを取得
+// ds : 元のデータセット（入力は(チャネル, 高さ, 幅)の順に画素値が並んでいるものとする）
+// shape : 1枚の画像の形状, augmentation : 適用するデータ拡張（Pipelineで複数を組み合わせられる）
+// デフォルトではutil.SetSeedで設定したライブラリ全体のシードから派生させた乱数を利用する
+// 初期化時にオプション指定が可能
+func NewDataset(ds dataset.Dataset, shape neuralNetwork.Shape, augmentation Augmentation, options ...DatasetOption) *Dataset {
+	if shape.IsEmpty() {
+		panic("画像の形状を指定してください")
+	}
+	d := Dataset{ds: ds, shape: shape, augmentation: augmentation}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&d)
+	}
+	if d.rnd == nil {
+		d.rnd, d.source = util.NewRandWithSource()
+	}
+	return &d
+}
+
+// WithDatasetSeed : データ拡張に利用する乱数のシード指定のオプションを取得
+// 同じシードで同じ順にデータを取得すれば、同じデータ拡張の結果が得られる
+func WithDatasetSeed(seed int64) DatasetOption {
+	return func(d *Dataset) {
+		d.source = util.NewSource(seed)
+		d.rnd = rand.New(d.source)
+	}
+}
+
+func (d *Dataset) Count() int {
+	return d.ds.Count()
+}
+
+func (d *Dataset) GetSample(index int) (x []float64, t []float64) {
+	sx, st := d.ds.GetSample(index)
+	return d.augmentSample(sx), st
+}
+
+// GetBatch : 指定したインデックスのデータを、データ拡張を適用した入力と正解データの行列で取得
+// 乱数はインデックスの順に利用する
+func (d *Dataset) GetBatch(indexes []int) (x mat.Matrix, t mat.Matrix) {
+	bx, bt := dataset.GetBatch(d.ds, indexes)
+	r, c := bx.Dims()
+	dst := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		dst.SetRow(i, d.augmentSample(mat.Row(nil, i, bx)))
+	}
+	return dst, bt
+}
+
+// augmentSample : 1件の入力データにデータ拡張を適用する
+func (d *Dataset) augmentSample(x []float64) []float64 {
+	if len(x) != d.shape.Size() {
+		panic(fmt.Sprintf("入力の要素数%dが画像の形状%vとマッチしてません", len(x), d.shape))
+	}
+	img := neuralNetwork.NewImageWithChannel(x, d.shape.Width, d.shape.Height, d.shape.Channel)
+
+	// DataLoaderの事前作成のgoroutineからも呼ばれるため、乱数の利用は排他制御する
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return flatten(d.augmentation.Apply(img, d.rnd))
+}
+
+// GetDataset : データ拡張前の元のデータセットを取得
+func (d *Dataset) GetDataset() dataset.Dataset {
+	return d.ds
+}
+
+// GetRandomState : データ拡張に利用する乱数の状態を取得
+func (d *Dataset) GetRandomState() uint64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.source.GetState()
+}
+
+// SetRandomState : データ拡張に利用する乱数の状態を設定（GetRandomStateで取得した時点から再開できる）
+func (d *Dataset) SetRandomState(state uint64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.source.SetState(state)
+}
@@ -0,0 +1,96 @@
+package augment
+
+import (
+	"testing"
+
+	"github.com/goMLLibrary/core/dataset"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDataset(t *testing.T) {
+	Convey("Given : 4x4の画像3件のデータセットとデータ拡張が与えられた時", t, func() {
+		x := mat.NewDense(3, 16, util.CreateFloatArrayByStep(48, 1, 1))
+		tm := mat.NewDense(3, 2, []float64{1, 0, 0, 1, 1, 0})
+		ds := dataset.NewMatrixDataset(x, tm)
+		shape := neuralNetwork.NewShape(1, 4, 4)
+		newAugmentation := func() Augmentation {
+			return NewPipeline(NewRandomCrop(1), NewRandomHorizontalFlip(0.5))
+		}
+
+		Convey("When : 同じシードで2つのDatasetを作成し、同じ順にデータを取得する", func() {
+			d1 := NewDataset(ds, shape, newAugmentation(), WithDatasetSeed(7))
+			d2 := NewDataset(ds, shape, newAugmentation(), WithDatasetSeed(7))
+			x1, t1 := d1.GetSample(0)
+			x2, t2 := d2.GetSample(0)
+			Convey("Then : 同じデータ拡張の結果となり、正解データはそのままであること", func() {
+				So(d1.Count(), ShouldEqual, 3)
+				So(x1, ShouldResemble, x2)
+				So(t1, ShouldResemble, []float64{1, 0})
+				So(t2, ShouldResemble, t1)
+			})
+		})
+
+		Convey("When : 同じデータを繰り返し取得する", func() {
+			d := NewDataset(ds, shape, newAugmentation(), WithDatasetSeed(7))
+			different := false
+			first, _ := d.GetSample(1)
+			for i := 0; i < 10 && !different; i++ {
+				next, _ := d.GetSample(1)
+				different = !floats.Equal(first, next)
+			}
+			Convey("Then : 取得する度に異なるデータ拡張が適用されること", func() {
+				So(different, ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 乱数の状態を保存してから取得し、状態を戻して再度取得する", func() {
+			d := NewDataset(ds, shape, newAugmentation(), WithDatasetSeed(3))
+			d.GetSample(0)
+			state := d.GetRandomState()
+			before, _ := d.GetSample(2)
+			d.SetRandomState(state)
+			after, _ := d.GetSample(2)
+			Convey("Then : 同じデータ拡張の結果となること", func() {
+				So(after, ShouldResemble, before)
+			})
+		})
+
+		Convey("When : GetBatchとGetSampleを同じシードで取得する", func() {
+			batchX, batchT := NewDataset(ds, shape, newAugmentation(), WithDatasetSeed(5)).GetBatch([]int{2, 0})
+			d := NewDataset(ds, shape, newAugmentation(), WithDatasetSeed(5))
+			s2, _ := d.GetSample(2)
+			s0, _ := d.GetSample(0)
+			Convey("Then : インデックスの順にデータ拡張が適用されること", func() {
+				So(mat.Row(nil, 0, batchX), ShouldResemble, s2)
+				So(mat.Row(nil, 1, batchX), ShouldResemble, s0)
+				So(mat.Row(nil, 0, batchT), ShouldResemble, []float64{1, 0})
+			})
+		})
+
+		Convey("When : 事前作成を行うDataLoaderから取得する", func() {
+			d := NewDataset(ds, shape, newAugmentation(), WithDatasetSeed(5))
+			loader := dataset.NewDataLoader(d, 2, dataset.WithDataLoaderPrefetch())
+			it := loader.Iterator()
+			count := 0
+			for it.Next() {
+				r, c := it.Batch().X.Dims()
+				count += r
+				So(c, ShouldEqual, 16)
+			}
+			Convey("Then : 全てのデータを取得できること", func() {
+				So(count, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When : 不正な形状を指定する", func() {
+			Convey("Then : panicが発生すること", func() {
+				So(func() { NewDataset(ds, neuralNetwork.Shape{}, newAugmentation()) }, ShouldPanic)
+				So(func() { NewDataset(ds, neuralNetwork.NewShape(1, 3, 3), newAugmentation()).GetSample(0) }, ShouldPanic)
+			})
+		})
+	})
+}
@@ -0,0 +1,252 @@
+package augment
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+)
+
+// RandomShift : 縦横にランダムな画素数だけ平行移動する（はみ出した部分は0で埋める）
+type RandomShift struct {
+	maxDx int
+	maxDy int
+}
+
+// NewRandomShift : RandomShiftを取得
+// maxDx, maxDy : 横・縦の移動量の最大値（-max〜maxの範囲で移動する）
+func NewRandomShift(maxDx int, maxDy int) *RandomShift {
+	if maxDx < 0 || maxDy < 0 {
+		panic("移動量の最大値は0以上を指定してください")
+	}
+	return &RandomShift{maxDx: maxDx, maxDy: maxDy}
+}
+
+func (s *RandomShift) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	dx := rnd.Intn(2*s.maxDx+1) - s.maxDx
+	dy := rnd.Intn(2*s.maxDy+1) - s.maxDy
+	return remap(img, func(x int, y int) (float64, float64) {
+		return float64(x - dx), float64(y - dy)
+	})
+}
+
+// RandomRotation : 画像の中心を軸にランダムな角度だけ回転する（はみ出した部分は0で埋める）
+type RandomRotation struct {
+	maxDegrees float64
+}
+
+// NewRandomRotation : RandomRotationを取得
+// maxDegrees : 回転角度の最大値（-maxDegrees〜maxDegrees度の範囲で回転する）
+func NewRandomRotation(maxDegrees float64) *RandomRotation {
+	if maxDegrees < 0 {
+		panic("回転角度の最大値は0以上を指定してください")
+	}
+	return &RandomRotation{maxDegrees: maxDegrees}
+}
+
+func (r *RandomRotation) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	theta := (rnd.Float64()*2 - 1) * r.maxDegrees * math.Pi / 180
+	cos, sin := math.Cos(theta), math.Sin(theta)
+	cx, cy := center(img)
+	// 変換後の画素から変換前の位置を求めるため、逆回転を行う
+	return remap(img, func(x int, y int) (float64, float64) {
+		px, py := float64(x)-cx, float64(y)-cy
+		return cos*px + sin*py + cx, -sin*px + cos*py + cy
+	})
+}
+
+// RandomScale : 画像の中心を基準にランダムな倍率で拡大・縮小する（はみ出した部分は0で埋める）
+type RandomScale struct {
+	min float64
+	max float64
+}
+
+// NewRandomScale : RandomScaleを取得
+// min, max : 倍率の最小値と最大値
+func NewRandomScale(min float64, max float64) *RandomScale {
+	if min <= 0 || min > max {
+		panic("倍率は0より大きく、最小値が最大値以下となるように指定してください")
+	}
+	return &RandomScale{min: min, max: max}
+}
+
+func (s *RandomScale) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	scale := s.min + rnd.Float64()*(s.max-s.min)
+	cx, cy := center(img)
+	return remap(img, func(x int, y int) (float64, float64) {
+		return (float64(x)-cx)/scale + cx, (float64(y)-cy)/scale + cy
+	})
+}
+
+// RandomHorizontalFlip : 指定した確率で左右反転する
+type RandomHorizontalFlip struct {
+	probability float64
+}
+
+// NewRandomHorizontalFlip : RandomHorizontalFlipを取得
+// probability : 反転する確率
+func NewRandomHorizontalFlip(probability float64) *RandomHorizontalFlip {
+	if probability < 0 || probability > 1 {
+		panic("確率は0以上1以下を指定してください")
+	}
+	return &RandomHorizontalFlip{probability: probability}
+}
+
+func (f *RandomHorizontalFlip) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	// 反転しない場合も同じ数の乱数を消費し、他のデータ拡張の乱数列が変わらないようにする
+	flip := rnd.Float64() < f.probability
+	_, _, w := dims(img)
+	return remap(img, func(x int, y int) (float64, float64) {
+		if flip {
+			return float64(w - 1 - x), float64(y)
+		}
+		return float64(x), float64(y)
+	})
+}
+
+// RandomCrop : 周囲を0でpaddingした画像から、元のサイズの領域をランダムな位置で切り出す
+type RandomCrop struct {
+	padding int
+}
+
+// NewRandomCrop : RandomCropを取得
+// padding : 上下左右に追加する画素数
+func NewRandomCrop(padding int) *RandomCrop {
+	if padding < 0 {
+		panic("paddingは0以上を指定してください")
+	}
+	return &RandomCrop{padding: padding}
+}
+
+func (c *RandomCrop) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	// paddingした画像の(ox, oy)から切り出すことは、元の画像を(padding-ox, padding-oy)移動することと同じ
+	ox := rnd.Intn(2*c.padding + 1)
+	oy := rnd.Intn(2*c.padding + 1)
+	return remap(img, func(x int, y int) (float64, float64) {
+		return float64(x + ox - c.padding), float64(y + oy - c.padding)
+	})
+}
+
+// Cutout : ランダムな位置の正方形の領域を0で塗りつぶす
+// 領域の中心は画像内のランダムな位置とし、画像からはみ出した部分は無視する
+type Cutout struct {
+	size int
+}
+
+// NewCutout : Cutoutを取得
+// size : 塗りつぶす正方形の一辺の画素数
+func NewCutout(size int) *Cutout {
+	if size <= 0 {
+		panic("sizeは1以上を指定してください")
+	}
+	return &Cutout{size: size}
+}
+
+func (c *Cutout) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	ch, h, w := dims(img)
+	cx, cy := rnd.Intn(w), rnd.Intn(h)
+	x0, y0 := cx-c.size/2, cy-c.size/2
+	dst := neuralNetwork.NewImageWithChannel(flatten(img), w, h, ch)
+	for _, channel := range dst {
+		for y := maxInt(y0, 0); y < minInt(y0+c.size, h); y++ {
+			for x := maxInt(x0, 0); x < minInt(x0+c.size, w); x++ {
+				channel[y][x] = 0
+			}
+		}
+	}
+	return dst
+}
+
+// ElasticDistortion : 画素毎のランダムな変位をガウシアンフィルタで平滑化し、画像を弾性変形する
+// Simard et al., "Best Practices for Convolutional Neural Networks Applied to Visual Document Analysis"
+type ElasticDistortion struct {
+	alpha float64
+	sigma float64
+}
+
+// NewElasticDistortion : ElasticDistortionを取得
+// alpha : 変位の大きさ, sigma : 平滑化に利用するガウシアンフィルタの標準偏差
+func NewElasticDistortion(alpha float64, sigma float64) *ElasticDistortion {
+	if alpha < 0 || sigma <= 0 {
+		panic("alphaは0以上、sigmaは0より大きい値を指定してください")
+	}
+	return &ElasticDistortion{alpha: alpha, sigma: sigma}
+}
+
+func (e *ElasticDistortion) Apply(img neuralNetwork.ImageWithChannel, rnd *rand.Rand) neuralNetwork.ImageWithChannel {
+	_, h, w := dims(img)
+	dx := make([]float64, w*h)
+	dy := make([]float64, w*h)
+	for i := range dx {
+		dx[i] = rnd.Float64()*2 - 1
+		dy[i] = rnd.Float64()*2 - 1
+	}
+	kernel := gaussianKernel(e.sigma)
+	dx = gaussianBlur(dx, w, h, kernel)
+	dy = gaussianBlur(dy, w, h, kernel)
+	return remap(img, func(x int, y int) (float64, float64) {
+		return float64(x) + e.alpha*dx[y*w+x], float64(y) + e.alpha*dy[y*w+x]
+	})
+}
+
+// gaussianKernel : 半径を3σとした、合計が1の1次元のガウシアンフィルタを作成
+func gaussianKernel(sigma float64) []float64 {
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
+
+// gaussianBlur : 横方向・縦方向の順に1次元のガウシアンフィルタを適用する（範囲外は0とする）
+func gaussianBlur(src []float64, w int, h int, kernel []float64) []float64 {
+	radius := len(kernel) / 2
+	tmp := make([]float64, w*h)
+	dst := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for k, weight := range kernel {
+				if sx := x + k - radius; sx >= 0 && sx < w {
+					tmp[y*w+x] += src[y*w+sx] * weight
+				}
+			}
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for k, weight := range kernel {
+				if sy := y + k - radius; sy >= 0 && sy < h {
+					dst[y*w+x] += tmp[sy*w+x] * weight
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// center : 画像の中心の位置を取得
+func center(img neuralNetwork.ImageWithChannel) (cx float64, cy float64) {
+	_, h, w := dims(img)
+	return float64(w-1) / 2, float64(h-1) / 2
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
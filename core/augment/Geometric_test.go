@@ -0,0 +1,153 @@
+package augment
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGeometric(t *testing.T) {
+	Convey("Given : 2チャネル・5*6の画像が与えられた時", t, func() {
+		img := sequenceImage(2, 5, 6)
+		rnd := rand.New(util.NewSource(7))
+
+		Convey("Then : 変化量が0のデータ拡張は元の画像のままとなること", func() {
+			So(NewRandomShift(0, 0).Apply(img, rnd), ShouldResemble, img)
+			So(NewRandomRotation(0).Apply(img, rnd), ShouldResemble, img)
+			So(NewRandomScale(1, 1).Apply(img, rnd), ShouldResemble, img)
+			So(NewRandomHorizontalFlip(0).Apply(img, rnd), ShouldResemble, img)
+			So(NewRandomCrop(0).Apply(img, rnd), ShouldResemble, img)
+			So(NewElasticDistortion(0, 1).Apply(img, rnd), ShouldResemble, img)
+		})
+
+		Convey("When : 平行移動を繰り返し適用する", func() {
+			Convey("Then : 指定した範囲内の移動量で、全チャネルが同じだけ移動すること", func() {
+				for i := 0; i < 20; i++ {
+					dx, dy, ok := findShift(img, NewRandomShift(2, 1).Apply(img, rnd))
+					So(ok, ShouldBeTrue)
+					So(dx, ShouldBeBetweenOrEqual, -2, 2)
+					So(dy, ShouldBeBetweenOrEqual, -1, 1)
+				}
+			})
+		})
+
+		Convey("When : paddingした画像からランダムに切り出す", func() {
+			Convey("Then : padding以内の平行移動と同じ結果となること", func() {
+				for i := 0; i < 20; i++ {
+					dx, dy, ok := findShift(img, NewRandomCrop(1).Apply(img, rnd))
+					So(ok, ShouldBeTrue)
+					So(dx, ShouldBeBetweenOrEqual, -1, 1)
+					So(dy, ShouldBeBetweenOrEqual, -1, 1)
+				}
+			})
+		})
+
+		Convey("When : 確率1で左右反転する", func() {
+			flipped := NewRandomHorizontalFlip(1).Apply(img, rnd)
+			Convey("Then : 各行が左右反転すること", func() {
+				So(flipped[1][2], ShouldResemble, []float64{47, 46, 45, 44, 43, 42})
+			})
+		})
+
+		Convey("When : 180度固定で回転する", func() {
+			square := sequenceImage(1, 3, 3)
+			rotated := NewRandomRotation(360).Apply(square, rand.New(constantSource{}))
+			Convey("Then : 上下左右が反転すること", func() {
+				expected := []float64{8, 7, 6, 5, 4, 3, 2, 1, 0}
+				actual := flatten(rotated)
+				for i := range expected {
+					So(actual[i], ShouldAlmostEqual, expected[i], 1e-9)
+				}
+			})
+		})
+
+		Convey("When : 2倍に拡大する", func() {
+			square := sequenceImage(1, 3, 3)
+			scaled := NewRandomScale(2, 2).Apply(square, rnd)
+			Convey("Then : 中心の画素は変わらず、周囲は中心との間の値となること", func() {
+				So(scaled[0][1][1], ShouldEqual, 4)
+				So(scaled[0][0][0], ShouldEqual, 2)
+				So(scaled[0][2][2], ShouldEqual, 6)
+			})
+		})
+
+		Convey("When : Cutoutを適用する", func() {
+			ones := neuralNetwork.NewImageWithChannel(util.CreateFloatArrayByStep(2*5*6, 1, 0), 6, 5, 2)
+			cut := NewCutout(2).Apply(ones, rnd)
+			Convey("Then : 全チャネルで同じ最大2*2の領域のみ0になること", func() {
+				zeros := 0
+				for y := 0; y < 5; y++ {
+					for x := 0; x < 6; x++ {
+						So(cut[0][y][x], ShouldEqual, cut[1][y][x])
+						if cut[0][y][x] == 0 {
+							zeros++
+						} else {
+							So(cut[0][y][x], ShouldEqual, 1)
+						}
+					}
+				}
+				So(zeros, ShouldBeBetweenOrEqual, 1, 4)
+				So(ones[0][0][0], ShouldEqual, 1)
+			})
+		})
+
+		Convey("When : 同じシードで弾性変形を適用する", func() {
+			e := NewElasticDistortion(3, 1)
+			a := e.Apply(img, rand.New(util.NewSource(3)))
+			b := e.Apply(img, rand.New(util.NewSource(3)))
+			Convey("Then : 同じ結果となり、元の画像から変形していること", func() {
+				So(a, ShouldResemble, b)
+				So(a, ShouldNotResemble, img)
+			})
+		})
+
+		Convey("Then : 不正な引数の場合はpanicが発生すること", func() {
+			So(func() { NewRandomShift(-1, 0) }, ShouldPanic)
+			So(func() { NewRandomRotation(-1) }, ShouldPanic)
+			So(func() { NewRandomScale(0, 1) }, ShouldPanic)
+			So(func() { NewRandomScale(2, 1) }, ShouldPanic)
+			So(func() { NewRandomHorizontalFlip(1.5) }, ShouldPanic)
+			So(func() { NewRandomCrop(-1) }, ShouldPanic)
+			So(func() { NewCutout(0) }, ShouldPanic)
+			So(func() { NewElasticDistortion(1, 0) }, ShouldPanic)
+		})
+	})
+}
+
+// findShift : dstがsrcを(dx, dy)平行移動し、はみ出した部分を0で埋めた画像となる移動量を探す
+func findShift(src neuralNetwork.ImageWithChannel, dst neuralNetwork.ImageWithChannel) (dx int, dy int, ok bool) {
+	_, h, w := dims(src)
+	for dy := -h + 1; dy < h; dy++ {
+		for dx := -w + 1; dx < w; dx++ {
+			shifted := remap(src, func(x int, y int) (float64, float64) {
+				return float64(x - dx), float64(y - dy)
+			})
+			if flattenEqual(shifted, dst) {
+				return dx, dy, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func flattenEqual(a neuralNetwork.ImageWithChannel, b neuralNetwork.ImageWithChannel) bool {
+	fa, fb := flatten(a), flatten(b)
+	for i := range fa {
+		if fa[i] != fb[i] {
+			return false
+		}
+	}
+	return len(fa) == len(fb)
+}
+
+// constantSource : Float64が常に0.75を返す乱数のソース（最大360度の回転で角度を180度に固定する）
+type constantSource struct{}
+
+func (constantSource) Int63() int64 {
+	return 3 << 61
+}
+
+func (constantSource) Seed(int64) {}
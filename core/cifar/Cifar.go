@@ -0,0 +1,315 @@
+package cifar
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// Width : 画像の幅
+	Width = 32
+	// Height : 画像の高さ
+	Height = 32
+	// Channel : 画像のチャネル数（R, G, B）
+	Channel = 3
+	// imageSize : 1枚の画像のbyte数
+	imageSize = Channel * Height * Width
+)
+
+// Format : CIFARのバイナリ形式の種類
+type Format int
+
+const (
+	// Cifar10 : 1byteのラベルと画像からなる形式
+	Cifar10 Format = iota
+	// Cifar100 : 1byteの大分類（coarse）のラベル、1byteの小分類（fine）のラベルと画像からなる形式
+	Cifar100
+)
+
+// labelSize : 1件のデータのラベル部分のbyte数
+func (f Format) labelSize() int {
+	if f == Cifar100 {
+		return 2
+	}
+	return 1
+}
+
+// Cifar10ClassNames : CIFAR-10のクラス名
+var Cifar10ClassNames = []string{
+	"airplane", "automobile", "bird", "cat", "deer", "dog", "frog", "horse", "ship", "truck",
+}
+
+// Cifar100ClassNames : CIFAR-100の小分類（fine）のクラス名
+var Cifar100ClassNames = []string{
+	"apple", "aquarium_fish", "baby", "bear", "beaver", "bed", "bee", "beetle", "bicycle", "bottle",
+	"bowl", "boy", "bridge", "bus", "butterfly", "camel", "can", "castle", "caterpillar", "cattle",
+	"chair", "chimpanzee", "clock", "cloud", "cockroach", "couch", "crab", "crocodile", "cup", "dinosaur",
+	"dolphin", "elephant", "flatfish", "forest", "fox", "girl", "hamster", "house", "kangaroo", "keyboard",
+	"lamp", "lawn_mower", "leopard", "lion", "lizard", "lobster", "man", "maple_tree", "motorcycle", "mountain",
+	"mouse", "mushroom", "oak_tree", "orange", "orchid", "otter", "palm_tree", "pear", "pickup_truck", "pine_tree",
+	"plain", "plate", "poppy", "porcupine", "possum", "rabbit", "raccoon", "ray", "road", "rocket",
+	"rose", "sea", "seal", "shark", "shrew", "skunk", "skyscraper", "snail", "snake", "spider",
+	"squirrel", "streetcar", "sunflower", "sweet_pepper", "table", "tank", "telephone", "television", "tiger", "tractor",
+	"train", "trout", "tulip", "turtle", "wardrobe", "whale", "willow_tree", "wolf", "woman", "worm",
+}
+
+// Cifar100CoarseClassNames : CIFAR-100の大分類（coarse）のクラス名
+var Cifar100CoarseClassNames = []string{
+	"aquatic_mammals", "fish", "flowers", "food_containers", "fruit_and_vegetables",
+	"household_electrical_devices", "household_furniture", "insects", "large_carnivores", "large_man-made_outdoor_things",
+	"large_natural_outdoor_scenes", "large_omnivores_and_herbivores", "medium_mammals", "non-insect_invertebrates", "people",
+	"reptiles", "small_mammals", "trees", "vehicles_1", "vehicles_2",
+}
+
+// DataSet : CIFARのデータセット
+// 画像は(チャネル, 高さ, 幅)の順に並べた0〜255の値として扱い、正解データはクラス番号をone-hot形式に変換する
+type DataSet struct {
+	images       []byte
+	labels       []int
+	coarseLabels []int // CIFAR-10の場合はnil
+	classNames   []string
+	coarseNames  []string
+	useCoarse    bool
+}
+
+// Read : CIFARのバイナリ形式のデータを読み込む
+// classNames, coarseClassNames : クラス名（Cifar10の場合、coarseClassNamesは利用しない）
+func Read(r io.Reader, format Format, classNames []string, coarseClassNames []string) (*DataSet, error) {
+	ds := DataSet{classNames: classNames}
+	if format == Cifar100 {
+		ds.coarseLabels = make([]int, 0)
+		ds.coarseNames = coarseClassNames
+	}
+	if err := ds.read(r, format); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
+
+// ReadFiles : CIFARのバイナリ形式の複数のファイルを読み込み、1つのデータセットにまとめる
+func ReadFiles(format Format, classNames []string, coarseClassNames []string, filePaths ...string) (*DataSet, error) {
+	if len(filePaths) == 0 {
+		return nil, errors.New("ファイルが指定されていません")
+	}
+	ds := DataSet{classNames: classNames}
+	if format == Cifar100 {
+		ds.coarseLabels = make([]int, 0)
+		ds.coarseNames = coarseClassNames
+	}
+	for _, filePath := range filePaths {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		err = ds.read(f, format)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s : %v", filePath, err)
+		}
+	}
+	return &ds, nil
+}
+
+// read : 1件ずつデータを読み込んで追加する
+func (ds *DataSet) read(r io.Reader, format Format) error {
+	br := bufio.NewReader(r)
+	record := make([]byte, format.labelSize()+imageSize)
+	for {
+		n, err := io.ReadFull(br, record)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%d件目のデータが不足しています（%d bytes）", ds.Count()+1, n)
+		}
+
+		label := int(record[format.labelSize()-1])
+		if label >= len(ds.classNames) {
+			return fmt.Errorf("%d件目のラベル%dがクラス数%dの範囲外です", ds.Count()+1, label, len(ds.classNames))
+		}
+		if format == Cifar100 {
+			coarse := int(record[0])
+			if coarse >= len(ds.coarseNames) {
+				return fmt.Errorf("%d件目の大分類のラベル%dがクラス数%dの範囲外です", ds.Count()+1, coarse, len(ds.coarseNames))
+			}
+			ds.coarseLabels = append(ds.coarseLabels, coarse)
+		}
+		ds.labels = append(ds.labels, label)
+		ds.images = append(ds.images, record[format.labelSize():]...)
+	}
+}
+
+// LoadCifar10 : CIFAR-10のバイナリ版を展開したフォルダ（cifar-10-batches-bin）からデータセットを取得
+// 学習用データはdata_batch_1.bin〜data_batch_5.bin、テスト用データはtest_batch.binから読み込む
+// batches.meta.txtがあればクラス名として利用する
+func LoadCifar10(rootPath string) (trainSet *DataSet, testSet *DataSet, err error) {
+	classNames, err := readClassNames(path.Join(rootPath, "batches.meta.txt"), Cifar10ClassNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	trainPaths := make([]string, 0, 5)
+	for i := 1; i <= 5; i++ {
+		trainPaths = append(trainPaths, path.Join(rootPath, fmt.Sprintf("data_batch_%d.bin", i)))
+	}
+	trainSet, err = ReadFiles(Cifar10, classNames, nil, trainPaths...)
+	if err != nil {
+		return nil, nil, err
+	}
+	testSet, err = ReadFiles(Cifar10, classNames, nil, path.Join(rootPath, "test_batch.bin"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return trainSet, testSet, nil
+}
+
+// LoadCifar100 : CIFAR-100のバイナリ版を展開したフォルダ（cifar-100-binary）からデータセットを取得
+// 学習用データはtrain.bin、テスト用データはtest.binから読み込む
+// fine_label_names.txt, coarse_label_names.txtがあればクラス名として利用する
+func LoadCifar100(rootPath string) (trainSet *DataSet, testSet *DataSet, err error) {
+	classNames, err := readClassNames(path.Join(rootPath, "fine_label_names.txt"), Cifar100ClassNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	coarseNames, err := readClassNames(path.Join(rootPath, "coarse_label_names.txt"), Cifar100CoarseClassNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	trainSet, err = ReadFiles(Cifar100, classNames, coarseNames, path.Join(rootPath, "train.bin"))
+	if err != nil {
+		return nil, nil, err
+	}
+	testSet, err = ReadFiles(Cifar100, classNames, coarseNames, path.Join(rootPath, "test.bin"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return trainSet, testSet, nil
+}
+
+// readClassNames : 1行に1つのクラス名を記載したファイルを読み込む（ファイルが無い場合はdefaultNamesを返す）
+func readClassNames(filePath string, defaultNames []string) ([]string, error) {
+	if !util.Exists(filePath) {
+		return defaultNames, nil
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(defaultNames))
+	for _, line := range strings.Split(string(data), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (ds *DataSet) Count() int {
+	return len(ds.labels)
+}
+
+func (ds *DataSet) GetSample(index int) (x []float64, t []float64) {
+	x = ds.GetImageVector(index)
+	t = make([]float64, ds.GetClassCount())
+	t[ds.GetLabel(index)] = 1
+	return x, t
+}
+
+func (ds *DataSet) GetBatch(indexes []int) (x mat.Matrix, t mat.Matrix) {
+	bx := mat.NewDense(len(indexes), imageSize, nil)
+	bt := mat.NewDense(len(indexes), ds.GetClassCount(), nil)
+	for i, index := range indexes {
+		bx.SetRow(i, ds.GetImageVector(index))
+		bt.Set(i, ds.GetLabel(index), 1)
+	}
+	return bx, bt
+}
+
+// GetImageVector : 指定したインデックスの画像を(チャネル, 高さ, 幅)の順に並べた0〜255の値で取得
+func (ds *DataSet) GetImageVector(index int) []float64 {
+	if index < 0 || index >= ds.Count() {
+		panic(fmt.Sprintf("インデックス%dがデータ数%dの範囲外です", index, ds.Count()))
+	}
+	x := make([]float64, imageSize)
+	for i, v := range ds.images[index*imageSize : (index+1)*imageSize] {
+		x[i] = float64(v)
+	}
+	return x
+}
+
+// GetImagesWithChannel : 指定したインデックスの画像を、ImagesWithChannelの形式で取得
+func (ds *DataSet) GetImagesWithChannel(indexes []int) neuralNetwork.ImagesWithChannel {
+	input := make([]float64, 0, len(indexes)*imageSize)
+	for _, index := range indexes {
+		input = append(input, ds.GetImageVector(index)...)
+	}
+	return neuralNetwork.NewImagesWithChannel(input, Width, Height, Channel, len(indexes))
+}
+
+// ConvertMatrix : 全データを、(データ数, チャネル*高さ*幅)の入力と(データ数, クラス数)の正解データの行列で取得
+func (ds *DataSet) ConvertMatrix() (x mat.Matrix, t mat.Matrix) {
+	indexes := make([]int, ds.Count())
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return ds.GetBatch(indexes)
+}
+
+// GetLabel : 指定したインデックスのデータの、正解データとして利用するクラス番号を取得
+func (ds *DataSet) GetLabel(index int) int {
+	if ds.useCoarse {
+		return ds.coarseLabels[index]
+	}
+	return ds.labels[index]
+}
+
+// GetFineLabel : 指定したインデックスのデータの小分類（CIFAR-10ではクラス）のクラス番号を取得
+func (ds *DataSet) GetFineLabel(index int) int {
+	return ds.labels[index]
+}
+
+// GetCoarseLabel : 指定したインデックスのデータの大分類のクラス番号を取得（CIFAR-10の場合はpanicが発生する）
+func (ds *DataSet) GetCoarseLabel(index int) int {
+	if !ds.HasCoarseLabels() {
+		panic("大分類のラベルがありません")
+	}
+	return ds.coarseLabels[index]
+}
+
+// HasCoarseLabels : 大分類のラベルを持つ（CIFAR-100）かどうか
+func (ds *DataSet) HasCoarseLabels() bool {
+	return ds.coarseLabels != nil
+}
+
+// WithCoarseLabels : 大分類のラベルを正解データとして利用するデータセットを取得（画像・ラベルは共有する）
+// 大分類のラベルが無い場合はpanicが発生する
+func (ds *DataSet) WithCoarseLabels() *DataSet {
+	if !ds.HasCoarseLabels() {
+		panic("大分類のラベルがありません")
+	}
+	coarse := *ds
+	coarse.useCoarse = true
+	return &coarse
+}
+
+// GetClassCount : 正解データとして利用するクラス数を取得
+func (ds *DataSet) GetClassCount() int {
+	return len(ds.GetClassNames())
+}
+
+// GetClassNames : 正解データとして利用するクラス番号順のクラス名を取得
+func (ds *DataSet) GetClassNames() []string {
+	if ds.useCoarse {
+		return ds.coarseNames
+	}
+	return ds.classNames
+}
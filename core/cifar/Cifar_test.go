@@ -0,0 +1,179 @@
+package cifar
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRead(t *testing.T) {
+	Convey("Given : CIFAR-10形式の2件のデータが与えられた時", t, func() {
+		data := append(createRecord([]byte{3}, 0), createRecord([]byte{9}, 1)...)
+		ds, err := Read(bytes.NewReader(data), Cifar10, Cifar10ClassNames, nil)
+		Convey("Then : ラベルと画像が読み込めること", func() {
+			So(err, ShouldBeNil)
+			So(ds.Count(), ShouldEqual, 2)
+			So(ds.GetClassCount(), ShouldEqual, 10)
+			So(ds.GetLabel(1), ShouldEqual, 9)
+			So(ds.HasCoarseLabels(), ShouldBeFalse)
+			So(func() { ds.GetCoarseLabel(0) }, ShouldPanic)
+			So(func() { ds.WithCoarseLabels() }, ShouldPanic)
+		})
+		Convey("When : 1件のデータを取得する", func() {
+			x, label := ds.GetSample(1)
+			Convey("Then : (チャネル, 高さ, 幅)の順の画像とone-hot形式の正解データが取得できること", func() {
+				So(len(x), ShouldEqual, Channel*Height*Width)
+				So(x[0], ShouldEqual, pixel(1, 0, 0, 0))
+				So(x[Width+2], ShouldEqual, pixel(1, 0, 1, 2))
+				So(x[2*Height*Width+3*Width+4], ShouldEqual, pixel(1, 2, 3, 4))
+				So(label, ShouldResemble, []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 1})
+			})
+		})
+		Convey("When : ImagesWithChannelの形式で取得する", func() {
+			images := ds.GetImagesWithChannel([]int{1, 0})
+			Convey("Then : 指定した順に(画像, チャネル, 高さ, 幅)の配列で取得できること", func() {
+				So(len(images), ShouldEqual, 2)
+				So(len(images[0]), ShouldEqual, Channel)
+				So(len(images[0][0]), ShouldEqual, Height)
+				So(len(images[0][0][0]), ShouldEqual, Width)
+				So(images[0][2][3][4], ShouldEqual, pixel(1, 2, 3, 4))
+				So(images[1][1][5][6], ShouldEqual, pixel(0, 1, 5, 6))
+			})
+		})
+		Convey("When : 全データを行列で取得する", func() {
+			x, label := ds.ConvertMatrix()
+			Convey("Then : 各行が1件のデータとなる行列で取得できること", func() {
+				r, c := x.Dims()
+				So(r, ShouldEqual, 2)
+				So(c, ShouldEqual, Channel*Height*Width)
+				sx, st := ds.GetSample(0)
+				So(mat.Equal(x.(*mat.Dense).RowView(0), mat.NewVecDense(c, sx)), ShouldBeTrue)
+				So(mat.Equal(label.(*mat.Dense).RowView(0), mat.NewVecDense(10, st)), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given : CIFAR-100形式のデータが与えられた時", t, func() {
+		data := append(createRecord([]byte{4, 72}, 0), createRecord([]byte{19, 99}, 1)...)
+		ds, err := Read(bytes.NewReader(data), Cifar100, Cifar100ClassNames, Cifar100CoarseClassNames)
+		Convey("Then : 小分類と大分類のラベルが読み込めること", func() {
+			So(err, ShouldBeNil)
+			So(ds.HasCoarseLabels(), ShouldBeTrue)
+			So(ds.GetClassCount(), ShouldEqual, 100)
+			So(len(Cifar100CoarseClassNames), ShouldEqual, 20)
+			So(ds.GetLabel(0), ShouldEqual, 72)
+			So(ds.GetCoarseLabel(0), ShouldEqual, 4)
+			So(ds.GetClassNames()[ds.GetLabel(1)], ShouldEqual, "worm")
+			_, label := ds.GetSample(0)
+			So(len(label), ShouldEqual, 100)
+			So(label[72], ShouldEqual, 1)
+		})
+		Convey("When : 大分類のラベルを正解データとして利用する", func() {
+			coarse := ds.WithCoarseLabels()
+			x, label := coarse.GetBatch([]int{1})
+			Convey("Then : 大分類のone-hot形式の正解データが取得でき、元のデータセットは変わらないこと", func() {
+				So(coarse.GetClassCount(), ShouldEqual, 20)
+				So(coarse.GetLabel(1), ShouldEqual, 19)
+				So(coarse.GetFineLabel(1), ShouldEqual, 99)
+				_, c := label.Dims()
+				So(c, ShouldEqual, 20)
+				So(label.At(0, 19), ShouldEqual, 1)
+				So(x.At(0, 0), ShouldEqual, pixel(1, 0, 0, 0))
+				So(ds.GetClassCount(), ShouldEqual, 100)
+			})
+		})
+	})
+
+	Convey("Given : 不正なデータが与えられた時", t, func() {
+		Convey("Then : データが不足している場合はエラーとなること", func() {
+			data := createRecord([]byte{1}, 0)
+			_, err := Read(bytes.NewReader(data[:len(data)-1]), Cifar10, Cifar10ClassNames, nil)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Then : ラベルがクラス数の範囲外の場合はエラーとなること", func() {
+			_, err := Read(bytes.NewReader(createRecord([]byte{10}, 0)), Cifar10, Cifar10ClassNames, nil)
+			So(err, ShouldNotBeNil)
+			_, err = Read(bytes.NewReader(createRecord([]byte{20, 0}, 0)), Cifar100, Cifar100ClassNames, Cifar100CoarseClassNames)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given : CIFAR-10のバイナリ版を展開したフォルダが与えられた時", t, func() {
+		rootPath, _ := ioutil.TempDir("", "cifar")
+		defer os.RemoveAll(rootPath)
+		for i := 1; i <= 5; i++ {
+			data := append(createRecord([]byte{byte(i)}, i), createRecord([]byte{0}, i)...)
+			ioutil.WriteFile(path.Join(rootPath, fmt.Sprintf("data_batch_%d.bin", i)), data, 0644)
+		}
+		ioutil.WriteFile(path.Join(rootPath, "test_batch.bin"), createRecord([]byte{7}, 9), 0644)
+		Convey("When : データセットを読み込む", func() {
+			train, test, err := LoadCifar10(rootPath)
+			Convey("Then : 全ての学習用ファイルとテスト用ファイルが読み込めること", func() {
+				So(err, ShouldBeNil)
+				So(train.Count(), ShouldEqual, 10)
+				So(train.GetLabel(8), ShouldEqual, 5)
+				So(test.Count(), ShouldEqual, 1)
+				So(test.GetClassNames()[test.GetLabel(0)], ShouldEqual, "horse")
+			})
+		})
+		Convey("When : クラス名のファイルがあるフォルダから読み込む", func() {
+			names := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n\n"
+			ioutil.WriteFile(path.Join(rootPath, "batches.meta.txt"), []byte(names), 0644)
+			_, test, err := LoadCifar10(rootPath)
+			Convey("Then : ファイルのクラス名が利用されること", func() {
+				So(err, ShouldBeNil)
+				So(test.GetClassNames(), ShouldResemble, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"})
+			})
+		})
+		Convey("When : ファイルが不足しているフォルダから読み込む", func() {
+			os.Remove(path.Join(rootPath, "data_batch_3.bin"))
+			_, _, err := LoadCifar10(rootPath)
+			Convey("Then : エラーとなること", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given : CIFAR-100のバイナリ版を展開したフォルダが与えられた時", t, func() {
+		rootPath, _ := ioutil.TempDir("", "cifar")
+		defer os.RemoveAll(rootPath)
+		ioutil.WriteFile(path.Join(rootPath, "train.bin"), createRecord([]byte{11, 3}, 0), 0644)
+		ioutil.WriteFile(path.Join(rootPath, "test.bin"), createRecord([]byte{2, 54}, 0), 0644)
+		Convey("When : データセットを読み込む", func() {
+			train, test, err := LoadCifar100(rootPath)
+			Convey("Then : デフォルトのクラス名で読み込めること", func() {
+				So(err, ShouldBeNil)
+				So(train.GetClassNames()[train.GetLabel(0)], ShouldEqual, "bear")
+				coarse := test.WithCoarseLabels()
+				So(coarse.GetClassNames()[coarse.GetLabel(0)], ShouldEqual, "flowers")
+				So(test.GetClassNames()[test.GetLabel(0)], ShouldEqual, "orchid")
+			})
+		})
+	})
+}
+
+// createRecord : ラベルと、pixelの値を持つ画像からなる1件のデータを作成
+func createRecord(labels []byte, seed int) []byte {
+	record := append([]byte{}, labels...)
+	for c := 0; c < Channel; c++ {
+		for y := 0; y < Height; y++ {
+			for x := 0; x < Width; x++ {
+				record = append(record, byte(pixel(seed, c, y, x)))
+			}
+		}
+	}
+	return record
+}
+
+// pixel : テスト用の画像の画素値
+func pixel(seed int, c int, y int, x int) float64 {
+	return float64((seed*31 + c*101 + y*7 + x) % 256)
+}
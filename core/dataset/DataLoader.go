@@ -0,0 +1,241 @@
+package dataset
+
+import (
+	"math/rand"
+
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// DataLoader : データセットからepoch毎にミニバッチを作成する
+// epoch毎にインデックスを重複なくシャッフルするため、1epochで全てのデータを1回ずつ利用する
+type DataLoader struct {
+	ds        Dataset
+	batchSize int
+	shuffle   bool
+	dropLast  bool
+	prefetch  bool
+	rnd       *rand.Rand
+	source    *util.SplitMix64Source // 乱数の状態の保存・復元用（WithDataLoaderRandで指定した場合はnil）
+}
+
+// DataLoaderOption : DataLoaderのオプション
+type DataLoaderOption func(*DataLoader)
+
+// NewDataLoader : DataLoaderを取得
+// ds : データセット, batchSize : バッチサイズ
+// デフォルトではepoch毎にシャッフルし、最後のバッチはバッチサイズに満たない場合もそのまま利用する
+// 初期化時にオプション指定が可能
+func NewDataLoader(ds Dataset, batchSize int, options ...DataLoaderOption) *DataLoader {
+	if batchSize <= 0 {
+		panic("batchSizeは1以上を指定してください")
+	}
+	dl := DataLoader{ds: ds, batchSize: batchSize, shuffle: true}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&dl)
+	}
+	// シャッフルしない場合は、ライブラリ全体の乱数に影響を与えないように乱数生成器を作成しない
+	if dl.rnd == nil && dl.shuffle {
+		dl.rnd, dl.source = util.NewRandWithSource()
+	}
+	return &dl
+}
+
+// WithDataLoaderNoShuffle : シャッフルせずにインデックス順にミニバッチを作成するオプションを取得
+func WithDataLoaderNoShuffle() DataLoaderOption {
+	return func(dl *DataLoader) {
+		dl.shuffle = false
+	}
+}
+
+// WithDataLoaderDropLast : バッチサイズに満たない最後のバッチを利用しないオプションを取得
+func WithDataLoaderDropLast() DataLoaderOption {
+	return func(dl *DataLoader) {
+		dl.dropLast = true
+	}
+}
+
+// WithDataLoaderPrefetch : 次のミニバッチを別のgoroutineで事前に作成するオプションを取得
+func WithDataLoaderPrefetch() DataLoaderOption {
+	return func(dl *DataLoader) {
+		dl.prefetch = true
+	}
+}
+
+// WithDataLoaderRand : シャッフルに利用する乱数生成器指定のオプションを取得
+func WithDataLoaderRand(rnd *rand.Rand) DataLoaderOption {
+	return func(dl *DataLoader) {
+		dl.rnd = rnd
+		dl.source = nil
+	}
+}
+
+// GetDataset : データセットを取得
+func (dl *DataLoader) GetDataset() Dataset {
+	return dl.ds
+}
+
+// GetBatchSize : バッチサイズを取得
+func (dl *DataLoader) GetBatchSize() int {
+	return dl.batchSize
+}
+
+// BatchCount : 1epochあたりのバッチ数を取得
+func (dl *DataLoader) BatchCount() int {
+	if dl.dropLast {
+		return dl.ds.Count() / dl.batchSize
+	}
+	return (dl.ds.Count() + dl.batchSize - 1) / dl.batchSize
+}
+
+// SampleCount : 1epochで利用するデータ数を取得
+func (dl *DataLoader) SampleCount() int {
+	if dl.dropLast {
+		return dl.BatchCount() * dl.batchSize
+	}
+	return dl.ds.Count()
+}
+
+// GetRandomState : シャッフルに利用する乱数の状態を取得（WithDataLoaderRandで指定した場合、シャッフルしない場合はfalse）
+func (dl *DataLoader) GetRandomState() (uint64, bool) {
+	if dl.source == nil {
+		return 0, false
+	}
+	return dl.source.GetState(), true
+}
+
+// SetRandomState : シャッフルに利用する乱数の状態を設定
+// WithDataLoaderRandで乱数生成器を指定していた場合は、SplitMix64Sourceを利用した乱数生成器に置き換える
+func (dl *DataLoader) SetRandomState(state uint64) {
+	if dl.source == nil {
+		dl.source = util.NewSource(0)
+		dl.rnd = rand.New(dl.source)
+	}
+	dl.source.SetState(state)
+}
+
+// Iterator : 1epoch分のミニバッチを順に取得するBatchIteratorを取得
+// 呼び出す毎にインデックスをシャッフルし直す
+func (dl *DataLoader) Iterator() *BatchIterator {
+	count := dl.ds.Count()
+	order := make([]int, count)
+	for i := range order {
+		order[i] = i
+	}
+	if dl.shuffle {
+		dl.rnd.Shuffle(count, func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+
+	batches := make([][]int, 0, dl.BatchCount())
+	for start := 0; start < count; start += dl.batchSize {
+		end := start + dl.batchSize
+		if end > count {
+			if dl.dropLast {
+				break
+			}
+			end = count
+		}
+		batches = append(batches, order[start:end])
+	}
+
+	it := BatchIterator{ds: dl.ds, batches: batches, pos: -1}
+	if dl.prefetch {
+		it.startPrefetch()
+	}
+	return &it
+}
+
+// Batch : 1つのミニバッチ
+type Batch struct {
+	X       mat.Matrix
+	T       mat.Matrix
+	Indexes []int // ミニバッチに含まれるデータのインデックス
+}
+
+// BatchIterator : 1epoch分のミニバッチを順に取得する
+// Nextがtrueを返す間、Batchで現在のミニバッチを取得する
+type BatchIterator struct {
+	ds      Dataset
+	batches [][]int
+	pos     int
+	current Batch
+
+	// 事前作成を行う場合のみ利用する
+	prefetched chan prefetchResult
+	done       chan struct{}
+}
+
+// prefetchResult : 事前に作成したミニバッチ（作成中にpanicが発生した場合はその値）
+type prefetchResult struct {
+	batch     Batch
+	recovered interface{}
+}
+
+// Next : 次のミニバッチに進む. 全てのミニバッチを取得済みの場合はfalseを返す
+// 事前作成時にデータセットでpanicが発生した場合は、呼び出し元でpanicを発生させる
+func (it *BatchIterator) Next() bool {
+	if it.pos+1 >= len(it.batches) {
+		it.Close()
+		return false
+	}
+	it.pos++
+
+	if it.prefetched == nil {
+		x, t := GetBatch(it.ds, it.batches[it.pos])
+		it.current = Batch{X: x, T: t, Indexes: it.batches[it.pos]}
+		return true
+	}
+	result := <-it.prefetched
+	if result.recovered != nil {
+		it.Close()
+		panic(result.recovered)
+	}
+	it.current = result.batch
+	return true
+}
+
+// Batch : 現在のミニバッチを取得
+func (it *BatchIterator) Batch() Batch {
+	return it.current
+}
+
+// Close : 途中で取得を終える（事前作成を行っている場合はgoroutineを終了させる）
+// 以降のNextはfalseを返す
+func (it *BatchIterator) Close() {
+	it.pos = len(it.batches)
+	if it.done != nil {
+		close(it.done)
+		it.done = nil
+	}
+}
+
+// startPrefetch : 別のgoroutineで、取得される前のミニバッチを順に作成する
+func (it *BatchIterator) startPrefetch() {
+	it.prefetched = make(chan prefetchResult, 1)
+	it.done = make(chan struct{})
+	go func(done chan struct{}) {
+		for _, indexes := range it.batches {
+			result := prefetchResult{}
+			func() {
+				defer func() {
+					result.recovered = recover()
+				}()
+				x, t := GetBatch(it.ds, indexes)
+				result.batch = Batch{X: x, T: t, Indexes: indexes}
+			}()
+
+			select {
+			case it.prefetched <- result:
+			case <-done:
+				return
+			}
+			if result.recovered != nil {
+				return
+			}
+		}
+	}(it.done)
+}
@@ -0,0 +1,130 @@
+package dataset
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDataLoader(t *testing.T) {
+	Convey("Given : 10件のデータセットが与えられた時", t, func() {
+		util.SetSeed(1)
+		ds := newSequenceDataset(10)
+
+		Convey("When : バッチサイズ3でシャッフルしながら1epoch分のミニバッチを取得する", func() {
+			loader := NewDataLoader(ds, 3)
+			batches := collectIndexes(loader)
+			Convey("Then : 4バッチに分割され、最後のバッチは1件であること", func() {
+				So(loader.BatchCount(), ShouldEqual, 4)
+				So(loader.SampleCount(), ShouldEqual, 10)
+				So(len(batches), ShouldEqual, 4)
+				So(len(batches[3]), ShouldEqual, 1)
+			})
+			Convey("Then : 全てのデータを重複なく1回ずつ利用すること", func() {
+				So(flatten(batches), ShouldResemble, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+			})
+			Convey("Then : 次のepochではシャッフルし直されること", func() {
+				next := collectIndexes(loader)
+				So(flatten(next), ShouldResemble, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+				So(next, ShouldNotResemble, batches)
+			})
+		})
+
+		Convey("When : ミニバッチの行列を取得する", func() {
+			it := NewDataLoader(ds, 4).Iterator()
+			it.Next()
+			b := it.Batch()
+			Convey("Then : インデックスに対応するデータが取得できること", func() {
+				for i, index := range b.Indexes {
+					So(b.X.At(i, 1), ShouldEqual, index*2)
+					So(b.T.At(i, 0), ShouldEqual, index)
+				}
+			})
+		})
+
+		Convey("When : シャッフルせずに取得する", func() {
+			batches := collectIndexes(NewDataLoader(ds, 4, WithDataLoaderNoShuffle()))
+			Convey("Then : インデックス順にミニバッチが作成されること", func() {
+				So(batches, ShouldResemble, [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}})
+			})
+		})
+
+		Convey("When : バッチサイズに満たない最後のバッチを利用しない", func() {
+			loader := NewDataLoader(ds, 3, WithDataLoaderDropLast())
+			batches := collectIndexes(loader)
+			Convey("Then : 3件ずつの3バッチのみ取得されること", func() {
+				So(loader.BatchCount(), ShouldEqual, 3)
+				So(loader.SampleCount(), ShouldEqual, 9)
+				So(len(batches), ShouldEqual, 3)
+				for _, indexes := range batches {
+					So(len(indexes), ShouldEqual, 3)
+				}
+			})
+		})
+
+		Convey("When : 同じ乱数生成器の状態から、事前作成あり・なしで取得する", func() {
+			batches := collectIndexes(NewDataLoader(ds, 3, WithDataLoaderRand(rand.New(util.NewSource(7)))))
+			prefetched := collectIndexes(NewDataLoader(ds, 3, WithDataLoaderRand(rand.New(util.NewSource(7))), WithDataLoaderPrefetch()))
+			Convey("Then : 同じミニバッチが取得されること", func() {
+				So(prefetched, ShouldResemble, batches)
+			})
+		})
+
+		Convey("When : 事前作成を行い、途中で取得を終える", func() {
+			it := NewDataLoader(ds, 2, WithDataLoaderPrefetch()).Iterator()
+			it.Next()
+			it.Close()
+			Convey("Then : 以降のミニバッチは取得されないこと", func() {
+				So(it.Next(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When : 事前作成中にデータセットでpanicが発生する", func() {
+			ds.panicAt = 5
+			it := NewDataLoader(ds, 2, WithDataLoaderNoShuffle(), WithDataLoaderPrefetch()).Iterator()
+			Convey("Then : 該当するミニバッチの取得時にpanicが発生すること", func() {
+				So(it.Next(), ShouldBeTrue)
+				So(it.Next(), ShouldBeTrue)
+				So(func() { it.Next() }, ShouldPanic)
+			})
+		})
+
+		Convey("When : 乱数の状態を取得し、別のDataLoaderに設定する", func() {
+			loader := NewDataLoader(ds, 3)
+			state, ok := loader.GetRandomState()
+			other := NewDataLoader(ds, 3)
+			other.SetRandomState(state)
+			Convey("Then : 同じ順序でミニバッチが作成されること", func() {
+				So(ok, ShouldBeTrue)
+				So(collectIndexes(other), ShouldResemble, collectIndexes(loader))
+			})
+		})
+
+		Convey("Then : バッチサイズが0以下の場合はpanicが発生すること", func() {
+			So(func() { NewDataLoader(ds, 0) }, ShouldPanic)
+		})
+	})
+}
+
+// collectIndexes : 1epoch分のミニバッチのインデックスを取得
+func collectIndexes(loader *DataLoader) [][]int {
+	batches := make([][]int, 0)
+	it := loader.Iterator()
+	for it.Next() {
+		batches = append(batches, it.Batch().Indexes)
+	}
+	return batches
+}
+
+// flatten : 全てのミニバッチのインデックスを昇順に並べて取得
+func flatten(batches [][]int) []int {
+	indexes := make([]int, 0)
+	for _, b := range batches {
+		indexes = append(indexes, b...)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
@@ -0,0 +1,118 @@
+package dataset
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Dataset : 学習・評価に利用するデータセットのIF
+type Dataset interface {
+	// Count : データ数を取得
+	Count() int
+	// GetSample : 指定したインデックスの1件のデータを、入力と正解データのスライスで取得
+	GetSample(index int) (x []float64, t []float64)
+}
+
+// BatchDataset : 複数件のデータをまとめて取得できるデータセットのIF
+// DataLoaderは、データセットがBatchDatasetを実装していればGetBatchを利用してミニバッチを作成する
+type BatchDataset interface {
+	Dataset
+	// GetBatch : 指定したインデックスのデータを、(データ数, 入力の要素数)の入力と(データ数, 正解の要素数)の正解データの行列で取得
+	GetBatch(indexes []int) (x mat.Matrix, t mat.Matrix)
+}
+
+// WrapperDataset : 元のデータセットに処理を加えたデータセット（transform.Datasetなど）のIF
+type WrapperDataset interface {
+	Dataset
+	// GetDataset : 元のデータセットを取得
+	GetDataset() Dataset
+}
+
+// RandomStateDataset : データの取得時に乱数を利用し、その状態を保存・復元できるデータセット（augment.Datasetなど）のIF
+type RandomStateDataset interface {
+	Dataset
+	// GetRandomState : 乱数の状態を取得
+	GetRandomState() uint64
+	// SetRandomState : GetRandomStateで取得した乱数の状態を設定
+	SetRandomState(state uint64)
+}
+
+// FindRandomStateDataset : ds自身、またはWrapperDatasetの元のデータセットを順にたどり、最初に見つかったRandomStateDatasetを取得
+// 見つからない場合はfalseを返す
+func FindRandomStateDataset(ds Dataset) (RandomStateDataset, bool) {
+	for ds != nil {
+		if randomDataset, ok := ds.(RandomStateDataset); ok {
+			return randomDataset, true
+		}
+		wrapper, ok := ds.(WrapperDataset)
+		if !ok {
+			break
+		}
+		ds = wrapper.GetDataset()
+	}
+	return nil, false
+}
+
+// GetBatch : 指定したインデックスのデータを、入力と正解データの行列で取得
+// BatchDatasetを実装していない場合は、GetSampleで1件ずつ取得して行列にまとめる
+func GetBatch(ds Dataset, indexes []int) (x mat.Matrix, t mat.Matrix) {
+	if batchDataset, ok := ds.(BatchDataset); ok {
+		return batchDataset.GetBatch(indexes)
+	}
+	if len(indexes) == 0 {
+		panic("データのインデックスが指定されていません")
+	}
+
+	var bx, bt *mat.Dense
+	for i, index := range indexes {
+		sx, st := ds.GetSample(index)
+		if bx == nil {
+			bx = mat.NewDense(len(indexes), len(sx), nil)
+			bt = mat.NewDense(len(indexes), len(st), nil)
+		}
+		bx.SetRow(i, sx)
+		bt.SetRow(i, st)
+	}
+	return bx, bt
+}
+
+// MatrixDataset : 入力と正解データの行列をそのままデータセットとして扱う
+type MatrixDataset struct {
+	x *mat.Dense
+	t *mat.Dense
+}
+
+// NewMatrixDataset : 入力xと正解データtの行列からデータセットを取得
+// 各行を1つのデータとして扱う
+func NewMatrixDataset(x mat.Matrix, t mat.Matrix) *MatrixDataset {
+	xr, _ := x.Dims()
+	tr, _ := t.Dims()
+	if xr != tr {
+		panic(fmt.Sprintf("入力のデータ数%dと正解データのデータ数%dがマッチしてません", xr, tr))
+	}
+	return &MatrixDataset{x: mat.DenseCopyOf(x), t: mat.DenseCopyOf(t)}
+}
+
+func (ds *MatrixDataset) Count() int {
+	r, _ := ds.x.Dims()
+	return r
+}
+
+func (ds *MatrixDataset) GetSample(index int) (x []float64, t []float64) {
+	x = append([]float64{}, ds.x.RawRowView(index)...)
+	t = append([]float64{}, ds.t.RawRowView(index)...)
+	return x, t
+}
+
+func (ds *MatrixDataset) GetBatch(indexes []int) (x mat.Matrix, t mat.Matrix) {
+	_, xc := ds.x.Dims()
+	_, tc := ds.t.Dims()
+	bx := mat.NewDense(len(indexes), xc, nil)
+	bt := mat.NewDense(len(indexes), tc, nil)
+	for i, index := range indexes {
+		bx.SetRow(i, ds.x.RawRowView(index))
+		bt.SetRow(i, ds.t.RawRowView(index))
+	}
+	return bx, bt
+}
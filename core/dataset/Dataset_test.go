@@ -0,0 +1,72 @@
+package dataset
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMatrixDataset(t *testing.T) {
+	Convey("Given : 行列のデータセットが与えられた時", t, func() {
+		x := mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6})
+		label := mat.NewDense(3, 1, []float64{10, 20, 30})
+		ds := NewMatrixDataset(x, label)
+		Convey("When : インデックスを指定してバッチを取得する", func() {
+			bx, bt := ds.GetBatch([]int{2, 0})
+			Convey("Then : 指定した順にデータが取得できること", func() {
+				So(ds.Count(), ShouldEqual, 3)
+				So(mat.Equal(bx, mat.NewDense(2, 2, []float64{5, 6, 1, 2})), ShouldBeTrue)
+				So(mat.Equal(bt, mat.NewDense(2, 1, []float64{30, 10})), ShouldBeTrue)
+			})
+		})
+		Convey("When : インデックスを指定して1件のデータを取得し、値を変更する", func() {
+			sx, st := ds.GetSample(1)
+			sx[0] = 100
+			Convey("Then : 指定したデータが取得でき、元のデータは変更されないこと", func() {
+				So(st, ShouldResemble, []float64{20})
+				So(x.At(1, 0), ShouldEqual, 3)
+				rx, _ := ds.GetSample(1)
+				So(rx, ShouldResemble, []float64{3, 4})
+			})
+		})
+		Convey("Then : データ数が異なる場合はpanicが発生すること", func() {
+			So(func() { NewMatrixDataset(x, mat.NewDense(2, 1, nil)) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given : 1件ずつのみデータを取得できるデータセットが与えられた時", t, func() {
+		ds := newSequenceDataset(5)
+		Convey("When : GetBatchでインデックスを指定してバッチを取得する", func() {
+			bx, bt := GetBatch(ds, []int{4, 1, 3})
+			Convey("Then : 1件ずつ取得したデータが行列にまとめられること", func() {
+				So(mat.Equal(bx, mat.NewDense(3, 2, []float64{4, 8, 1, 2, 3, 6})), ShouldBeTrue)
+				So(mat.Equal(bt, mat.NewDense(3, 1, []float64{4, 1, 3})), ShouldBeTrue)
+			})
+		})
+		Convey("Then : インデックスを指定しない場合はpanicが発生すること", func() {
+			So(func() { GetBatch(ds, nil) }, ShouldPanic)
+		})
+	})
+}
+
+// sequenceDataset : i番目のデータの入力が[i, 2i]、正解データが[i]となるデータセット
+type sequenceDataset struct {
+	count   int
+	panicAt int // このインデックスのデータを取得するとpanicを発生させる（-1の場合は発生させない）
+}
+
+func newSequenceDataset(count int) *sequenceDataset {
+	return &sequenceDataset{count: count, panicAt: -1}
+}
+
+func (ds *sequenceDataset) Count() int {
+	return ds.count
+}
+
+func (ds *sequenceDataset) GetSample(index int) (x []float64, t []float64) {
+	if index == ds.panicAt {
+		panic("データを取得できません")
+	}
+	return []float64{float64(index), float64(index * 2)}, []float64{float64(index)}
+}
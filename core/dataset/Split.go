@@ -0,0 +1,258 @@
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LabeledDataset : データ毎のラベルの番号を取得できるデータセットのIF
+// 層化分割では、データセットがLabeledDatasetを実装していればGetLabelでラベルを取得する
+type LabeledDataset interface {
+	Dataset
+	// GetLabel : 指定したインデックスのデータのラベルの番号を取得
+	GetLabel(index int) int
+}
+
+// GetLabel : 指定したインデックスのデータのラベルの番号を取得
+// LabeledDatasetを実装していない場合は、正解データがone-hot形式であれば最大の要素の位置、1要素であればその値を四捨五入したものとする
+func GetLabel(ds Dataset, index int) int {
+	if labeledDataset, ok := ds.(LabeledDataset); ok {
+		return labeledDataset.GetLabel(index)
+	}
+	_, t := ds.GetSample(index)
+	if len(t) == 1 {
+		return int(math.Round(t[0]))
+	}
+	label := 0
+	for i := range t {
+		if t[i] > t[label] {
+			label = i
+		}
+	}
+	return label
+}
+
+// Subset : 元のデータセットの一部のデータのみを扱うデータセット
+type Subset struct {
+	ds      Dataset
+	indexes []int
+}
+
+// NewSubset : 元のデータセットdsのうち、指定したインデックスのデータのみを扱うデータセットを取得
+// SubsetのインデックスiのデータはdsのインデックスIndexes[i]のデータとなる
+func NewSubset(ds Dataset, indexes []int) *Subset {
+	for _, index := range indexes {
+		if index < 0 || index >= ds.Count() {
+			panic(fmt.Sprintf("インデックス%dがデータ数%dの範囲外です", index, ds.Count()))
+		}
+	}
+	return &Subset{ds: ds, indexes: append([]int{}, indexes...)}
+}
+
+func (s *Subset) Count() int {
+	return len(s.indexes)
+}
+
+func (s *Subset) GetSample(index int) (x []float64, t []float64) {
+	return s.ds.GetSample(s.indexes[index])
+}
+
+func (s *Subset) GetBatch(indexes []int) (x mat.Matrix, t mat.Matrix) {
+	return GetBatch(s.ds, s.convertIndexes(indexes))
+}
+
+func (s *Subset) GetLabel(index int) int {
+	return GetLabel(s.ds, s.indexes[index])
+}
+
+// GetDataset : 元のデータセットを取得
+func (s *Subset) GetDataset() Dataset {
+	return s.ds
+}
+
+// GetIndexes : 元のデータセットでのインデックスを取得
+func (s *Subset) GetIndexes() []int {
+	return append([]int{}, s.indexes...)
+}
+
+// convertIndexes : Subsetのインデックスを元のデータセットのインデックスに変換
+func (s *Subset) convertIndexes(indexes []int) []int {
+	converted := make([]int, len(indexes))
+	for i, index := range indexes {
+		converted[i] = s.indexes[index]
+	}
+	return converted
+}
+
+// splitConfig : データセットの分割の設定
+type splitConfig struct {
+	rnd        *rand.Rand
+	noStratify bool
+}
+
+// SplitOption : データセットの分割のオプション
+type SplitOption func(*splitConfig)
+
+// WithSplitRand : データの割り当てに利用する乱数生成器指定のオプションを取得
+func WithSplitRand(rnd *rand.Rand) SplitOption {
+	return func(c *splitConfig) {
+		c.rnd = rnd
+	}
+}
+
+// WithSplitNoStratify : ラベル毎の比率を揃えずに分割するオプションを取得（回帰のデータセットなどで利用する）
+func WithSplitNoStratify() SplitOption {
+	return func(c *splitConfig) {
+		c.noStratify = true
+	}
+}
+
+// newSplitConfig : オプションを適用した分割の設定を取得
+// 乱数生成器を指定しない場合は、util.SetSeedで設定したライブラリ全体のシードから派生させた乱数を利用する
+func newSplitConfig(options []SplitOption) *splitConfig {
+	c := splitConfig{}
+	for _, opt := range options {
+		opt(&c)
+	}
+	if c.rnd == nil {
+		c.rnd = util.NewRand()
+	}
+	return &c
+}
+
+// groupByLabel : インデックスをラベル毎にまとめ、ラベルの番号順に、ラベル内はシャッフルして取得する
+// 層化しない場合は全てのデータを1つのグループとする
+func (c *splitConfig) groupByLabel(ds Dataset) [][]int {
+	groups := make(map[int][]int)
+	for i := 0; i < ds.Count(); i++ {
+		label := 0
+		if !c.noStratify {
+			label = GetLabel(ds, i)
+		}
+		groups[label] = append(groups[label], i)
+	}
+	labels := make([]int, 0, len(groups))
+	for label := range groups {
+		labels = append(labels, label)
+	}
+	sort.Ints(labels)
+
+	grouped := make([][]int, 0, len(labels))
+	for _, label := range labels {
+		indexes := groups[label]
+		c.rnd.Shuffle(len(indexes), func(i, j int) {
+			indexes[i], indexes[j] = indexes[j], indexes[i]
+		})
+		grouped = append(grouped, indexes)
+	}
+	return grouped
+}
+
+// StratifiedSplit : データセットを指定した比率で重複なく分割する
+// ratios : 各分割の比率（合計で割って正規化する）
+// 各ラベルのデータをそれぞれ比率に応じて割り当てるため、分割後もラベル毎のデータ数の比率は元のデータセットとほぼ同じになる
+// 各分割のインデックスは元のデータセットでの昇順とする
+// 初期化時にオプション指定が可能
+func StratifiedSplit(ds Dataset, ratios []float64, options ...SplitOption) []*Subset {
+	if len(ratios) == 0 {
+		panic("分割の比率を指定してください")
+	}
+	sum := 0.0
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			panic("分割の比率は0以上を指定してください")
+		}
+		sum += ratio
+	}
+	if sum <= 0 {
+		panic("分割の比率の合計は0より大きい値を指定してください")
+	}
+	config := newSplitConfig(options)
+
+	splits := make([][]int, len(ratios))
+	for _, indexes := range config.groupByLabel(ds) {
+		// 比率の累積和で境界を決めるため、ラベル毎の端数は分割間で1件以内の差となる
+		start, cumulative := 0, 0.0
+		for i, ratio := range ratios {
+			cumulative += ratio
+			end := int(math.Round(cumulative / sum * float64(len(indexes))))
+			if i == len(ratios)-1 {
+				end = len(indexes)
+			}
+			splits[i] = append(splits[i], indexes[start:end]...)
+			start = end
+		}
+	}
+
+	subsets := make([]*Subset, len(splits))
+	for i, indexes := range splits {
+		sort.Ints(indexes)
+		subsets[i] = NewSubset(ds, indexes)
+	}
+	return subsets
+}
+
+// TrainValidationSplit : データセットを学習データと検証データに分割する
+// validationRatio : 検証データの比率（0より大きく1未満）
+func TrainValidationSplit(ds Dataset, validationRatio float64, options ...SplitOption) (train *Subset, validation *Subset) {
+	if validationRatio <= 0 || validationRatio >= 1 {
+		panic("検証データの比率は0より大きく1未満を指定してください")
+	}
+	subsets := StratifiedSplit(ds, []float64{1 - validationRatio, validationRatio}, options...)
+	return subsets[0], subsets[1]
+}
+
+// TrainValidationTestSplit : データセットを学習データ・検証データ・テストデータに分割する
+// validationRatio, testRatio : 検証データとテストデータの比率（それぞれ0より大きく、合計が1未満）
+func TrainValidationTestSplit(ds Dataset, validationRatio float64, testRatio float64, options ...SplitOption) (train *Subset, validation *Subset, test *Subset) {
+	if validationRatio <= 0 || testRatio <= 0 || validationRatio+testRatio >= 1 {
+		panic("検証データとテストデータの比率はそれぞれ0より大きく、合計が1未満となるように指定してください")
+	}
+	subsets := StratifiedSplit(ds, []float64{1 - validationRatio - testRatio, validationRatio, testRatio}, options...)
+	return subsets[0], subsets[1], subsets[2]
+}
+
+// Fold : 交差検証の1つの分割（学習データと検証データ）
+type Fold struct {
+	Train      *Subset
+	Validation *Subset
+}
+
+// StratifiedKFold : 交差検証のため、データセットをk個に重複なく分割し、それぞれを検証データとしたk組の分割を取得する
+// 各ラベルのデータを順にk個のグループへ割り当てるため、各グループのデータ数とラベル毎の比率はほぼ同じになる
+// 初期化時にオプション指定が可能
+func StratifiedKFold(ds Dataset, k int, options ...SplitOption) []Fold {
+	if k < 2 || k > ds.Count() {
+		panic(fmt.Sprintf("kは2以上、データ数%d以下を指定してください", ds.Count()))
+	}
+	config := newSplitConfig(options)
+
+	// ラベルをまたいで割り当て先を続けることで、各グループのデータ数の差を1件以内とする
+	groups := make([][]int, k)
+	next := 0
+	for _, indexes := range config.groupByLabel(ds) {
+		for _, index := range indexes {
+			groups[next] = append(groups[next], index)
+			next = (next + 1) % k
+		}
+	}
+
+	folds := make([]Fold, k)
+	for i := range groups {
+		train := make([]int, 0, ds.Count()-len(groups[i]))
+		for j := range groups {
+			if j != i {
+				train = append(train, groups[j]...)
+			}
+		}
+		sort.Ints(train)
+		sort.Ints(groups[i])
+		folds[i] = Fold{Train: NewSubset(ds, train), Validation: NewSubset(ds, groups[i])}
+	}
+	return folds
+}
@@ -0,0 +1,156 @@
+package dataset
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSubset(t *testing.T) {
+	Convey("Given : 行列のデータセットと、その一部のインデックスが与えられた時", t, func() {
+		x := mat.NewDense(4, 2, []float64{1, 2, 3, 4, 5, 6, 7, 8})
+		label := mat.NewDense(4, 2, []float64{1, 0, 0, 1, 0, 1, 1, 0})
+		subset := NewSubset(NewMatrixDataset(x, label), []int{3, 1})
+		Convey("When : Subsetからデータを取得する", func() {
+			sx, st := subset.GetSample(0)
+			bx, bt := subset.GetBatch([]int{1, 0})
+			Convey("Then : 元のデータセットの対応するインデックスのデータが取得できること", func() {
+				So(subset.Count(), ShouldEqual, 2)
+				So(sx, ShouldResemble, []float64{7, 8})
+				So(st, ShouldResemble, []float64{1, 0})
+				So(mat.Equal(bx, mat.NewDense(2, 2, []float64{3, 4, 7, 8})), ShouldBeTrue)
+				So(mat.Equal(bt, mat.NewDense(2, 2, []float64{0, 1, 1, 0})), ShouldBeTrue)
+				So(subset.GetLabel(1), ShouldEqual, 1)
+				So(subset.GetIndexes(), ShouldResemble, []int{3, 1})
+			})
+		})
+		Convey("Then : 範囲外のインデックスを指定した場合はpanicが発生すること", func() {
+			So(func() { NewSubset(NewMatrixDataset(x, label), []int{4}) }, ShouldPanic)
+		})
+	})
+}
+
+func TestStratifiedSplit(t *testing.T) {
+	Convey("Given : ラベル0が60件、ラベル1が30件、ラベル2が10件のデータセットが与えられた時", t, func() {
+		ds := newLabeledDataset(60, 30, 10)
+		Convey("When : 学習・検証・テストデータに7:2:1で分割する", func() {
+			train, validation, test := TrainValidationTestSplit(ds, 0.2, 0.1, WithSplitRand(rand.New(util.NewSource(1))))
+			Convey("Then : 比率に応じたデータ数となり、ラベル毎の比率も保たれること", func() {
+				So(train.Count(), ShouldEqual, 70)
+				So(validation.Count(), ShouldEqual, 20)
+				So(test.Count(), ShouldEqual, 10)
+				So(labelCounts(train), ShouldResemble, map[int]int{0: 42, 1: 21, 2: 7})
+				So(labelCounts(validation), ShouldResemble, map[int]int{0: 12, 1: 6, 2: 2})
+				So(labelCounts(test), ShouldResemble, map[int]int{0: 6, 1: 3, 2: 1})
+			})
+			Convey("Then : 全てのデータが重複なくいずれかに割り当てられること", func() {
+				used := make(map[int]bool)
+				for _, subset := range []*Subset{train, validation, test} {
+					for _, index := range subset.GetIndexes() {
+						So(used[index], ShouldBeFalse)
+						used[index] = true
+					}
+				}
+				So(len(used), ShouldEqual, 100)
+			})
+		})
+		Convey("When : 同じシードで2回分割する", func() {
+			train1, _ := TrainValidationSplit(ds, 0.25, WithSplitRand(rand.New(util.NewSource(3))))
+			train2, _ := TrainValidationSplit(ds, 0.25, WithSplitRand(rand.New(util.NewSource(3))))
+			train3, _ := TrainValidationSplit(ds, 0.25, WithSplitRand(rand.New(util.NewSource(4))))
+			Convey("Then : 同じ分割となり、シードが異なれば異なる分割となること", func() {
+				So(train1.GetIndexes(), ShouldResemble, train2.GetIndexes())
+				So(train1.GetIndexes(), ShouldNotResemble, train3.GetIndexes())
+			})
+		})
+		Convey("When : 層化せずに分割する", func() {
+			subsets := StratifiedSplit(ds, []float64{1, 1}, WithSplitNoStratify())
+			Convey("Then : 全体のデータ数が比率に応じて分割されること", func() {
+				So(subsets[0].Count(), ShouldEqual, 50)
+				So(subsets[1].Count(), ShouldEqual, 50)
+			})
+		})
+		Convey("Then : 不正な比率を指定した場合はpanicが発生すること", func() {
+			So(func() { StratifiedSplit(ds, nil) }, ShouldPanic)
+			So(func() { StratifiedSplit(ds, []float64{1, -1}) }, ShouldPanic)
+			So(func() { TrainValidationSplit(ds, 1) }, ShouldPanic)
+			So(func() { TrainValidationTestSplit(ds, 0.5, 0.5) }, ShouldPanic)
+		})
+	})
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	Convey("Given : ラベル0が60件、ラベル1が30件、ラベル2が10件のデータセットが与えられた時", t, func() {
+		ds := newLabeledDataset(60, 30, 10)
+		Convey("When : 5分割する", func() {
+			folds := StratifiedKFold(ds, 5, WithSplitRand(rand.New(util.NewSource(1))))
+			Convey("Then : 各foldの検証データはラベル毎の比率が保たれ、全体で重複なく全てのデータを含むこと", func() {
+				So(len(folds), ShouldEqual, 5)
+				used := make(map[int]bool)
+				for _, fold := range folds {
+					So(fold.Train.Count(), ShouldEqual, 80)
+					So(fold.Validation.Count(), ShouldEqual, 20)
+					So(labelCounts(fold.Validation), ShouldResemble, map[int]int{0: 12, 1: 6, 2: 2})
+					for _, index := range fold.Validation.GetIndexes() {
+						So(used[index], ShouldBeFalse)
+						used[index] = true
+					}
+				}
+				So(len(used), ShouldEqual, 100)
+			})
+			Convey("Then : 各foldの学習データは検証データ以外の全てのデータとなること", func() {
+				validation := make(map[int]bool)
+				for _, index := range folds[2].Validation.GetIndexes() {
+					validation[index] = true
+				}
+				for _, index := range folds[2].Train.GetIndexes() {
+					So(validation[index], ShouldBeFalse)
+				}
+			})
+		})
+		Convey("When : データ数がkで割り切れない場合に3分割する", func() {
+			folds := StratifiedKFold(ds, 3)
+			Convey("Then : 各foldの検証データ数の差は1件以内となること", func() {
+				So(folds[0].Validation.Count(), ShouldEqual, 34)
+				So(folds[1].Validation.Count(), ShouldEqual, 33)
+				So(folds[2].Validation.Count(), ShouldEqual, 33)
+			})
+		})
+		Convey("Then : 不正なkを指定した場合はpanicが発生すること", func() {
+			So(func() { StratifiedKFold(ds, 1) }, ShouldPanic)
+			So(func() { StratifiedKFold(ds, 101) }, ShouldPanic)
+		})
+	})
+}
+
+// newLabeledDataset : 指定したラベル毎のデータ数で、正解データをone-hot形式としたデータセットを作成
+// 入力はデータのインデックスとする
+func newLabeledDataset(counts ...int) *MatrixDataset {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	x := mat.NewDense(total, 1, nil)
+	t := mat.NewDense(total, len(counts), nil)
+	index := 0
+	for label, count := range counts {
+		for i := 0; i < count; i++ {
+			x.Set(index, 0, float64(index))
+			t.Set(index, label, 1)
+			index++
+		}
+	}
+	return NewMatrixDataset(x, t)
+}
+
+// labelCounts : ラベル毎のデータ数を取得
+func labelCounts(ds Dataset) map[int]int {
+	counts := make(map[int]int)
+	for i := 0; i < ds.Count(); i++ {
+		counts[GetLabel(ds, i)]++
+	}
+	return counts
+}
@@ -94,6 +94,11 @@ func (gp *GraphPoints) AddPoint(p Point) {
 	gp.points = append(gp.points, p)
 }
 
+// GetPoints : 追加済みの座標を取得
+func (gp *GraphPoints) GetPoints() []Point {
+	return gp.points
+}
+
 func (gp *GraphPoints) convertPlotterXYs() plotter.XYs {
 	pts := make(plotter.XYs, len(gp.points))
 	for i := range pts {
@@ -0,0 +1,84 @@
+package idx
+
+import (
+	"fmt"
+)
+
+// Dataset : IDXファイルの入力と正解ラベルをdataset.Datasetとして扱う
+// 入力は先頭の次元をデータ数とし、残りの次元を1次元に並べた値をそのまま利用する
+// 正解ラベルはクラス番号をone-hot形式に変換する
+type Dataset struct {
+	x          *Tensor
+	labels     *Tensor
+	classCount int
+}
+
+// NewDataset : 入力xと正解ラベルlabelsのTensorからデータセットを取得
+// labelsは(データ数)または(データ数, 1)の形状で、0以上classCount未満のクラス番号を持つ必要がある
+func NewDataset(x *Tensor, labels *Tensor, classCount int) *Dataset {
+	if classCount <= 0 {
+		panic("classCountは1以上を指定してください")
+	}
+	if err := validateLabels(x, labels, classCount); err != nil {
+		panic(err.Error())
+	}
+	return &Dataset{x: x, labels: labels, classCount: classCount}
+}
+
+// ReadDataset : 入力と正解ラベルのIDXファイルを読み込み、データセットを取得
+func ReadDataset(imagePath string, labelPath string, classCount int) (*Dataset, error) {
+	x, err := ReadFile(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := ReadFile(labelPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateLabels(x, labels, classCount); err != nil {
+		return nil, fmt.Errorf("%s, %s : %v", imagePath, labelPath, err)
+	}
+	return NewDataset(x, labels, classCount), nil
+}
+
+// validateLabels : 入力と正解ラベルのデータ数、正解ラベルの形状と値を検証する
+func validateLabels(x *Tensor, labels *Tensor, classCount int) error {
+	if x.Count() != labels.Count() {
+		return fmt.Errorf("入力のデータ数%dと正解ラベルのデータ数%dがマッチしてません", x.Count(), labels.Count())
+	}
+	if labels.ItemSize() != 1 {
+		return fmt.Errorf("正解ラベルの形状%vが不正です", labels.GetDims())
+	}
+	for i := 0; i < labels.Len(); i++ {
+		if label := labels.At(i); label < 0 || label >= float64(classCount) || label != float64(int(label)) {
+			return fmt.Errorf("%d番目の正解ラベル%vが0以上%d未満のクラス番号ではありません", i, label, classCount)
+		}
+	}
+	return nil
+}
+
+func (ds *Dataset) Count() int {
+	return ds.x.Count()
+}
+
+func (ds *Dataset) GetSample(index int) (x []float64, t []float64) {
+	x = ds.x.Item(index)
+	t = make([]float64, ds.classCount)
+	t[ds.GetLabel(index)] = 1
+	return x, t
+}
+
+// GetLabel : 指定したインデックスのデータのクラス番号を取得
+func (ds *Dataset) GetLabel(index int) int {
+	return int(ds.labels.At(index))
+}
+
+// GetClassCount : クラス数を取得
+func (ds *Dataset) GetClassCount() int {
+	return ds.classCount
+}
+
+// GetInput : 入力のTensorを取得
+func (ds *Dataset) GetInput() *Tensor {
+	return ds.x
+}
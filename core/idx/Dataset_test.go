@@ -0,0 +1,58 @@
+package idx
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDataset(t *testing.T) {
+	Convey("Given : 入力と正解ラベルのTensorが与えられた時", t, func() {
+		x := NewTensor(UnsignedByte, []int{3, 2, 2}, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11})
+		labels := NewTensor(UnsignedByte, []int{3}, []float64{2, 0, 1})
+		Convey("When : データセットを作成して1件のデータを取得する", func() {
+			ds := NewDataset(x, labels, 3)
+			sx, st := ds.GetSample(0)
+			Convey("Then : 入力の要素とone-hot形式の正解データが取得できること", func() {
+				So(ds.Count(), ShouldEqual, 3)
+				So(ds.GetClassCount(), ShouldEqual, 3)
+				So(ds.GetLabel(2), ShouldEqual, 1)
+				So(sx, ShouldResemble, []float64{0, 1, 2, 3})
+				So(st, ShouldResemble, []float64{0, 0, 1})
+			})
+		})
+		Convey("Then : データ数・ラベルの形状・ラベルの値が不正な場合はpanicが発生すること", func() {
+			So(func() { NewDataset(x, NewTensor(UnsignedByte, []int{2}, []float64{0, 1}), 3) }, ShouldPanic)
+			So(func() { NewDataset(x, NewTensor(UnsignedByte, []int{3, 2}, []float64{0, 1, 2, 0, 1, 2}), 3) }, ShouldPanic)
+			So(func() { NewDataset(x, labels, 2) }, ShouldPanic)
+			So(func() { NewDataset(x, labels, 0) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given : 入力と正解ラベルのIDXファイルが与えられた時", t, func() {
+		dir, _ := ioutil.TempDir("", "idx")
+		defer os.RemoveAll(dir)
+		imagePath := path.Join(dir, "images-idx3-ubyte.gz")
+		labelPath := path.Join(dir, "labels-idx1-ubyte")
+		WriteFile(imagePath, NewTensor(UnsignedByte, []int{2, 1, 2}, []float64{10, 20, 30, 40}))
+		WriteFile(labelPath, NewTensor(UnsignedByte, []int{2}, []float64{1, 4}))
+		Convey("When : ファイルからデータセットを読み込む", func() {
+			ds, err := ReadDataset(imagePath, labelPath, 5)
+			Convey("Then : ファイルのデータが取得できること", func() {
+				So(err, ShouldBeNil)
+				sx, st := ds.GetSample(1)
+				So(sx, ShouldResemble, []float64{30, 40})
+				So(st, ShouldResemble, []float64{0, 0, 0, 0, 1})
+			})
+		})
+		Convey("When : クラス数より大きいラベルを含むファイルを読み込む", func() {
+			_, err := ReadDataset(imagePath, labelPath, 3)
+			Convey("Then : エラーとなること", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
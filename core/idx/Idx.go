@@ -0,0 +1,314 @@
+package idx
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+)
+
+// DataType : IDXファイルの要素のデータ型
+type DataType byte
+
+const (
+	// UnsignedByte : 符号なし8bit整数
+	UnsignedByte DataType = 0x08
+	// SignedByte : 符号付き8bit整数
+	SignedByte DataType = 0x09
+	// Short : 符号付き16bit整数
+	Short DataType = 0x0B
+	// Int : 符号付き32bit整数
+	Int DataType = 0x0C
+	// Float : 32bit浮動小数点数
+	Float DataType = 0x0D
+	// Double : 64bit浮動小数点数
+	Double DataType = 0x0E
+)
+
+// maxDims : 次元数の上限（ヘッダーの次元数は1byteで表す）
+const maxDims = 255
+
+// Size : 1要素あたりのbyte数を取得（未対応のデータ型の場合は0）
+func (dt DataType) Size() int {
+	switch dt {
+	case UnsignedByte, SignedByte:
+		return 1
+	case Short:
+		return 2
+	case Int, Float:
+		return 4
+	case Double:
+		return 8
+	}
+	return 0
+}
+
+func (dt DataType) String() string {
+	switch dt {
+	case UnsignedByte:
+		return "ubyte"
+	case SignedByte:
+		return "byte"
+	case Short:
+		return "short"
+	case Int:
+		return "int"
+	case Float:
+		return "float"
+	case Double:
+		return "double"
+	}
+	return fmt.Sprintf("unknown(0x%02x)", byte(dt))
+}
+
+// Tensor : IDXファイルのデータ（データ型・各次元のサイズ・要素）
+// 要素はIDXファイルと同じビッグエンディアンのbyte列で保持し、取得時にfloat64に変換する
+type Tensor struct {
+	dataType DataType
+	dims     []int
+	data     []byte
+}
+
+// NewTensor : データ型・各次元のサイズ・要素からTensorを取得
+// 要素数が各次元のサイズの積と一致しない場合、データ型で表せない値が含まれる場合はpanicが発生する
+func NewTensor(dataType DataType, dims []int, values []float64) *Tensor {
+	if dataType.Size() == 0 {
+		panic(fmt.Sprintf("未対応のデータ型です : %v", dataType))
+	}
+	if len(dims) == 0 || len(dims) > maxDims {
+		panic(fmt.Sprintf("次元数は1以上%d以下を指定してください : %d", maxDims, len(dims)))
+	}
+	if count := elementCount(dims); count != len(values) {
+		panic(fmt.Sprintf("各次元のサイズ%vと要素数%dがマッチしてません", dims, len(values)))
+	}
+
+	t := Tensor{dataType: dataType, dims: append([]int{}, dims...), data: make([]byte, len(values)*dataType.Size())}
+	for i, v := range values {
+		t.set(i, v)
+	}
+	return &t
+}
+
+// GetDataType : データ型を取得
+func (t *Tensor) GetDataType() DataType {
+	return t.dataType
+}
+
+// GetDims : 各次元のサイズを取得
+func (t *Tensor) GetDims() []int {
+	return append([]int{}, t.dims...)
+}
+
+// Len : 全要素数を取得
+func (t *Tensor) Len() int {
+	return len(t.data) / t.dataType.Size()
+}
+
+// Count : 先頭の次元のサイズ（データ数）を取得
+func (t *Tensor) Count() int {
+	return t.dims[0]
+}
+
+// ItemSize : 1データあたりの要素数（先頭以外の次元のサイズの積）を取得
+func (t *Tensor) ItemSize() int {
+	return elementCount(t.dims[1:])
+}
+
+// At : 全要素を1次元に並べた時の、指定したインデックスの要素を取得
+func (t *Tensor) At(index int) float64 {
+	size := t.dataType.Size()
+	b := t.data[index*size : (index+1)*size]
+	switch t.dataType {
+	case UnsignedByte:
+		return float64(b[0])
+	case SignedByte:
+		return float64(int8(b[0]))
+	case Short:
+		return float64(int16(binary.BigEndian.Uint16(b)))
+	case Int:
+		return float64(int32(binary.BigEndian.Uint32(b)))
+	case Float:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b))
+}
+
+// Item : 先頭の次元で指定したインデックスのデータの要素を取得
+func (t *Tensor) Item(index int) []float64 {
+	if index < 0 || index >= t.Count() {
+		panic(fmt.Sprintf("インデックス%dがデータ数%dの範囲外です", index, t.Count()))
+	}
+	itemSize := t.ItemSize()
+	values := make([]float64, itemSize)
+	for i := range values {
+		values[i] = t.At(index*itemSize + i)
+	}
+	return values
+}
+
+// Values : 全要素を1次元に並べて取得
+func (t *Tensor) Values() []float64 {
+	values := make([]float64, t.Len())
+	for i := range values {
+		values[i] = t.At(i)
+	}
+	return values
+}
+
+// set : 指定したインデックスに要素を設定する（データ型で表せない値の場合はpanicが発生する）
+func (t *Tensor) set(index int, v float64) {
+	size := t.dataType.Size()
+	b := t.data[index*size : (index+1)*size]
+	switch t.dataType {
+	case UnsignedByte:
+		b[0] = byte(checkInteger(v, 0, math.MaxUint8))
+	case SignedByte:
+		b[0] = byte(int8(checkInteger(v, math.MinInt8, math.MaxInt8)))
+	case Short:
+		binary.BigEndian.PutUint16(b, uint16(int16(checkInteger(v, math.MinInt16, math.MaxInt16))))
+	case Int:
+		binary.BigEndian.PutUint32(b, uint32(int32(checkInteger(v, math.MinInt32, math.MaxInt32))))
+	case Float:
+		binary.BigEndian.PutUint32(b, math.Float32bits(float32(v)))
+	case Double:
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+	}
+}
+
+// checkInteger : 値がmin以上max以下の整数であることを確認する
+func checkInteger(v float64, min float64, max float64) int64 {
+	if v != math.Trunc(v) || v < min || v > max {
+		panic(fmt.Sprintf("値%vは%v以上%v以下の整数ではありません", v, min, max))
+	}
+	return int64(v)
+}
+
+// elementCount : 各次元のサイズの積を取得
+func elementCount(dims []int) int {
+	count := 1
+	for _, d := range dims {
+		count *= d
+	}
+	return count
+}
+
+// Read : IDX形式のデータを読み込む（gzip圧縮されている場合は展開して読み込む）
+func Read(r io.Reader) (*Tensor, error) {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if head, err := br.Peek(2); err == nil && head[0] == 0x1f && head[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	// ヘッダー : 0, 0, データ型, 次元数の4byteと、次元毎のサイズ（ビッグエンディアンの32bit整数）
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return nil, fmt.Errorf("IDXのヘッダーが読み込めません : %v", err)
+	}
+	if header[0] != 0 || header[1] != 0 {
+		return nil, errors.New("IDX形式のデータではありません")
+	}
+	dataType := DataType(header[2])
+	if dataType.Size() == 0 {
+		return nil, fmt.Errorf("未対応のデータ型です : %v", dataType)
+	}
+	if header[3] == 0 {
+		return nil, errors.New("IDXの次元数が0です")
+	}
+
+	dims := make([]int, header[3])
+	count := int64(1)
+	for i := range dims {
+		var d uint32
+		if err := binary.Read(src, binary.BigEndian, &d); err != nil {
+			return nil, fmt.Errorf("IDXの次元のサイズが読み込めません : %v", err)
+		}
+		dims[i] = int(d)
+		count *= int64(d)
+		if count*int64(dataType.Size()) > math.MaxInt32*8 {
+			return nil, fmt.Errorf("IDXのデータサイズが大きすぎます : %v", dims)
+		}
+	}
+
+	// ヘッダーの値だけで領域を確保しないように、実際に読み込めたデータ量を確認する
+	size := count * int64(dataType.Size())
+	data, err := ioutil.ReadAll(io.LimitReader(src, size))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != size {
+		return nil, fmt.Errorf("IDXのデータが不足しています : expected %d bytes, actual %d bytes", size, len(data))
+	}
+	return &Tensor{dataType: dataType, dims: dims, data: data}, nil
+}
+
+// ReadFile : IDX形式のファイルを読み込む（gzip圧縮されている場合は展開して読み込む）
+func ReadFile(filePath string) (*Tensor, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %v", filePath, err)
+	}
+	return t, nil
+}
+
+// Write : IDX形式でデータを書き込む
+func Write(w io.Writer, t *Tensor) error {
+	header := []byte{0, 0, byte(t.dataType), byte(len(t.dims))}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	for _, d := range t.dims {
+		if err := binary.Write(w, binary.BigEndian, uint32(d)); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(t.data)
+	return err
+}
+
+// WriteFile : IDX形式のファイルを書き込む（ファイル名が.gzで終わる場合はgzip圧縮する）
+func WriteFile(filePath string, t *Tensor) (err error) {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	if !strings.HasSuffix(filePath, ".gz") {
+		if err := Write(w, t); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := Write(gz, t); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
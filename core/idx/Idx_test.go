@@ -0,0 +1,154 @@
+package idx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTensor(t *testing.T) {
+	Convey("Given : (3, 2, 2)のTensorが与えられた時", t, func() {
+		values := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+		tensor := NewTensor(UnsignedByte, []int{3, 2, 2}, values)
+		Convey("Then : データ数・1データあたりの要素数・要素が取得できること", func() {
+			So(tensor.GetDataType(), ShouldEqual, UnsignedByte)
+			So(tensor.GetDims(), ShouldResemble, []int{3, 2, 2})
+			So(tensor.Len(), ShouldEqual, 12)
+			So(tensor.Count(), ShouldEqual, 3)
+			So(tensor.ItemSize(), ShouldEqual, 4)
+			So(tensor.Item(1), ShouldResemble, []float64{4, 5, 6, 7})
+			So(tensor.Values(), ShouldResemble, values)
+		})
+		Convey("Then : 範囲外のデータを指定した場合はpanicが発生すること", func() {
+			So(func() { tensor.Item(3) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given : 不正な引数が与えられた時", t, func() {
+		Convey("Then : NewTensorでpanicが発生すること", func() {
+			So(func() { NewTensor(UnsignedByte, []int{2, 2}, []float64{1, 2, 3}) }, ShouldPanic)
+			So(func() { NewTensor(UnsignedByte, nil, nil) }, ShouldPanic)
+			So(func() { NewTensor(DataType(0x01), []int{1}, []float64{1}) }, ShouldPanic)
+			So(func() { NewTensor(UnsignedByte, []int{1}, []float64{256}) }, ShouldPanic)
+			So(func() { NewTensor(SignedByte, []int{1}, []float64{-129}) }, ShouldPanic)
+			So(func() { NewTensor(Int, []int{1}, []float64{0.5}) }, ShouldPanic)
+		})
+	})
+}
+
+func TestReadWrite(t *testing.T) {
+	Convey("Given : 各データ型のTensorが与えられた時", t, func() {
+		tensors := map[DataType]*Tensor{
+			UnsignedByte: NewTensor(UnsignedByte, []int{2, 3}, []float64{0, 1, 2, 127, 128, 255}),
+			SignedByte:   NewTensor(SignedByte, []int{2, 3}, []float64{-128, -1, 0, 1, 64, 127}),
+			Short:        NewTensor(Short, []int{2, 3}, []float64{math.MinInt16, -300, 0, 1, 300, math.MaxInt16}),
+			Int:          NewTensor(Int, []int{2, 3}, []float64{math.MinInt32, -70000, 0, 1, 70000, math.MaxInt32}),
+			Float:        NewTensor(Float, []int{2, 3}, []float64{-1.5, 0, 0.25, 3, 1e10, -1e-3}),
+			Double:       NewTensor(Double, []int{2, 3}, []float64{-1.5, 0, 0.1, math.Pi, 1e300, -1e-300}),
+		}
+		Convey("When : 書き込んだデータを読み込む", func() {
+			for dataType, tensor := range tensors {
+				buf := new(bytes.Buffer)
+				So(Write(buf, tensor), ShouldBeNil)
+				read, err := Read(buf)
+				Convey("Then : "+dataType.String()+"のデータ型・形状・要素が一致すること", func() {
+					So(err, ShouldBeNil)
+					So(read.GetDataType(), ShouldEqual, dataType)
+					So(read.GetDims(), ShouldResemble, []int{2, 3})
+					if dataType == Float {
+						for i, v := range tensor.Values() {
+							So(read.At(i), ShouldEqual, float64(float32(v)))
+						}
+					} else {
+						So(read.Values(), ShouldResemble, tensor.Values())
+					}
+				})
+			}
+		})
+	})
+
+	Convey("Given : Mnistのラベル形式（idx1-ubyte）のデータが与えられた時", t, func() {
+		data := []byte{0, 0, 0x08, 1, 0, 0, 0, 3, 7, 2, 1}
+		Convey("When : データを読み込む", func() {
+			tensor, err := Read(bytes.NewReader(data))
+			Convey("Then : ヘッダーに従って要素が取得できること", func() {
+				So(err, ShouldBeNil)
+				So(tensor.GetDims(), ShouldResemble, []int{3})
+				So(tensor.Values(), ShouldResemble, []float64{7, 2, 1})
+			})
+		})
+		Convey("When : 書き込む", func() {
+			buf := new(bytes.Buffer)
+			err := Write(buf, NewTensor(UnsignedByte, []int{3}, []float64{7, 2, 1}))
+			Convey("Then : 同じbyte列となること", func() {
+				So(err, ShouldBeNil)
+				So(buf.Bytes(), ShouldResemble, data)
+			})
+		})
+		Convey("When : gzip圧縮したデータを読み込む", func() {
+			buf := new(bytes.Buffer)
+			gz := gzip.NewWriter(buf)
+			gz.Write(data)
+			gz.Close()
+			tensor, err := Read(buf)
+			Convey("Then : 展開して読み込めること", func() {
+				So(err, ShouldBeNil)
+				So(tensor.Values(), ShouldResemble, []float64{7, 2, 1})
+			})
+		})
+		Convey("Then : 不正なデータの場合はエラーとなること", func() {
+			invalids := [][]byte{
+				{},
+				{1, 0, 0x08, 1, 0, 0, 0, 3, 7, 2, 1},
+				{0, 0, 0x01, 1, 0, 0, 0, 3, 7, 2, 1},
+				{0, 0, 0x08, 0},
+				{0, 0, 0x08, 1, 0, 0},
+				{0, 0, 0x08, 1, 0, 0, 0, 3, 7, 2},
+				{0, 0, 0x0E, 2, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			}
+			for _, invalid := range invalids {
+				_, err := Read(bytes.NewReader(invalid))
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given : 一時フォルダとTensorが与えられた時", t, func() {
+		dir, _ := ioutil.TempDir("", "idx")
+		defer os.RemoveAll(dir)
+		tensor := NewTensor(Short, []int{2, 2, 2}, []float64{-4, -3, -2, -1, 0, 1, 2, 3})
+		Convey("When : 拡張子.gzのファイルに書き込んで読み込む", func() {
+			filePath := path.Join(dir, "data-idx3-short.gz")
+			So(WriteFile(filePath, tensor), ShouldBeNil)
+			raw, _ := ioutil.ReadFile(filePath)
+			read, err := ReadFile(filePath)
+			Convey("Then : gzip圧縮されて書き込まれ、同じデータが読み込めること", func() {
+				So(raw[:2], ShouldResemble, []byte{0x1f, 0x8b})
+				So(err, ShouldBeNil)
+				So(read.GetDims(), ShouldResemble, tensor.GetDims())
+				So(read.Values(), ShouldResemble, tensor.Values())
+			})
+		})
+		Convey("When : 拡張子.gz以外のファイルに書き込んで読み込む", func() {
+			filePath := path.Join(dir, "data-idx3-short")
+			So(WriteFile(filePath, tensor), ShouldBeNil)
+			raw, _ := ioutil.ReadFile(filePath)
+			read, err := ReadFile(filePath)
+			Convey("Then : 圧縮されずに書き込まれ、同じデータが読み込めること", func() {
+				So(len(raw), ShouldEqual, 4+3*4+8*2)
+				So(err, ShouldBeNil)
+				So(read.Values(), ShouldResemble, tensor.Values())
+			})
+		})
+		Convey("Then : 存在しないファイルを読み込んだ場合はエラーとなること", func() {
+			_, err := ReadFile(path.Join(dir, "none"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -1 +1,346 @@
 package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	// PNG, JPEGのデコーダーを登録する
+	_ "image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ColorMode : 画像を変換する色の形式
+type ColorMode int
+
+const (
+	// RGB : R, G, Bの3チャネル
+	RGB ColorMode = iota
+	// Grayscale : 輝度の1チャネル
+	Grayscale
+)
+
+// Channel : チャネル数を取得
+func (mode ColorMode) Channel() int {
+	if mode == Grayscale {
+		return 1
+	}
+	return 3
+}
+
+// defaultExtensions : デフォルトで読み込む画像ファイルの拡張子
+var defaultExtensions = []string{".png", ".jpg", ".jpeg"}
+
+// FolderDataset : root/<クラス名>/*.pngのように、クラス毎のフォルダに格納した画像のデータセット
+// 画像は指定したサイズに縮小・拡大し、(チャネル, 高さ, 幅)の順に並べた0〜255の値として扱う
+// 正解データはクラス番号をone-hot形式に変換する（クラス番号はクラス名の昇順）
+type FolderDataset struct {
+	rootPath   string
+	width      int
+	height     int
+	mode       ColorMode
+	extensions []string
+	classNames []string
+	lazy       bool
+
+	paths  []string
+	labels []int
+	pixels [][]byte // 読み込み済みの画像（lazyの場合はnil）
+}
+
+// FolderDatasetOption : FolderDatasetのオプション
+type FolderDatasetOption func(*FolderDataset)
+
+// NewFolderDataset : FolderDatasetを取得
+// rootPath : クラス毎のフォルダを格納したフォルダ, width, height : 変換後の画像の幅と高さ
+// デフォルトではRGBに変換し、作成時に全ての画像を読み込む（読み込めない画像があればエラーを返す）
+// 初期化時にオプション指定が可能
+func NewFolderDataset(rootPath string, width int, height int, options ...FolderDatasetOption) (*FolderDataset, error) {
+	if width <= 0 || height <= 0 {
+		panic("width, heightは1以上を指定してください")
+	}
+	ds := FolderDataset{rootPath: rootPath, width: width, height: height, mode: RGB, extensions: defaultExtensions}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&ds)
+	}
+
+	if err := ds.scan(); err != nil {
+		return nil, err
+	}
+	if ds.lazy {
+		return &ds, nil
+	}
+	ds.pixels = make([][]byte, len(ds.paths))
+	for i, filePath := range ds.paths {
+		pixels, err := ds.readPixels(filePath)
+		if err != nil {
+			return nil, err
+		}
+		ds.pixels[i] = pixels
+	}
+	return &ds, nil
+}
+
+// WithFolderDatasetGrayscale : グレースケールに変換するオプションを取得
+func WithFolderDatasetGrayscale() FolderDatasetOption {
+	return func(ds *FolderDataset) {
+		ds.mode = Grayscale
+	}
+}
+
+// WithFolderDatasetClassNames : クラス番号の順に並べたクラス名指定のオプションを取得
+// 学習用データと検証用データでクラス番号を揃える場合に利用する
+// 指定していないクラス名のフォルダがある場合はエラーとなる（画像が無いクラスがあっても良い）
+func WithFolderDatasetClassNames(classNames []string) FolderDatasetOption {
+	return func(ds *FolderDataset) {
+		ds.classNames = append([]string{}, classNames...)
+	}
+}
+
+// WithFolderDatasetExtensions : 読み込む画像ファイルの拡張子指定のオプションを取得（大文字・小文字は区別しない）
+func WithFolderDatasetExtensions(extensions ...string) FolderDatasetOption {
+	return func(ds *FolderDataset) {
+		ds.extensions = extensions
+	}
+}
+
+// WithFolderDatasetLazy : 作成時に画像を読み込まず、データを取得する度に読み込むオプションを取得
+// メモリ使用量を抑えられるが、読み込めない画像がある場合はデータの取得時にpanicが発生する
+func WithFolderDatasetLazy() FolderDatasetOption {
+	return func(ds *FolderDataset) {
+		ds.lazy = true
+	}
+}
+
+// scan : クラス毎のフォルダを探索し、画像ファイルのパスとクラス番号を取得する
+func (ds *FolderDataset) scan() error {
+	infos, err := ioutil.ReadDir(ds.rootPath)
+	if err != nil {
+		return err
+	}
+	dirs := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() && !strings.HasPrefix(info.Name(), ".") {
+			dirs = append(dirs, info.Name())
+		}
+	}
+
+	labelIndex := make(map[string]int)
+	if ds.classNames == nil {
+		ds.classNames = dirs
+	}
+	for i, name := range ds.classNames {
+		labelIndex[name] = i
+	}
+
+	for _, dir := range dirs {
+		label, ok := labelIndex[dir]
+		if !ok {
+			return fmt.Errorf("%s : 指定したクラス名に無いフォルダです", filepath.Join(ds.rootPath, dir))
+		}
+		// クラスのフォルダ以下は、サブフォルダも含めてファイル名の昇順に読み込む
+		err := filepath.Walk(filepath.Join(ds.rootPath, dir), func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && ds.isImageFile(filePath) {
+				ds.paths = append(ds.paths, filePath)
+				ds.labels = append(ds.labels, label)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(ds.paths) == 0 {
+		return fmt.Errorf("%s : 画像ファイルが見つかりません", ds.rootPath)
+	}
+	return nil
+}
+
+// isImageFile : 読み込む拡張子のファイルかどうか
+func (ds *FolderDataset) isImageFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, e := range ds.extensions {
+		if ext == strings.ToLower(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPixels : 画像ファイルを読み込み、色の形式・サイズを変換した画素値を取得
+func (ds *FolderDataset) readPixels(filePath string) ([]byte, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %v", filePath, err)
+	}
+	if img.Bounds().Empty() {
+		return nil, fmt.Errorf("%s : 画像のサイズが0です", filePath)
+	}
+	return Convert(img, ds.width, ds.height, ds.mode), nil
+}
+
+// Convert : 画像を指定した色の形式に変換し、バイリニア補間で指定したサイズに縮小・拡大する
+// 戻り値は(チャネル, 高さ, 幅)の順に並べた画素値
+// 透過色を持つ画像は黒の背景に合成した色として扱う
+func Convert(img image.Image, width int, height int, mode ColorMode) []byte {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	channel := mode.Channel()
+
+	// 変換前のサイズで、チャネル毎の画素値を取得する
+	src := make([][]float64, channel)
+	for c := range src {
+		src[c] = make([]float64, srcW*srcH)
+	}
+	for y := 0; y < srcH; y++ {
+		for x := 0; x < srcW; x++ {
+			px := img.At(bounds.Min.X+x, bounds.Min.Y+y)
+			if mode == Grayscale {
+				src[0][y*srcW+x] = float64(color.GrayModel.Convert(px).(color.Gray).Y)
+				continue
+			}
+			r, g, b, _ := px.RGBA()
+			src[0][y*srcW+x] = float64(r >> 8)
+			src[1][y*srcW+x] = float64(g >> 8)
+			src[2][y*srcW+x] = float64(b >> 8)
+		}
+	}
+
+	dst := make([]byte, 0, channel*height*width)
+	for c := 0; c < channel; c++ {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				v := bilinear(src[c], srcW, srcH, x, y, width, height)
+				dst = append(dst, byte(math.Min(math.Max(math.Round(v), 0), 255)))
+			}
+		}
+	}
+	return dst
+}
+
+// bilinear : 変換後の画素(x, y)の中心に対応する変換前の位置の値を、周囲4画素から補間して取得
+func bilinear(src []float64, srcW int, srcH int, x int, y int, width int, height int) float64 {
+	sx := clamp((float64(x)+0.5)*float64(srcW)/float64(width)-0.5, float64(srcW-1))
+	sy := clamp((float64(y)+0.5)*float64(srcH)/float64(height)-0.5, float64(srcH-1))
+	x0, y0 := int(sx), int(sy)
+	x1, y1 := x0, y0
+	if x0+1 < srcW {
+		x1 = x0 + 1
+	}
+	if y0+1 < srcH {
+		y1 = y0 + 1
+	}
+	fx, fy := sx-float64(x0), sy-float64(y0)
+	top := src[y0*srcW+x0]*(1-fx) + src[y0*srcW+x1]*fx
+	bottom := src[y1*srcW+x0]*(1-fx) + src[y1*srcW+x1]*fx
+	return top*(1-fy) + bottom*fy
+}
+
+// clamp : 0以上max以下に値を制限する
+func clamp(v float64, max float64) float64 {
+	return math.Min(math.Max(v, 0), max)
+}
+
+func (ds *FolderDataset) Count() int {
+	return len(ds.paths)
+}
+
+func (ds *FolderDataset) GetSample(index int) (x []float64, t []float64) {
+	x = ds.GetImageVector(index)
+	t = make([]float64, len(ds.classNames))
+	t[ds.labels[index]] = 1
+	return x, t
+}
+
+func (ds *FolderDataset) GetBatch(indexes []int) (x mat.Matrix, t mat.Matrix) {
+	bx := mat.NewDense(len(indexes), ds.GetShape().Size(), nil)
+	bt := mat.NewDense(len(indexes), len(ds.classNames), nil)
+	for i, index := range indexes {
+		bx.SetRow(i, ds.GetImageVector(index))
+		bt.Set(i, ds.labels[index], 1)
+	}
+	return bx, bt
+}
+
+// GetImageVector : 指定したインデックスの画像を(チャネル, 高さ, 幅)の順に並べた0〜255の値で取得
+func (ds *FolderDataset) GetImageVector(index int) []float64 {
+	if index < 0 || index >= ds.Count() {
+		panic(fmt.Sprintf("インデックス%dがデータ数%dの範囲外です", index, ds.Count()))
+	}
+	pixels := ds.pixels
+	if ds.lazy {
+		p, err := ds.readPixels(ds.paths[index])
+		if err != nil {
+			panic(err.Error())
+		}
+		pixels = [][]byte{p}
+		index = 0
+	}
+	x := make([]float64, len(pixels[index]))
+	for i, v := range pixels[index] {
+		x[i] = float64(v)
+	}
+	return x
+}
+
+// GetImagesWithChannel : 指定したインデックスの画像を、ImagesWithChannelの形式で取得
+func (ds *FolderDataset) GetImagesWithChannel(indexes []int) neuralNetwork.ImagesWithChannel {
+	shape := ds.GetShape()
+	input := make([]float64, 0, len(indexes)*shape.Size())
+	for _, index := range indexes {
+		input = append(input, ds.GetImageVector(index)...)
+	}
+	return neuralNetwork.NewImagesWithChannel(input, shape.Width, shape.Height, shape.Channel, len(indexes))
+}
+
+// GetShape : 1枚の画像の形状（チャネル数・高さ・幅）を取得
+func (ds *FolderDataset) GetShape() neuralNetwork.Shape {
+	return neuralNetwork.NewShape(ds.mode.Channel(), ds.height, ds.width)
+}
+
+// GetLabel : 指定したインデックスの画像のクラス番号を取得
+func (ds *FolderDataset) GetLabel(index int) int {
+	return ds.labels[index]
+}
+
+// GetPath : 指定したインデックスの画像のファイルパスを取得
+func (ds *FolderDataset) GetPath(index int) string {
+	return ds.paths[index]
+}
+
+// GetClassNames : クラス番号の順に並べたクラス名を取得
+func (ds *FolderDataset) GetClassNames() []string {
+	return append([]string{}, ds.classNames...)
+}
+
+// GetClassCount : クラス数を取得
+func (ds *FolderDataset) GetClassCount() int {
+	return len(ds.classNames)
+}
+
+// GetClassCounts : クラス番号の順に、クラス毎の画像数を取得
+func (ds *FolderDataset) GetClassCounts() []int {
+	counts := make([]int, len(ds.classNames))
+	for _, label := range ds.labels {
+		counts[label]++
+	}
+	return counts
+}
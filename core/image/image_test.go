@@ -0,0 +1,182 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestConvert(t *testing.T) {
+	Convey("Given : 4*2のRGBの画像が与えられた時", t, func() {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 4; x++ {
+				img.Set(x, y, color.RGBA{uint8(x * 40), uint8(y * 100), 200, 255})
+			}
+		}
+		Convey("When : 同じサイズのRGBに変換する", func() {
+			pixels := Convert(img, 4, 2, RGB)
+			Convey("Then : (チャネル, 高さ, 幅)の順に画素値が並ぶこと", func() {
+				So(len(pixels), ShouldEqual, 3*2*4)
+				So(pixels[:8], ShouldResemble, []byte{0, 40, 80, 120, 0, 40, 80, 120})
+				So(pixels[8:16], ShouldResemble, []byte{0, 0, 0, 0, 100, 100, 100, 100})
+				So(pixels[16:], ShouldResemble, []byte{200, 200, 200, 200, 200, 200, 200, 200})
+			})
+		})
+		Convey("When : 2*1に縮小する", func() {
+			pixels := Convert(img, 2, 1, RGB)
+			Convey("Then : 縮小前の2*2の画素の平均となること", func() {
+				So(pixels, ShouldResemble, []byte{20, 100, 50, 50, 200, 200})
+			})
+		})
+		Convey("When : 8*4に拡大する", func() {
+			pixels := Convert(img, 8, 4, RGB)
+			Convey("Then : 端の画素は元の値となり、間の画素は補間されること", func() {
+				So(len(pixels), ShouldEqual, 3*4*8)
+				So(pixels[0], ShouldEqual, 0)
+				So(pixels[7], ShouldEqual, 120)
+				So(pixels[1], ShouldEqual, 10)
+				So(pixels[2*32+8*3+7], ShouldEqual, 200)
+			})
+		})
+		Convey("When : グレースケールに変換する", func() {
+			pixels := Convert(img, 4, 2, Grayscale)
+			Convey("Then : 1チャネルの輝度となること", func() {
+				So(len(pixels), ShouldEqual, 8)
+				So(pixels[0], ShouldEqual, color.GrayModel.Convert(color.RGBA{0, 0, 200, 255}).(color.Gray).Y)
+				So(pixels[7], ShouldEqual, color.GrayModel.Convert(color.RGBA{120, 100, 200, 255}).(color.Gray).Y)
+			})
+		})
+	})
+}
+
+func TestFolderDataset(t *testing.T) {
+	Convey("Given : クラス毎のフォルダに画像を格納したフォルダが与えられた時", t, func() {
+		rootPath, _ := ioutil.TempDir("", "image")
+		defer os.RemoveAll(rootPath)
+		writePNG(filepath.Join(rootPath, "dog", "b.png"), 4, 4, color.RGBA{10, 20, 30, 255})
+		writePNG(filepath.Join(rootPath, "dog", "a.PNG"), 8, 6, color.RGBA{40, 50, 60, 255})
+		writePNG(filepath.Join(rootPath, "cat", "sub", "c.png"), 2, 2, color.RGBA{70, 80, 90, 255})
+		writeJPEG(filepath.Join(rootPath, "cat", "d.jpg"), 4, 4, color.RGBA{128, 128, 128, 255})
+		ioutil.WriteFile(filepath.Join(rootPath, "cat", "readme.txt"), []byte("not image"), 0644)
+		os.MkdirAll(filepath.Join(rootPath, ".cache"), 0777)
+
+		Convey("When : RGBの4*4でデータセットを作成する", func() {
+			ds, err := NewFolderDataset(rootPath, 4, 4)
+			Convey("Then : クラス名の昇順にクラス番号が付き、画像ファイルのみが読み込まれること", func() {
+				So(err, ShouldBeNil)
+				So(ds.GetClassNames(), ShouldResemble, []string{"cat", "dog"})
+				So(ds.Count(), ShouldEqual, 4)
+				So(ds.GetClassCounts(), ShouldResemble, []int{2, 2})
+				So(filepath.Base(ds.GetPath(0)), ShouldEqual, "d.jpg")
+				So(filepath.Base(ds.GetPath(1)), ShouldEqual, "c.png")
+				So(filepath.Base(ds.GetPath(2)), ShouldEqual, "a.PNG")
+				So(ds.GetLabel(2), ShouldEqual, 1)
+				So(ds.GetShape().Size(), ShouldEqual, 3*4*4)
+			})
+			Convey("Then : 指定したサイズに変換した画像とone-hot形式の正解データが取得できること", func() {
+				x, label := ds.GetSample(1)
+				So(len(x), ShouldEqual, 3*4*4)
+				So(x[0], ShouldEqual, 70)
+				So(x[16], ShouldEqual, 80)
+				So(x[47], ShouldEqual, 90)
+				So(label, ShouldResemble, []float64{1, 0})
+			})
+			Convey("Then : バッチとImagesWithChannelの形式で同じ画素値が取得できること", func() {
+				bx, bt := ds.GetBatch([]int{3, 1})
+				images := ds.GetImagesWithChannel([]int{3, 1})
+				So(mat.Equal(bt, mat.NewDense(2, 2, []float64{0, 1, 1, 0})), ShouldBeTrue)
+				So(len(images), ShouldEqual, 2)
+				So(len(images[0]), ShouldEqual, 3)
+				So(images[0][2][3][1], ShouldEqual, bx.At(0, 2*16+3*4+1))
+				So(images[1][1][0][0], ShouldEqual, 80)
+			})
+		})
+
+		Convey("When : グレースケールで作成時に画像を読み込まない設定でデータセットを作成する", func() {
+			ds, err := NewFolderDataset(rootPath, 3, 2, WithFolderDatasetGrayscale(), WithFolderDatasetLazy())
+			Convey("Then : 1チャネルの画像がデータ取得時に読み込まれること", func() {
+				So(err, ShouldBeNil)
+				So(ds.GetShape().Channel, ShouldEqual, 1)
+				x, _ := ds.GetSample(3)
+				So(len(x), ShouldEqual, 6)
+				So(x[0], ShouldEqual, color.GrayModel.Convert(color.RGBA{10, 20, 30, 255}).(color.Gray).Y)
+				os.Remove(ds.GetPath(3))
+				So(func() { ds.GetSample(3) }, ShouldPanic)
+			})
+		})
+
+		Convey("When : クラス名を指定してデータセットを作成する", func() {
+			ds, err := NewFolderDataset(rootPath, 2, 2, WithFolderDatasetClassNames([]string{"bird", "dog", "cat"}))
+			Convey("Then : 指定した順にクラス番号が付くこと", func() {
+				So(err, ShouldBeNil)
+				So(ds.GetClassCount(), ShouldEqual, 3)
+				So(ds.GetClassCounts(), ShouldResemble, []int{0, 2, 2})
+				_, label := ds.GetSample(0)
+				So(label, ShouldResemble, []float64{0, 0, 1})
+			})
+		})
+
+		Convey("When : 指定したクラス名に無いフォルダがある", func() {
+			_, err := NewFolderDataset(rootPath, 2, 2, WithFolderDatasetClassNames([]string{"dog"}))
+			Convey("Then : エラーとなること", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When : 読み込めない画像ファイルがある", func() {
+			ioutil.WriteFile(filepath.Join(rootPath, "cat", "broken.png"), []byte("broken"), 0644)
+			_, err := NewFolderDataset(rootPath, 2, 2)
+			Convey("Then : エラーとなること", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When : 読み込む拡張子を指定して画像ファイルが無い", func() {
+			_, err := NewFolderDataset(rootPath, 2, 2, WithFolderDatasetExtensions(".bmp"))
+			Convey("Then : エラーとなること", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Then : 存在しないフォルダ・不正なサイズを指定した場合はエラー・panicとなること", func() {
+			_, err := NewFolderDataset(filepath.Join(rootPath, "none"), 2, 2)
+			So(err, ShouldNotBeNil)
+			So(func() { NewFolderDataset(rootPath, 0, 2) }, ShouldPanic)
+		})
+	})
+}
+
+// writePNG : 単色の画像をPNG形式で書き込む
+func writePNG(filePath string, width int, height int, c color.Color) {
+	os.MkdirAll(filepath.Dir(filePath), 0777)
+	f, _ := os.Create(filePath)
+	defer f.Close()
+	png.Encode(f, uniformImage(width, height, c))
+}
+
+// writeJPEG : 単色の画像をJPEG形式で書き込む
+func writeJPEG(filePath string, width int, height int, c color.Color) {
+	os.MkdirAll(filepath.Dir(filePath), 0777)
+	f, _ := os.Create(filePath)
+	defer f.Close()
+	jpeg.Encode(f, uniformImage(width, height, c), &jpeg.Options{Quality: 100})
+}
+
+func uniformImage(width int, height int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
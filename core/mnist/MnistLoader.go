@@ -4,61 +4,237 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math/rand"
+	"net/http"
+	"os"
 	"path"
+	"strconv"
+	"strings"
 
+	"github.com/goMLLibrary/core/idx"
 	"github.com/goMLLibrary/core/util"
 	"gonum.org/v1/gonum/mat"
-	"github.com/petar/GoMNIST"
 )
 
-var downloadMaps = map[string]string {
-	"train-images-idx3-ubyte.gz": "http://yann.lecun.com/exdb/mnist/train-images-idx3-ubyte.gz",
-	"train-labels-idx1-ubyte.gz": "http://yann.lecun.com/exdb/mnist/train-labels-idx1-ubyte.gz",
-	"t10k-images-idx3-ubyte.gz": "http://yann.lecun.com/exdb/mnist/t10k-images-idx3-ubyte.gz",
-	"t10k-labels-idx1-ubyte.gz": "http://yann.lecun.com/exdb/mnist/t10k-labels-idx1-ubyte.gz",
+// DefaultMnistBaseURL : Mnistのデータセットのダウンロード元のデフォルトのURL
+const DefaultMnistBaseURL = "http://yann.lecun.com/exdb/mnist/"
+
+// DefaultMnistChecksums : Mnistのデータセットの各ファイルのSHA-256
+var DefaultMnistChecksums = map[string]string{
+	"train-images-idx3-ubyte.gz": "440fcabf73cc546fa21475e81ea370265605f56be210a4024d2ca8f203523609",
+	"train-labels-idx1-ubyte.gz": "3552534a0a558bbed6aed32b30c495cca23d567ec52cac8be1a0730e8010255c",
+	"t10k-images-idx3-ubyte.gz":  "8d422c7b0a1c1c79245a5bcf07fe86e33eeafee792b84584aec276f5a2dbc4e6",
+	"t10k-labels-idx1-ubyte.gz":  "f7ae60f92e00ec6debd23a6088c31dbd2371eca3ffa0defaefb259924204aec6",
+}
+
+// Loader : Mnist形式のデータセットをファイルから読み込む（ファイルが無ければダウンロードする）
+type Loader struct {
+	rootPath  string
+	source    Source
+	baseURL   string
+	localOnly bool
+	checksums map[string]string
+	client    *http.Client
+}
+
+// LoaderOption : Loaderのオプション
+type LoaderOption func(*Loader)
+
+// NewLoader : Loaderを取得
+// rootPath : データセットのファイルを格納するフォルダ
+// デフォルトではMnistのデータセットをDefaultMnistBaseURLからダウンロードし、DefaultMnistChecksumsで検証する
+// 初期化時にオプション指定が可能
+func NewLoader(rootPath string, options ...LoaderOption) *Loader {
+	l := Loader{rootPath: rootPath, source: MnistSource}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&l)
+	}
+	if l.baseURL == "" {
+		l.baseURL = l.source.BaseURL
+	}
+	if l.checksums == nil {
+		l.checksums = l.source.Checksums
+	}
+	return &l
+}
+
+// NewFashionMnistLoader : Fashion-MNISTのデータセットを読み込むLoaderを取得
+// ファイル名がMnistと同じため、rootPathにはMnistと別のフォルダを指定する
+func NewFashionMnistLoader(rootPath string, options ...LoaderOption) *Loader {
+	return NewLoader(rootPath, append([]LoaderOption{WithLoaderSource(FashionMnistSource)}, options...)...)
+}
+
+// NewKMnistLoader : KMNISTのデータセットを読み込むLoaderを取得
+// ファイル名がMnistと同じため、rootPathにはMnistと別のフォルダを指定する
+func NewKMnistLoader(rootPath string, options ...LoaderOption) *Loader {
+	return NewLoader(rootPath, append([]LoaderOption{WithLoaderSource(KMnistSource)}, options...)...)
+}
+
+// WithLoaderSource : 読み込むデータセットの取得元と構成指定のオプションを取得
+// WithLoaderMirror, WithLoaderChecksumsを指定した場合は、そちらを優先する
+func WithLoaderSource(source Source) LoaderOption {
+	return func(l *Loader) {
+		l.source = source
+	}
+}
+
+// WithLoaderLocalOnly : ダウンロードを行わず、ファイルが無い・壊れている場合はエラーとするオプションを取得
+func WithLoaderLocalOnly() LoaderOption {
+	return func(l *Loader) {
+		l.localOnly = true
+	}
+}
+
+// WithLoaderMirror : ダウンロード元のURL指定のオプションを取得（各ファイルは"baseURL/ファイル名"から取得する）
+func WithLoaderMirror(baseURL string) LoaderOption {
+	return func(l *Loader) {
+		l.baseURL = baseURL
+	}
+}
+
+// WithLoaderChecksums : 各ファイルのSHA-256指定のオプションを取得（指定の無いファイルは検証しない）
+func WithLoaderChecksums(checksums map[string]string) LoaderOption {
+	return func(l *Loader) {
+		l.checksums = checksums
+	}
+}
+
+// WithLoaderHTTPClient : ダウンロードに利用するHTTPクライアント指定のオプションを取得
+func WithLoaderHTTPClient(client *http.Client) LoaderOption {
+	return func(l *Loader) {
+		l.client = client
+	}
 }
 
 // LoadData : Mnistのデータセットを取得
-func LoadData(rootPath string) (trainSet *MnistDataSet, testSet *MnistDataSet, err error) {
-	// mnistデータがなければ、ダウンロードする
-	downloadMnistDataIfNeeded(rootPath)
+// 初期化時にオプション指定が可能（NewLoaderと同じ）
+func LoadData(rootPath string, options ...LoaderOption) (trainSet *MnistDataSet, testSet *MnistDataSet, err error) {
+	return NewLoader(rootPath, options...).Load()
+}
 
-	// train : Mnistの学習用データ
-	// test : Mnistのテスト用データ
-	train, test, err := GoMNIST.Load(rootPath)
-	if err != nil {
+// LoadFashionMnistData : Fashion-MNISTのデータセットを取得
+// 初期化時にオプション指定が可能（NewLoaderと同じ）
+func LoadFashionMnistData(rootPath string, options ...LoaderOption) (trainSet *MnistDataSet, testSet *MnistDataSet, err error) {
+	return NewFashionMnistLoader(rootPath, options...).Load()
+}
+
+// LoadKMnistData : KMNISTのデータセットを取得
+// 初期化時にオプション指定が可能（NewLoaderと同じ）
+func LoadKMnistData(rootPath string, options ...LoaderOption) (trainSet *MnistDataSet, testSet *MnistDataSet, err error) {
+	return NewKMnistLoader(rootPath, options...).Load()
+}
+
+// Load : データセットを取得
+// ファイルが無い、またはSHA-256が一致しない場合はダウンロードし直す
+func (l *Loader) Load() (trainSet *MnistDataSet, testSet *MnistDataSet, err error) {
+	if err := l.prepareFiles(); err != nil {
 		return nil, nil, err
 	}
 
-	trainDataSet := newMnistDataSet(train)
-	testDataSet := newMnistDataSet(test)
+	// train : 学習用データ
+	// test : テスト用データ
+	names := l.source.FileNames
+	trainDataSet, err := readMnistDataSet(path.Join(l.rootPath, names[0]), path.Join(l.rootPath, names[1]), l.source.ClassNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	testDataSet, err := readMnistDataSet(path.Join(l.rootPath, names[2]), path.Join(l.rootPath, names[3]), l.source.ClassNames)
+	if err != nil {
+		return nil, nil, err
+	}
 	return trainDataSet, testDataSet, nil
 }
 
+// prepareFiles : 各ファイルを検証し、必要であればダウンロードする
+func (l *Loader) prepareFiles() error {
+	if !l.localOnly {
+		if err := os.MkdirAll(l.rootPath, 0777); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range l.source.FileNames {
+		filePath := path.Join(l.rootPath, name)
+		checksum := l.checksums[name]
+		if util.Exists(filePath) {
+			err := util.VerifyFileSHA256(filePath, checksum)
+			if err == nil {
+				continue
+			}
+			if l.localOnly {
+				return err
+			}
+		} else if l.localOnly {
+			return fmt.Errorf("%sが見つかりません", filePath)
+		}
+
+		url := strings.TrimSuffix(l.baseURL, "/") + "/" + name
+		if err := util.DownloadFileWithSHA256(l.client, filePath, url, checksum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type MnistDataSet struct {
 	dataSet []MnistData
 	nCol    int
 	nRow    int
+	// classNames : ラベルの番号順のクラス名
+	classNames []string
 }
 
-func newMnistDataSet(set *GoMNIST.Set) *MnistDataSet {
-	dataSet := MnistDataSet{nCol: set.NCol, nRow: set.NRow}
-	dataSet.dataSet = make([]MnistData, 0, set.Count())
-	for i, rawData := range set.Images {
-		data := newMnistDataFromGoMNISTData(rawData, uint8(set.Labels[i]))
-		dataSet.addData(data)
+// readMnistDataSet : 画像とラベルのIDXファイル（idx3-ubyte, idx1-ubyte）からデータセットを取得
+func readMnistDataSet(imagePath string, labelPath string, classNames []string) (*MnistDataSet, error) {
+	images, err := idx.ReadFile(imagePath)
+	if err != nil {
+		return nil, err
 	}
-	return &dataSet
+	labels, err := idx.ReadFile(labelPath)
+	if err != nil {
+		return nil, err
+	}
+	dims := images.GetDims()
+	if images.GetDataType() != idx.UnsignedByte || len(dims) != 3 {
+		return nil, fmt.Errorf("%s : 画像の形式(%v, %v)がidx3-ubyteではありません", imagePath, images.GetDataType(), dims)
+	}
+	if labels.GetDataType() != idx.UnsignedByte || len(labels.GetDims()) != 1 || labels.Count() != images.Count() {
+		return nil, fmt.Errorf("%s : ラベルの形式(%v, %v)が画像とマッチしてません", labelPath, labels.GetDataType(), labels.GetDims())
+	}
+
+	dataSet := MnistDataSet{nCol: dims[2], nRow: dims[1], classNames: classNames}
+	dataSet.dataSet = make([]MnistData, 0, images.Count())
+	for i := 0; i < images.Count(); i++ {
+		label := labels.At(i)
+		if label > 9 {
+			return nil, fmt.Errorf("%s : %d番目のラベル%vが0〜9ではありません", labelPath, i, label)
+		}
+		img := image.NewGray(image.Rect(0, 0, dataSet.nCol, dataSet.nRow))
+		for j, v := range images.Item(i) {
+			img.Pix[j] = uint8(v)
+		}
+		dataSet.addData(MnistData{img, uint8(label)})
+	}
+	return &dataSet, nil
 }
 
 // ExtractRandomDataSet : 指定したmnistのデータセットからランダムに指定サイズ分だけのデータを抽出する
+// 乱数はutil.SetSeedで設定したライブラリ全体のシードから派生させる
 func ExtractRandomDataSet(rawSet *MnistDataSet, count int) *MnistDataSet {
-	dataSet := MnistDataSet{nCol: rawSet.nCol, nRow: rawSet.nRow}
+	return ExtractRandomDataSetWithRand(rawSet, count, util.NewRand())
+}
+
+// ExtractRandomDataSetWithRand : 指定した乱数生成器を利用して、mnistのデータセットからランダムに指定サイズ分だけのデータを抽出する
+// 同じデータが重複して抽出されることはない
+func ExtractRandomDataSetWithRand(rawSet *MnistDataSet, count int, rnd *rand.Rand) *MnistDataSet {
+	dataSet := MnistDataSet{nCol: rawSet.nCol, nRow: rawSet.nRow, classNames: rawSet.classNames}
 	dataSet.dataSet = make([]MnistData, 0, count)
 	if rawSet.Count() < count {
 		panic("count is not match!")
 	}
-	randomIndexs := util.RandomIntArray(rawSet.Count(), count)
+	randomIndexs := rnd.Perm(rawSet.Count())[:count]
 	for _, index := range randomIndexs {
 		dataSet.dataSet = append(dataSet.dataSet, rawSet.GetData(index))
 	}
@@ -77,6 +253,30 @@ func ConvertMatrixFromDataSet(rawSet *MnistDataSet) (x mat.Matrix, labels mat.Ma
 	return xDense, tDense
 }
 
+// GetBatch : 指定したインデックスのデータを、入力と正解データ（one-hot形式）の行列で取得する
+func (set *MnistDataSet) GetBatch(indexes []int) (x mat.Matrix, labels mat.Matrix) {
+	batch := MnistDataSet{nCol: set.nCol, nRow: set.nRow, classNames: set.classNames}
+	batch.dataSet = make([]MnistData, 0, len(indexes))
+	for _, index := range indexes {
+		batch.dataSet = append(batch.dataSet, set.GetData(index))
+	}
+	return ConvertMatrixFromDataSet(&batch)
+}
+
+// GetSample : 指定したインデックスのデータを、入力と正解データ（one-hot形式）のスライスで取得する
+func (set *MnistDataSet) GetSample(index int) (x []float64, label []float64) {
+	data := set.GetData(index)
+	x = mat.VecDenseCopyOf(data.GetImageVector()).RawVector().Data
+	label = mat.VecDenseCopyOf(data.GetLabelVector()).RawVector().Data
+	return x, label
+}
+
+// GetLabel : 指定したインデックスのデータのラベルの番号を取得する
+func (set *MnistDataSet) GetLabel(index int) int {
+	data := set.GetData(index)
+	return data.GetLabel()
+}
+
 func (set *MnistDataSet) addData(data MnistData) {
 	set.dataSet = append(set.dataSet, data)
 }
@@ -101,16 +301,24 @@ func (set *MnistDataSet) GetNRow() int {
 	return set.nRow
 }
 
+// GetClassNames : ラベルの番号順のクラス名を取得
+func (set *MnistDataSet) GetClassNames() []string {
+	return append([]string{}, set.classNames...)
+}
+
+// GetClassName : 指定したラベルのクラス名を取得（クラス名が無い場合はラベルの番号）
+func (set *MnistDataSet) GetClassName(label int) string {
+	if label < 0 || label >= len(set.classNames) {
+		return strconv.Itoa(label)
+	}
+	return set.classNames[label]
+}
+
 type MnistData struct {
 	rawImage image.Image
 	label    uint8
 }
 
-func newMnistDataFromGoMNISTData(src GoMNIST.RawImage, label uint8) MnistData {
-	data := MnistData{src, label}
-	return data
-}
-
 func (data *MnistData) GetImageVector() mat.Vector {
 	if data.rawImage.ColorModel() != color.GrayModel {
 		panic("mnist data is not gray model!")
@@ -131,25 +339,13 @@ func (data *MnistData) GetImageVector() mat.Vector {
 	return vec
 }
 
+// GetLabel : ラベルの番号を取得
+func (data *MnistData) GetLabel() int {
+	return int(data.label)
+}
+
 func (data *MnistData) GetLabelVector() mat.Vector {
 	vec := mat.NewVecDense(10, nil)
 	vec.SetVec(int(data.label), 1)
 	return vec
 }
-
-func downloadMnistDataIfNeeded(rootPath string) error {
-	// download mnist files
-	for key, v := range downloadMaps {
-		filePath := path.Join(rootPath, key)
-
-		// if mnist file is not found, download it.
-		if !util.Exists(filePath) {
-			err := util.DownloadFile(filePath, v)
-			if err != nil {
-				fmt.Printf("%s \n", err.Error())
-				return err
-			}
-		}
-	}
-	return nil
-}
@@ -1,28 +1,230 @@
 package mnist
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
 
+// TestMnistLoader : 実際の配布元からダウンロードするため、環境変数MNIST_DOWNLOAD_TESTを設定した場合のみ実行する
 func TestMnistLoader(t *testing.T) {
+	if testing.Short() || os.Getenv("MNIST_DOWNLOAD_TEST") == "" {
+		t.Skip("MNIST_DOWNLOAD_TESTが設定されていないため、ダウンロードを伴うテストをスキップします")
+	}
 	Convey("Given : Mnistのデータを取得", t, func() {
 		os.Mkdir("data", 0777)
 		train, test, err := LoadData("data")
 
-		Convey("Then : エラーが発生しないこと", func() {
+		Convey("Then : エラーが発生せず、学習データの数が60000件、テストデータの数が10000件であること", func() {
 			So(err, ShouldBeNil)
-		})
-
-		Convey("Then : 学習データの数が60000件であること. テストデータの数が10000件であること", func() {
+			if err != nil {
+				return
+			}
 			So(train.Count(), ShouldEqual, 60000)
 			So(test.Count(), ShouldEqual, 10000)
 		})
 	})
 }
 
+func TestMnistLoaderWithServer(t *testing.T) {
+	Convey("Given : 学習データ3件・テストデータ2件のMnist形式のファイルを配信するサーバーが与えられた時", t, func() {
+		files := map[string][]byte{
+			"train-images-idx3-ubyte.gz": createImageFile(3),
+			"train-labels-idx1-ubyte.gz": createLabelFile(3),
+			"t10k-images-idx3-ubyte.gz":  createImageFile(2),
+			"t10k-labels-idx1-ubyte.gz":  createLabelFile(2),
+		}
+		checksums := make(map[string]string)
+		for name, data := range files {
+			hash := sha256.Sum256(data)
+			checksums[name] = hex.EncodeToString(hash[:])
+		}
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			data, ok := files[strings.TrimPrefix(r.URL.Path, "/mnist/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		}))
+		defer server.Close()
+
+		rootPath, _ := ioutil.TempDir("", "mnist")
+		defer os.RemoveAll(rootPath)
+		options := []LoaderOption{WithLoaderMirror(server.URL + "/mnist/"), WithLoaderChecksums(checksums)}
+
+		Convey("When : ダウンロード元にサーバーを指定して読み込む", func() {
+			train, test, err := LoadData(rootPath, options...)
+			Convey("Then : ダウンロードしたファイルから読み込めること", func() {
+				So(err, ShouldBeNil)
+				So(train.Count(), ShouldEqual, 3)
+				So(test.Count(), ShouldEqual, 2)
+				So(requests, ShouldEqual, 4)
+				x, label := train.GetSample(0)
+				So(len(x), ShouldEqual, 28*28)
+				So(x[0], ShouldEqual, 128)
+				So(len(label), ShouldEqual, 10)
+			})
+			Convey("Then : 一時ファイルが残っていないこと", func() {
+				infos, _ := ioutil.ReadDir(rootPath)
+				So(len(infos), ShouldEqual, 4)
+			})
+
+			Convey("AND : ダウンロードを行わない設定で読み込み直す", nil)
+			train, _, err = LoadData(rootPath, append(options, WithLoaderLocalOnly())...)
+			Convey("Then : ダウンロードせずに読み込めること", func() {
+				So(err, ShouldBeNil)
+				So(train.Count(), ShouldEqual, 3)
+				So(requests, ShouldEqual, 4)
+			})
+		})
+
+		Convey("When : 壊れたファイルが存在する状態で読み込む", func() {
+			ioutil.WriteFile(path.Join(rootPath, "train-images-idx3-ubyte.gz"), []byte("broken"), 0644)
+			Convey("Then : ダウンロードを行わない設定ではエラーとなること", func() {
+				_, _, err := LoadData(rootPath, append(options, WithLoaderLocalOnly())...)
+				So(err, ShouldNotBeNil)
+				So(requests, ShouldEqual, 0)
+			})
+			Convey("Then : ダウンロードを行う設定ではダウンロードし直して読み込めること", func() {
+				train, _, err := LoadData(rootPath, options...)
+				So(err, ShouldBeNil)
+				So(train.Count(), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When : ダウンロードを行わない設定で、ファイルが無いフォルダから読み込む", func() {
+			_, _, err := LoadData(rootPath, append(options, WithLoaderLocalOnly())...)
+			Convey("Then : サーバーに接続せずにエラーとなること", func() {
+				So(err, ShouldNotBeNil)
+				So(requests, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When : ダウンロードしたファイルのSHA-256が一致しない", func() {
+			wrong := map[string]string{"train-images-idx3-ubyte.gz": strings.Repeat("0", 64)}
+			_, _, err := LoadData(rootPath, WithLoaderMirror(server.URL+"/mnist"), WithLoaderChecksums(wrong))
+			Convey("Then : エラーとなり、ファイルが残っていないこと", func() {
+				So(err, ShouldNotBeNil)
+				infos, _ := ioutil.ReadDir(rootPath)
+				So(len(infos), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When : ファイルが存在しないダウンロード元を指定する", func() {
+			_, _, err := LoadData(rootPath, WithLoaderMirror(server.URL+"/unknown/"), WithLoaderChecksums(checksums))
+			Convey("Then : エラーとなること", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestMnistVariantsLoaderWithServer(t *testing.T) {
+	Convey("Given : Mnist形式のファイルを配信するサーバーが与えられた時", t, func() {
+		files := map[string][]byte{
+			"train-images-idx3-ubyte.gz": createImageFile(4),
+			"train-labels-idx1-ubyte.gz": createLabelFile(4),
+			"t10k-images-idx3-ubyte.gz":  createImageFile(1),
+			"t10k-labels-idx1-ubyte.gz":  createLabelFile(1),
+		}
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			data, ok := files[strings.TrimPrefix(r.URL.Path, "/fashion/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		}))
+		defer server.Close()
+
+		rootPath, _ := ioutil.TempDir("", "mnist")
+		defer os.RemoveAll(rootPath)
+
+		Convey("When : ダウンロード元にサーバーを指定してFashion-MNISTを読み込む", func() {
+			train, test, err := LoadFashionMnistData(rootPath, WithLoaderMirror(server.URL+"/fashion/"))
+			Convey("Then : ダウンロードしたファイルから、Fashion-MNISTのクラス名付きで読み込めること", func() {
+				So(err, ShouldBeNil)
+				So(requests, ShouldEqual, 4)
+				So(train.Count(), ShouldEqual, 4)
+				So(test.Count(), ShouldEqual, 1)
+				So(train.GetClassNames(), ShouldResemble, FashionMnistSource.ClassNames)
+				data := train.GetData(3)
+				So(train.GetClassName(data.GetLabel()), ShouldEqual, "Dress")
+				So(train.GetLabel(3), ShouldEqual, data.GetLabel())
+				So(test.GetClassName(0), ShouldEqual, "T-shirt/top")
+			})
+		})
+
+		Convey("When : ファイルが存在するフォルダから、ダウンロードを行わない設定でKMNISTを読み込む", func() {
+			for name, data := range files {
+				ioutil.WriteFile(path.Join(rootPath, name), data, 0644)
+			}
+			train, _, err := LoadKMnistData(rootPath, WithLoaderLocalOnly())
+			Convey("Then : サーバーに接続せずに、KMNISTのクラス名付きで読み込めること", func() {
+				So(err, ShouldBeNil)
+				So(requests, ShouldEqual, 0)
+				So(train.Count(), ShouldEqual, 4)
+				So(train.GetClassName(1), ShouldEqual, "ki")
+				So(train.GetClassName(10), ShouldEqual, "10")
+			})
+			Convey("Then : 抽出したデータセットもクラス名を引き継ぐこと", func() {
+				extracted := ExtractRandomDataSet(train, 2)
+				So(extracted.GetClassNames(), ShouldResemble, KMnistSource.ClassNames)
+			})
+		})
+
+		Convey("When : ダウンロードを行わない設定で、ファイルが無いフォルダからFashion-MNISTを読み込む", func() {
+			_, _, err := LoadFashionMnistData(rootPath, WithLoaderLocalOnly())
+			Convey("Then : サーバーに接続せずにエラーとなること", func() {
+				So(err, ShouldNotBeNil)
+				So(requests, ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+// createImageFile : 28*28の画像count枚を、Mnist形式（gzip圧縮）のデータで作成
+func createImageFile(count int) []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, []int32{0x00000803, int32(count), 28, 28})
+	buf.Write(bytes.Repeat([]byte{128}, count*28*28))
+	return gzipBytes(buf.Bytes())
+}
+
+// createLabelFile : count件のラベルを、Mnist形式（gzip圧縮）のデータで作成
+func createLabelFile(count int) []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, []int32{0x00000801, int32(count)})
+	for i := 0; i < count; i++ {
+		buf.WriteByte(byte(i % 10))
+	}
+	return gzipBytes(buf.Bytes())
+}
+
+func gzipBytes(data []byte) []byte {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
 /*func TestMnistData(t *testing.T) {
 	train, test, err := LoadData()
 	if err != nil {
@@ -0,0 +1,53 @@
+package mnist
+
+// Source : Mnist形式（idx3-ubyteの28*28の画像とidx1-ubyteのラベル）のデータセットの取得元と構成
+type Source struct {
+	// Name : データセットの名前
+	Name string
+	// BaseURL : ダウンロード元のURL（各ファイルは"BaseURL/ファイル名"から取得する）
+	BaseURL string
+	// FileNames : 学習用画像・学習用ラベル・テスト用画像・テスト用ラベルのファイル名
+	FileNames [4]string
+	// Checksums : 各ファイルのSHA-256（指定の無いファイルは検証しない）
+	Checksums map[string]string
+	// ClassNames : ラベルの番号順のクラス名
+	ClassNames []string
+}
+
+// mnistFileNames : Mnist形式のデータセットの標準のファイル名
+var mnistFileNames = [4]string{
+	"train-images-idx3-ubyte.gz",
+	"train-labels-idx1-ubyte.gz",
+	"t10k-images-idx3-ubyte.gz",
+	"t10k-labels-idx1-ubyte.gz",
+}
+
+// MnistSource : 手書き数字のMnistのデータセット
+var MnistSource = Source{
+	Name:       "mnist",
+	BaseURL:    DefaultMnistBaseURL,
+	FileNames:  mnistFileNames,
+	Checksums:  DefaultMnistChecksums,
+	ClassNames: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"},
+}
+
+// FashionMnistSource : 衣類の画像のFashion-MNISTのデータセット
+// 配布元はSHA-256を公開していないため、デフォルトではファイルを検証しない（WithLoaderChecksumsで指定可能）
+var FashionMnistSource = Source{
+	Name:      "fashion-mnist",
+	BaseURL:   "http://fashion-mnist.s3-website.eu-central-1.amazonaws.com/",
+	FileNames: mnistFileNames,
+	ClassNames: []string{
+		"T-shirt/top", "Trouser", "Pullover", "Dress", "Coat",
+		"Sandal", "Shirt", "Sneaker", "Bag", "Ankle boot",
+	},
+}
+
+// KMnistSource : くずし字のKuzushiji-MNIST（KMNIST）のデータセット
+// 配布元はSHA-256を公開していないため、デフォルトではファイルを検証しない（WithLoaderChecksumsで指定可能）
+var KMnistSource = Source{
+	Name:       "kmnist",
+	BaseURL:    "http://codh.rois.ac.jp/kmnist/dataset/kmnist/",
+	FileNames:  mnistFileNames,
+	ClassNames: []string{"o", "ki", "su", "tsu", "na", "ha", "ma", "ya", "re", "wo"},
+}
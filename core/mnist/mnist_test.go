@@ -1,27 +1,63 @@
 package mnist
 
 import (
-	"fmt"
 	"image/color"
+	"io/ioutil"
 	"os"
+	"path"
 	"testing"
 
-	"github.com/petar/GoMNIST"
+	"github.com/goMLLibrary/core/idx"
+	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestMnistLoad(t *testing.T) {
-	os.Mkdir("data", 0777)
-	train, _, err := GoMNIST.Load("data")
-	if err != nil {
-		t.Fatalf("can't load mnist data! error detail is " + err.Error())
-	}
-
-	for _, image := range train.Images {
-		for i := 0; i < 28; i++ {
-			for j := 0; j < 28; j++ {
-				y := image.At(i, j).(color.Gray).Y
-				fmt.Printf("%d\n", y)
-			}
+	Convey("Given : 画素毎に異なる値を持つ4*3の画像2枚と、そのラベルのIDXファイル（gzip圧縮）が与えられた時", t, func() {
+		rootPath, _ := ioutil.TempDir("", "mnist")
+		defer os.RemoveAll(rootPath)
+		pixels := make([]float64, 2*3*4)
+		for i := range pixels {
+			pixels[i] = float64(i * 10)
 		}
-	}
+		imagePath := path.Join(rootPath, "images-idx3-ubyte.gz")
+		labelPath := path.Join(rootPath, "labels-idx1-ubyte.gz")
+		So(idx.WriteFile(imagePath, idx.NewTensor(idx.UnsignedByte, []int{2, 3, 4}, pixels)), ShouldBeNil)
+		So(idx.WriteFile(labelPath, idx.NewTensor(idx.UnsignedByte, []int{2}, []float64{7, 2})), ShouldBeNil)
+
+		Convey("When : idx.ReadFileで画像ファイルを読み込む", func() {
+			images, err := idx.ReadFile(imagePath)
+			Convey("Then : 書き込んだ形式と画素値が得られること", func() {
+				So(err, ShouldBeNil)
+				if err != nil {
+					return
+				}
+				So(images.GetDataType(), ShouldEqual, idx.UnsignedByte)
+				So(images.GetDims(), ShouldResemble, []int{2, 3, 4})
+				So(images.Values(), ShouldResemble, pixels)
+			})
+		})
+
+		Convey("When : Mnistのデータセットとして読み込む", func() {
+			dataSet, err := readMnistDataSet(imagePath, labelPath, nil)
+			Convey("Then : 各画像の(x, y)の画素値がIDXファイルの行y・列xの値となり、ラベルも一致すること", func() {
+				So(err, ShouldBeNil)
+				if err != nil {
+					return
+				}
+				So(dataSet.Count(), ShouldEqual, 2)
+				So(dataSet.GetNRow(), ShouldEqual, 3)
+				So(dataSet.GetNCol(), ShouldEqual, 4)
+				So(dataSet.GetData(0).label, ShouldEqual, uint8(7))
+				So(dataSet.GetData(1).label, ShouldEqual, uint8(2))
+				for i := 0; i < dataSet.Count(); i++ {
+					img := dataSet.GetData(i).rawImage
+					for y := 0; y < 3; y++ {
+						for x := 0; x < 4; x++ {
+							So(img.At(x, y).(color.Gray).Y, ShouldEqual, uint8(pixels[(i*3+y)*4+x]))
+						}
+					}
+				}
+			})
+		})
+	})
 }
@@ -9,10 +9,62 @@ const (
 	SoftmaxWithLossType
 	AffineType
 	SgdType
+	Conv2DType
+	MaxPooling2DType
+	AveragePooling2DType
+	FlattenType
+	ReshapeType
+	MomentumType
+	NesterovType
+	AdaGradType
+	RMSPropType
+	AdamType
+	AdamWType
+	BatchNormalizationType
+	DropoutType
+	MeanSquaredErrorType
+	SigmoidWithBinaryCrossEntropyType
+	HuberType
+	MultiClassHingeType
+	MinMaxScalerType
+	StandardScalerType
+	ChannelNormalizerType
+	PCAWhiteningType
 )
 
 type NNModel struct {
 	Layers []NNData
+	// InputShape : 入力データの形状（未設定の場合は0）
+	InputShape NNShape
+	// Training : 学習を再開するための進捗状況（WriteNNLayersで保存した場合は0）
+	Training NNTrainingState
+	// Transforms : 入力データの前処理（適用する順）
+	Transforms []NNData
+}
+
+// NNTrainingState : 学習を中断した時点の進捗状況
+type NNTrainingState struct {
+	// Epoch : 次に学習する0から始まるepoch数
+	Epoch int
+	// Iteration : 次に学習する0から始まる通算のバッチ番号
+	Iteration int
+	// RandomState : 学習データのシャッフルに利用する乱数の状態（HasRandomStateがfalseの場合は無効）
+	RandomState    uint64
+	HasRandomState bool
+	// DatasetRandomState : 学習データの取得時のデータ拡張などに利用する乱数の状態（HasDatasetRandomStateがfalseの場合は無効）
+	DatasetRandomState    uint64
+	HasDatasetRandomState bool
+	// SchedulerStates : NNに設定した学習率のスケジューラーの状態（未設定の場合はnil）
+	SchedulerStates map[string]float64
+	// CallbackStates : 状態を持つコールバック（EarlyStoppingなど）の状態（"コールバックのインデックス.キー"をキーとする）
+	CallbackStates map[string]float64
+}
+
+// NNShape : テンソルの形状
+type NNShape struct {
+	Channel int
+	Height  int
+	Width   int
 }
 
 func NewNNModel() *NNModel {
@@ -24,11 +76,20 @@ func NewNNModel() *NNModel {
 type NNData struct {
 	Type      LayerType
 	Parameter map[string]NNRawData
+	// Attributes : レイヤーの構成情報（フィルターサイズなど）
+	Attributes map[string]float64
+	// States : 学習対象のパラメーター以外の状態（推論用の平均・分散、Optimizerのバッファーなど）
+	States map[string]NNRawData
+	// RandomState : レイヤーが持つ乱数の状態（HasRandomStateがfalseの場合は無効）
+	RandomState    uint64
+	HasRandomState bool
 }
 
 func NewNNData() NNData {
 	data := NNData{}
 	data.Parameter = make(map[string]NNRawData)
+	data.Attributes = make(map[string]float64)
+	data.States = make(map[string]NNRawData)
 	return data
 }
 
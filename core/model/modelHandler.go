@@ -7,16 +7,50 @@ import (
 	"io/ioutil"
 
 	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/transform"
 	"gonum.org/v1/gonum/mat"
 )
 
 // WriteNNLayers : ニューラルネットワークの情報をファイルに書き出す
 func WriteNNLayers(modelPath string, nnLayers *neuralNetwork.NeuralNetworkLayers) error {
+	return WriteCheckpoint(modelPath, nnLayers, NNTrainingState{})
+}
+
+// WriteCheckpoint : ニューラルネットワークの情報を、学習を再開するための進捗状況とあわせてファイルに書き出す
+// Optimizerのハイパーパラメーター・内部状態、レイヤーの乱数の状態も保存される
+func WriteCheckpoint(modelPath string, nnLayers *neuralNetwork.NeuralNetworkLayers, state NNTrainingState) error {
+	return writeNNModel(modelPath, nnLayers, state, nil)
+}
+
+// WriteNNLayersWithTransform : ニューラルネットワークの情報を、学習データでFitした入力データの前処理とあわせてファイルに書き出す
+func WriteNNLayersWithTransform(modelPath string, nnLayers *neuralNetwork.NeuralNetworkLayers, pipeline *transform.Pipeline) error {
+	return writeNNModel(modelPath, nnLayers, NNTrainingState{}, pipeline)
+}
+
+// WriteCheckpointWithTransform : ニューラルネットワークの情報を、学習を再開するための進捗状況と入力データの前処理とあわせてファイルに書き出す
+// 保存したファイルはReadNNLayersWithTransformでも読み込めるため、推論時に同じ前処理を適用できる
+func WriteCheckpointWithTransform(modelPath string, nnLayers *neuralNetwork.NeuralNetworkLayers, state NNTrainingState, pipeline *transform.Pipeline) error {
+	return writeNNModel(modelPath, nnLayers, state, pipeline)
+}
+
+func writeNNModel(modelPath string, nnLayers *neuralNetwork.NeuralNetworkLayers, state NNTrainingState, pipeline *transform.Pipeline) error {
 	// レイヤー情報を保存用のモデル情報に書き換える
 	nnModel, err := convertNNModel(nnLayers)
 	if err != nil {
 		return err
 	}
+	nnModel.Training = state
+
+	// 前処理の統計量を保存用のモデル情報に書き換える
+	if pipeline != nil {
+		for _, t := range pipeline.GetTransformers() {
+			nnData, err := convertNNDataFromTransformer(t)
+			if err != nil {
+				return err
+			}
+			nnModel.Transforms = append(nnModel.Transforms, nnData)
+		}
+	}
 
 	// モデル情報をbyteデータに書き換え、ファイルに書き込む
 	byteData, err := encodeNNModel(nnModel)
@@ -28,24 +62,71 @@ func WriteNNLayers(modelPath string, nnLayers *neuralNetwork.NeuralNetworkLayers
 
 // ReadNNLayers : ニューラルネットワークの情報をファイルから取得する
 func ReadNNLayers(modelPath string) (*neuralNetwork.NeuralNetworkLayers, error) {
+	nnLayers, _, err := ReadCheckpoint(modelPath)
+	return nnLayers, err
+}
+
+// ReadCheckpoint : ニューラルネットワークの情報と、学習を再開するための進捗状況をファイルから取得する
+func ReadCheckpoint(modelPath string) (*neuralNetwork.NeuralNetworkLayers, NNTrainingState, error) {
+	nnModel, nnLayers, err := readNNModel(modelPath)
+	if err != nil {
+		return nil, NNTrainingState{}, err
+	}
+	return nnLayers, nnModel.Training, nil
+}
+
+// ReadNNLayersWithTransform : ニューラルネットワークの情報と、入力データの前処理をファイルから取得する
+// 前処理が保存されていない場合は、何も変換しないPipelineを返す
+func ReadNNLayersWithTransform(modelPath string) (*neuralNetwork.NeuralNetworkLayers, *transform.Pipeline, error) {
+	nnLayers, _, pipeline, err := ReadCheckpointWithTransform(modelPath)
+	return nnLayers, pipeline, err
+}
+
+// ReadCheckpointWithTransform : ニューラルネットワークの情報と、学習を再開するための進捗状況、入力データの前処理をファイルから取得する
+// 前処理が保存されていない場合は、何も変換しないPipelineを返す
+func ReadCheckpointWithTransform(modelPath string) (*neuralNetwork.NeuralNetworkLayers, NNTrainingState, *transform.Pipeline, error) {
+	nnModel, nnLayers, err := readNNModel(modelPath)
+	if err != nil {
+		return nil, NNTrainingState{}, nil, err
+	}
+
+	// モデル情報から前処理を復元する
+	pipeline := transform.NewPipeline()
+	for _, nnData := range nnModel.Transforms {
+		t, err := convertTransformerFromNNData(nnData)
+		if err != nil {
+			return nil, NNTrainingState{}, nil, err
+		}
+		pipeline.Add(t)
+	}
+	return nnLayers, nnModel.Training, pipeline, nil
+}
+
+func readNNModel(modelPath string) (*NNModel, *neuralNetwork.NeuralNetworkLayers, error) {
 	// ファイルからmodelのbyteデータを取得
 	byteData, err := readModelFile(modelPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// byteデータからモデル情報を作成
 	nnModel, err := decodeNNModel(byteData)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// モデル情報からレイヤー情報を復元する
-	return convertNNLayers(nnModel)
+	nnLayers, err := convertNNLayers(nnModel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nnModel, nnLayers, nil
 }
 
 func convertNNModel(nnLayers *neuralNetwork.NeuralNetworkLayers) (*NNModel, error) {
 	nnModel := NewNNModel()
+	shape := nnLayers.GetInputShape()
+	nnModel.InputShape = NNShape{shape.Channel, shape.Height, shape.Width}
 
 	// レイヤー情報を取得
 	for _, layer := range nnLayers.GetLayers() {
@@ -54,6 +135,26 @@ func convertNNModel(nnLayers *neuralNetwork.NeuralNetworkLayers) (*NNModel, erro
 		switch convertLayer := layer.(type) {
 		case *neuralNetwork.Affine:
 			nnData = convertNNDataFromAffine(convertLayer)
+		case *neuralNetwork.Conv2D:
+			nnData = convertNNDataFromConv2D(convertLayer)
+		case *neuralNetwork.BatchNormalization:
+			nnData = convertNNDataFromBatchNormalization(convertLayer)
+		case *neuralNetwork.MaxPooling2D:
+			nnData = convertNNDataFromPooling2D(MaxPooling2DType, convertLayer)
+		case *neuralNetwork.AveragePooling2D:
+			nnData = convertNNDataFromPooling2D(AveragePooling2DType, convertLayer)
+		case *neuralNetwork.Flatten:
+			nnData.Type = FlattenType
+		case *neuralNetwork.Reshape:
+			nnData.Type = ReshapeType
+			setShapeAttributes(nnData.Attributes, "output", convertLayer.GetShape())
+		case *neuralNetwork.Dropout:
+			nnData.Type = DropoutType
+			nnData.Attributes["ratio"] = convertLayer.GetRatio()
+			if convertLayer.IsInverted() {
+				nnData.Attributes["inverted"] = 1
+			}
+			nnData.RandomState, nnData.HasRandomState = convertLayer.GetRandomState()
 		case *neuralNetwork.Tanh:
 			nnData.Type = TanhType
 		case *neuralNetwork.Relu:
@@ -69,17 +170,28 @@ func convertNNModel(nnLayers *neuralNetwork.NeuralNetworkLayers) (*NNModel, erro
 
 	// 最終のレイヤーを設定
 	nnData := NewNNData()
-	nnData.Type = SoftmaxWithLossType
+	switch lastLayer := nnLayers.GetLastActivationLayer().(type) {
+	case *neuralNetwork.SoftmaxWithLoss:
+		nnData.Type = SoftmaxWithLossType
+	case *neuralNetwork.MeanSquaredError:
+		nnData.Type = MeanSquaredErrorType
+	case *neuralNetwork.SigmoidWithBinaryCrossEntropy:
+		nnData.Type = SigmoidWithBinaryCrossEntropyType
+	case *neuralNetwork.Huber:
+		nnData.Type = HuberType
+		nnData.Attributes["delta"] = lastLayer.GetDelta()
+	case *neuralNetwork.MultiClassHinge:
+		nnData.Type = MultiClassHingeType
+		nnData.Attributes["margin"] = lastLayer.GetMargin()
+	default:
+		return nil, errors.New("意図しない最終レイヤーが指定されています.")
+	}
 	nnModel.Layers = append(nnModel.Layers, nnData)
 
 	// Optimizerを設定
-	nnData = NewNNData()
-	optimizer := nnLayers.GetOptimizer()
-	switch optimizer.(type) {
-	case *neuralNetwork.SGD:
-		nnData.Type = SgdType
-	default:
-		return nil, errors.New("意図しないoptimizerが指定されています.")
+	nnData, err := convertNNDataFromOptimizer(nnLayers.GetOptimizer())
+	if err != nil {
+		return nil, err
 	}
 	nnModel.Layers = append(nnModel.Layers, nnData)
 
@@ -88,23 +200,64 @@ func convertNNModel(nnLayers *neuralNetwork.NeuralNetworkLayers) (*NNModel, erro
 
 func convertNNLayers(model *NNModel) (*neuralNetwork.NeuralNetworkLayers, error) {
 	nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+	shape := neuralNetwork.NewShape(model.InputShape.Channel, model.InputShape.Height, model.InputShape.Width)
+	if !shape.IsEmpty() {
+		nnLayers.SetInputShape(shape)
+	}
 
 	for _, nnData := range model.Layers {
 		switch nnData.Type {
-		case SgdType:
-			// TODO : SGDのパラメーターを設定できるように対応
-			nnLayers.SetOptimizer(neuralNetwork.NewSGD())
+		case SgdType, MomentumType, NesterovType, AdaGradType, RMSPropType, AdamType, AdamWType:
+			nnLayers.SetOptimizer(convertOptimizerFromNNData(nnData))
 		case SoftmaxWithLossType:
 			nnLayers.SetLastActivationLayer(neuralNetwork.NewSoftmaxWithLoss())
+		case MeanSquaredErrorType:
+			nnLayers.SetLastActivationLayer(neuralNetwork.NewMeanSquaredError())
+		case SigmoidWithBinaryCrossEntropyType:
+			nnLayers.SetLastActivationLayer(neuralNetwork.NewSigmoidWithBinaryCrossEntropy())
+		case HuberType:
+			nnLayers.SetLastActivationLayer(neuralNetwork.NewHuber(neuralNetwork.WithHuberDelta(nnData.Attributes["delta"])))
+		case MultiClassHingeType:
+			nnLayers.SetLastActivationLayer(neuralNetwork.NewMultiClassHinge(neuralNetwork.WithMultiClassHingeMargin(nnData.Attributes["margin"])))
 		case SigmoidType:
 			nnLayers.Add(neuralNetwork.NewSigmoid())
 		case ReluType:
 			nnLayers.Add(neuralNetwork.NewRelu())
 		case TanhType:
 			nnLayers.Add(neuralNetwork.NewTanh())
+		case DropoutType:
+			dropout := neuralNetwork.NewDropout(nnData.Attributes["ratio"])
+			if nnData.Attributes["inverted"] == 1 {
+				dropout = neuralNetwork.NewInvertedDropout(nnData.Attributes["ratio"])
+			}
+			if nnData.HasRandomState {
+				dropout.SetRandomState(nnData.RandomState)
+			}
+			nnLayers.Add(dropout)
+		case FlattenType:
+			nnLayers.Add(neuralNetwork.NewFlatten())
+		case ReshapeType:
+			nnLayers.Add(neuralNetwork.NewReshape(convertShapeFromAttributes(nnData.Attributes, "output")))
 		case AffineType:
 			affine := convertAffineFromNNData(nnData)
 			nnLayers.Add(affine)
+		case Conv2DType:
+			conv := convertConv2DFromNNData(nnData)
+			nnLayers.Add(conv)
+		case BatchNormalizationType:
+			bn := neuralNetwork.NewBatchNormalization(
+				neuralNetwork.WithBatchNormalizationMomentum(nnData.Attributes["momentum"]),
+				neuralNetwork.WithBatchNormalizationEpsilon(nnData.Attributes["epsilon"]),
+			)
+			nnLayers.Add(bn)
+			bn.UpdateParams(convertMatrixMap(nnData.Parameter))
+			bn.SetStates(convertMatrixMap(nnData.States))
+		case MaxPooling2DType:
+			ph, pw, options := convertPooling2DConfigFromNNData(nnData)
+			nnLayers.Add(neuralNetwork.NewMaxPooling2D(ph, pw, options...))
+		case AveragePooling2DType:
+			ph, pw, options := convertPooling2DConfigFromNNData(nnData)
+			nnLayers.Add(neuralNetwork.NewAveragePooling2D(ph, pw, options...))
 		default:
 			return nil, errors.New("意図しないレイヤータイプが保存されています")
 		}
@@ -132,19 +285,274 @@ func convertNNDataFromAffine(affine *neuralNetwork.Affine) NNData {
 }
 
 func convertAffineFromNNData(data NNData) *neuralNetwork.Affine {
-	weight := data.Parameter["w"]
-	affine := neuralNetwork.NewAffine(weight.Row, weight.Col)
-
 	// wの設定
-	params := make(map[string]mat.Matrix)
-	params["w"] = mat.NewDense(weight.Row, weight.Col, weight.RawData)
+	weight := data.Parameter["w"]
+	w := mat.NewDense(weight.Row, weight.Col, weight.RawData)
 
 	// bの設定
 	bias := data.Parameter["b"]
-	params["b"] = mat.NewDense(bias.Row, bias.Col, bias.RawData)
+	b := mat.NewVecDense(len(bias.RawData), bias.RawData)
+
+	return neuralNetwork.NewAffineWithParams(w, b)
+}
 
-	affine.UpdateParams(params)
-	return affine
+func convertNNDataFromConv2D(conv *neuralNetwork.Conv2D) NNData {
+	nnData := NewNNData()
+	nnData.Type = Conv2DType
+	nnData.Parameter = convertNNRawDataMap(conv.GetParams())
+
+	// 構成情報の設定
+	fn, fh, fw := conv.GetFilterSize()
+	setShapeAttributes(nnData.Attributes, "input", conv.GetInputShape())
+	nnData.Attributes["filterNum"] = float64(fn)
+	nnData.Attributes["filterHeight"] = float64(fh)
+	nnData.Attributes["filterWidth"] = float64(fw)
+	nnData.Attributes["stride"] = float64(conv.GetStride())
+	nnData.Attributes["padding"] = float64(conv.GetPadding())
+	return nnData
+}
+
+func convertConv2DFromNNData(data NNData) *neuralNetwork.Conv2D {
+	attr := data.Attributes
+	params := convertMatrixMap(data.Parameter)
+	return neuralNetwork.NewConv2DWithParams(
+		params["w"], mat.DenseCopyOf(params["b"]).ColView(0),
+		int(attr["filterHeight"]), int(attr["filterWidth"]),
+		neuralNetwork.WithConv2DStride(int(attr["stride"])),
+		neuralNetwork.WithConv2DPadding(int(attr["padding"])),
+		neuralNetwork.WithConv2DInputShape(convertShapeFromAttributes(attr, "input")),
+	)
+}
+
+func convertNNDataFromBatchNormalization(bn *neuralNetwork.BatchNormalization) NNData {
+	nnData := NewNNData()
+	nnData.Type = BatchNormalizationType
+	nnData.Parameter = convertNNRawDataMap(bn.GetParams())
+	nnData.States = convertNNRawDataMap(bn.GetStates())
+	nnData.Attributes["momentum"] = bn.GetMomentum()
+	nnData.Attributes["epsilon"] = bn.GetEpsilon()
+	return nnData
+}
+
+func convertNNDataFromOptimizer(optimizer neuralNetwork.Optimizer) (NNData, error) {
+	nnData := NewNNData()
+	nnData.Attributes["lr"] = optimizer.GetLearningRate()
+
+	// ハイパーパラメーターの設定
+	switch convertOptimizer := optimizer.(type) {
+	case *neuralNetwork.SGD:
+		nnData.Type = SgdType
+	case *neuralNetwork.Momentum:
+		nnData.Type = MomentumType
+		nnData.Attributes["momentum"] = convertOptimizer.GetCoefficient()
+	case *neuralNetwork.Nesterov:
+		nnData.Type = NesterovType
+		nnData.Attributes["momentum"] = convertOptimizer.GetCoefficient()
+	case *neuralNetwork.AdaGrad:
+		nnData.Type = AdaGradType
+		nnData.Attributes["epsilon"] = convertOptimizer.GetEpsilon()
+	case *neuralNetwork.RMSProp:
+		nnData.Type = RMSPropType
+		nnData.Attributes["decayRate"] = convertOptimizer.GetDecayRate()
+		nnData.Attributes["epsilon"] = convertOptimizer.GetEpsilon()
+	case *neuralNetwork.Adam:
+		nnData.Type = AdamType
+		nnData.Attributes["beta1"] = convertOptimizer.GetBeta1()
+		nnData.Attributes["beta2"] = convertOptimizer.GetBeta2()
+		nnData.Attributes["epsilon"] = convertOptimizer.GetEpsilon()
+	case *neuralNetwork.AdamW:
+		nnData.Type = AdamWType
+		nnData.Attributes["beta1"] = convertOptimizer.GetBeta1()
+		nnData.Attributes["beta2"] = convertOptimizer.GetBeta2()
+		nnData.Attributes["epsilon"] = convertOptimizer.GetEpsilon()
+		nnData.Attributes["weightDecay"] = convertOptimizer.GetWeightDecay()
+	default:
+		return nnData, errors.New("意図しないoptimizerが指定されています.")
+	}
+
+	// 速度・モーメントなどの内部状態の設定
+	if stateful, ok := optimizer.(neuralNetwork.StatefulOptimizer); ok {
+		nnData.States = convertNNRawDataMap(stateful.GetStates())
+	}
+	return nnData, nil
+}
+
+// convertOptimizerFromNNData : 保存用のデータからOptimizerを復元する
+// ハイパーパラメーターが保存されていない古い形式の場合は、デフォルト値を利用する
+func convertOptimizerFromNNData(data NNData) neuralNetwork.Optimizer {
+	attr := data.Attributes
+	var optimizer neuralNetwork.Optimizer
+	switch data.Type {
+	case SgdType:
+		optimizer = neuralNetwork.NewSGD(
+			neuralNetwork.WithSGDLearningRate(getAttribute(attr, "lr", neuralNetwork.DefaultLearningRate)))
+	case MomentumType:
+		optimizer = neuralNetwork.NewMomentum(
+			neuralNetwork.WithMomentumLearningRate(getAttribute(attr, "lr", neuralNetwork.DefaultLearningRate)),
+			neuralNetwork.WithMomentumCoefficient(getAttribute(attr, "momentum", neuralNetwork.DefaultMomentum)))
+	case NesterovType:
+		optimizer = neuralNetwork.NewNesterov(
+			neuralNetwork.WithNesterovLearningRate(getAttribute(attr, "lr", neuralNetwork.DefaultLearningRate)),
+			neuralNetwork.WithNesterovCoefficient(getAttribute(attr, "momentum", neuralNetwork.DefaultMomentum)))
+	case AdaGradType:
+		optimizer = neuralNetwork.NewAdaGrad(
+			neuralNetwork.WithAdaGradLearningRate(getAttribute(attr, "lr", neuralNetwork.DefaultLearningRate)),
+			neuralNetwork.WithAdaGradEpsilon(getAttribute(attr, "epsilon", neuralNetwork.DefaultEpsilon)))
+	case RMSPropType:
+		optimizer = neuralNetwork.NewRMSProp(
+			neuralNetwork.WithRMSPropLearningRate(getAttribute(attr, "lr", neuralNetwork.DefaultAdaptiveLearningRate)),
+			neuralNetwork.WithRMSPropDecayRate(getAttribute(attr, "decayRate", neuralNetwork.DefaultRMSPropDecayRate)),
+			neuralNetwork.WithRMSPropEpsilon(getAttribute(attr, "epsilon", neuralNetwork.DefaultEpsilon)))
+	case AdamType:
+		optimizer = neuralNetwork.NewAdam(
+			neuralNetwork.WithAdamLearningRate(getAttribute(attr, "lr", neuralNetwork.DefaultAdaptiveLearningRate)),
+			neuralNetwork.WithAdamBeta1(getAttribute(attr, "beta1", neuralNetwork.DefaultAdamBeta1)),
+			neuralNetwork.WithAdamBeta2(getAttribute(attr, "beta2", neuralNetwork.DefaultAdamBeta2)),
+			neuralNetwork.WithAdamEpsilon(getAttribute(attr, "epsilon", neuralNetwork.DefaultEpsilon)))
+	case AdamWType:
+		optimizer = neuralNetwork.NewAdamW(
+			neuralNetwork.WithAdamWLearningRate(getAttribute(attr, "lr", neuralNetwork.DefaultAdaptiveLearningRate)),
+			neuralNetwork.WithAdamWBeta1(getAttribute(attr, "beta1", neuralNetwork.DefaultAdamBeta1)),
+			neuralNetwork.WithAdamWBeta2(getAttribute(attr, "beta2", neuralNetwork.DefaultAdamBeta2)),
+			neuralNetwork.WithAdamWEpsilon(getAttribute(attr, "epsilon", neuralNetwork.DefaultEpsilon)),
+			neuralNetwork.WithAdamWWeightDecay(getAttribute(attr, "weightDecay", neuralNetwork.DefaultAdamWWeightDecay)))
+	}
+
+	if stateful, ok := optimizer.(neuralNetwork.StatefulOptimizer); ok && len(data.States) > 0 {
+		stateful.SetStates(convertMatrixMap(data.States))
+	}
+	return optimizer
+}
+
+// convertNNDataFromTransformer : Fit済みの前処理を保存用のデータに変換する
+func convertNNDataFromTransformer(t transform.Transformer) (NNData, error) {
+	nnData := NewNNData()
+	if !t.IsFitted() {
+		return nnData, errors.New("Fitしていない前処理は保存できません.")
+	}
+
+	// 構成情報の設定
+	switch convertTransformer := t.(type) {
+	case *transform.MinMaxScaler:
+		nnData.Type = MinMaxScalerType
+		nnData.Attributes["rangeMin"], nnData.Attributes["rangeMax"] = convertTransformer.GetRange()
+	case *transform.StandardScaler:
+		nnData.Type = StandardScalerType
+	case *transform.ChannelNormalizer:
+		nnData.Type = ChannelNormalizerType
+		setShapeAttributes(nnData.Attributes, "input", convertTransformer.GetShape())
+	case *transform.PCAWhitening:
+		nnData.Type = PCAWhiteningType
+		nnData.Attributes["epsilon"] = convertTransformer.GetEpsilon()
+		nnData.Attributes["components"] = float64(convertTransformer.GetComponents())
+		if convertTransformer.IsZCA() {
+			nnData.Attributes["zca"] = 1
+		}
+	default:
+		return nnData, errors.New("意図しない前処理が指定されています.")
+	}
+
+	// 統計量の設定
+	nnData.States = convertNNRawDataMap(t.GetStates())
+	return nnData, nil
+}
+
+// convertTransformerFromNNData : 保存用のデータからFit済みの前処理を復元する
+func convertTransformerFromNNData(data NNData) (transform.Transformer, error) {
+	attr := data.Attributes
+	var t transform.Transformer
+	switch data.Type {
+	case MinMaxScalerType:
+		t = transform.NewMinMaxScaler(transform.WithMinMaxScalerRange(attr["rangeMin"], attr["rangeMax"]))
+	case StandardScalerType:
+		t = transform.NewStandardScaler()
+	case ChannelNormalizerType:
+		t = transform.NewChannelNormalizer(convertShapeFromAttributes(attr, "input"))
+	case PCAWhiteningType:
+		options := []transform.PCAWhiteningOption{transform.WithPCAWhiteningEpsilon(attr["epsilon"])}
+		if components := int(attr["components"]); components > 0 {
+			options = append(options, transform.WithPCAWhiteningComponents(components))
+		}
+		if attr["zca"] == 1 {
+			options = append(options, transform.WithPCAWhiteningZCA())
+		}
+		t = transform.NewPCAWhitening(options...)
+	default:
+		return nil, errors.New("意図しない前処理のデータです.")
+	}
+	t.SetStates(convertMatrixMap(data.States))
+	return t, nil
+}
+
+// getAttribute : 構成情報を取得する. 保存されていない場合はdefaultValueを返す
+func getAttribute(attributes map[string]float64, key string, defaultValue float64) float64 {
+	if value, ok := attributes[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+// pooling2DLayer : プーリングレイヤーの構成情報を取得するIF
+type pooling2DLayer interface {
+	GetInputShape() neuralNetwork.Shape
+	GetPoolSize() (height int, width int)
+	GetStride() int
+	GetPadding() int
+}
+
+func convertNNDataFromPooling2D(layerType LayerType, pool pooling2DLayer) NNData {
+	nnData := NewNNData()
+	nnData.Type = layerType
+
+	// 構成情報の設定
+	ph, pw := pool.GetPoolSize()
+	setShapeAttributes(nnData.Attributes, "input", pool.GetInputShape())
+	nnData.Attributes["poolHeight"] = float64(ph)
+	nnData.Attributes["poolWidth"] = float64(pw)
+	nnData.Attributes["stride"] = float64(pool.GetStride())
+	nnData.Attributes["padding"] = float64(pool.GetPadding())
+	return nnData
+}
+
+func convertPooling2DConfigFromNNData(data NNData) (ph, pw int, options []neuralNetwork.Pooling2DOption) {
+	attr := data.Attributes
+	options = []neuralNetwork.Pooling2DOption{
+		neuralNetwork.WithPooling2DStride(int(attr["stride"])),
+		neuralNetwork.WithPooling2DPadding(int(attr["padding"])),
+		neuralNetwork.WithPooling2DInputShape(convertShapeFromAttributes(attr, "input")),
+	}
+	return int(attr["poolHeight"]), int(attr["poolWidth"]), options
+}
+
+// setShapeAttributes : 形状を構成情報に設定する. prefixは構成情報のキーの接頭辞
+func setShapeAttributes(attributes map[string]float64, prefix string, shape neuralNetwork.Shape) {
+	attributes[prefix+"Channel"] = float64(shape.Channel)
+	attributes[prefix+"Height"] = float64(shape.Height)
+	attributes[prefix+"Width"] = float64(shape.Width)
+}
+
+// convertShapeFromAttributes : 構成情報から形状を取得する. prefixは構成情報のキーの接頭辞
+func convertShapeFromAttributes(attributes map[string]float64, prefix string) neuralNetwork.Shape {
+	return neuralNetwork.NewShape(int(attributes[prefix+"Channel"]), int(attributes[prefix+"Height"]), int(attributes[prefix+"Width"]))
+}
+
+// convertNNRawDataMap : パラメーターの行列を保存用のデータに変換する
+func convertNNRawDataMap(params map[string]mat.Matrix) map[string]NNRawData {
+	rawDataMap := make(map[string]NNRawData, len(params))
+	for key, param := range params {
+		r, c := param.Dims()
+		rawDataMap[key] = NNRawData{r, c, mat.DenseCopyOf(param).RawMatrix().Data}
+	}
+	return rawDataMap
+}
+
+// convertMatrixMap : 保存用のデータをパラメーターの行列に変換する
+func convertMatrixMap(rawDataMap map[string]NNRawData) map[string]mat.Matrix {
+	params := make(map[string]mat.Matrix, len(rawDataMap))
+	for key, rawData := range rawDataMap {
+		params[key] = mat.NewDense(rawData.Row, rawData.Col, rawData.RawData)
+	}
+	return params
 }
 
 func encodeNNModel(model *NNModel) ([]byte, error) {
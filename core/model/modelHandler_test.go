@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/transform"
 	"github.com/goMLLibrary/core/util"
 	. "github.com/smartystreets/goconvey/convey"
 	"gonum.org/v1/gonum/mat"
@@ -99,3 +100,346 @@ func TestModelHandler(t *testing.T) {
 		})
 	})
 }
+
+func TestModelHandlerConv2D(t *testing.T) {
+	Convey("Given : 畳み込みレイヤーを含むニューラルネットワークの情報が与えられた時", t, func() {
+		nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+
+		modelPath := "model_conv.db"
+		defer os.Remove(modelPath)
+
+		Convey("AND : 入力データの形状は1チャネル・4*4の画像とする", nil)
+		nnLayers.SetInputShape(neuralNetwork.NewShape(1, 4, 4))
+		Convey("AND : 2個の3*3フィルター（パディング1）で畳み込みを行う", nil)
+		nnLayers.Add(neuralNetwork.NewConv2D(2, 3, 3, neuralNetwork.WithConv2DPadding(1)))
+		Convey("AND : チャネル毎のBatchNormalizationを行う", nil)
+		nnLayers.Add(neuralNetwork.NewBatchNormalization(neuralNetwork.WithBatchNormalizationMomentum(0.5)))
+		nnLayers.Add(neuralNetwork.NewRelu())
+		Convey("AND : 2*2の最大値プーリング、平均値プーリングを行う", nil)
+		nnLayers.Add(neuralNetwork.NewMaxPooling2D(2, 2))
+		nnLayers.Add(neuralNetwork.NewAveragePooling2D(2, 2, neuralNetwork.WithPooling2DStride(1)))
+		Convey("AND : Flatten, Inverted Dropoutの後、出力サイズ3のAffineで変換する", nil)
+		nnLayers.Add(neuralNetwork.NewFlatten())
+		nnLayers.Add(neuralNetwork.NewInvertedDropout(0.25))
+		nnLayers.Add(neuralNetwork.NewAffineWithOutputSize(3))
+
+		Convey("AND : 学習時のForward処理を行い、BatchNormalizationの推論用の平均・分散を更新する", nil)
+		nnLayers.Forward(mat.NewDense(2, 16, util.CreateFloatArrayByStep(32, 0, 0.3)), mat.NewDense(2, 3, []float64{0, 1, 0, 1, 0, 0}))
+
+		Convey("When : NNの情報を保存し、復元する", func() {
+			err := WriteNNLayers(modelPath, nnLayers)
+			So(err, ShouldBeNil)
+			reLayers, err := ReadNNLayers(modelPath)
+			So(err, ShouldBeNil)
+
+			Convey("Then : 畳み込みレイヤーの構成とパラメーターが復元前と同一であること", func() {
+				bConv := nnLayers.GetLayers()[0].(*neuralNetwork.Conv2D)
+				aConv, ok := reLayers.GetLayers()[0].(*neuralNetwork.Conv2D)
+				So(ok, ShouldBeTrue)
+				So(aConv.GetPadding(), ShouldEqual, bConv.GetPadding())
+				So(aConv.GetStride(), ShouldEqual, bConv.GetStride())
+				So(mat.Equal(aConv.GetParams()["w"], bConv.GetParams()["w"]), ShouldBeTrue)
+				So(mat.Equal(aConv.GetParams()["b"], bConv.GetParams()["b"]), ShouldBeTrue)
+			})
+
+			Convey("Then : プーリングレイヤーの構成が復元前と同一であること", func() {
+				aPool, ok := reLayers.GetLayers()[4].(*neuralNetwork.AveragePooling2D)
+				So(ok, ShouldBeTrue)
+				So(aPool.GetStride(), ShouldEqual, 1)
+				So(aPool.GetOutputShape(), ShouldResemble, neuralNetwork.NewShape(2, 1, 1))
+			})
+
+			Convey("Then : 入力データの形状が復元前と同一であること", func() {
+				So(reLayers.GetInputShape(), ShouldResemble, nnLayers.GetInputShape())
+				So(reLayers.GetOutputShape(), ShouldResemble, neuralNetwork.NewFlatShape(3))
+			})
+
+			Convey("Then : BatchNormalizationのパラメーター・推論用の平均・分散が復元前と同一であること", func() {
+				bBn := nnLayers.GetLayers()[1].(*neuralNetwork.BatchNormalization)
+				aBn, ok := reLayers.GetLayers()[1].(*neuralNetwork.BatchNormalization)
+				So(ok, ShouldBeTrue)
+				So(aBn.GetMomentum(), ShouldEqual, 0.5)
+				So(mat.Equal(aBn.GetParams()["gamma"], bBn.GetParams()["gamma"]), ShouldBeTrue)
+				So(mat.Equal(aBn.GetStates()["runningMean"], bBn.GetStates()["runningMean"]), ShouldBeTrue)
+				So(mat.Equal(aBn.GetStates()["runningVar"], bBn.GetStates()["runningVar"]), ShouldBeTrue)
+			})
+
+			Convey("Then : Dropoutの構成が復元前と同一であること", func() {
+				aDropout, ok := reLayers.GetLayers()[6].(*neuralNetwork.Dropout)
+				So(ok, ShouldBeTrue)
+				So(aDropout.GetRatio(), ShouldEqual, 0.25)
+				So(aDropout.IsInverted(), ShouldBeTrue)
+			})
+
+			Convey("Then : 復元したNNと復元前のNNで推論時に同一結果が出ること", func() {
+				nnLayers.SetEvaluationMode()
+				reLayers.SetEvaluationMode()
+				input := mat.NewDense(2, 16, util.CreateFloatArrayByStep(32, -1, 0.1))
+				t := mat.NewDense(2, 3, []float64{1, 0, 0, 0, 0, 1})
+				bLoss, bAcc := nnLayers.Forward(input, t)
+				aLoss, aAcc := reLayers.Forward(input, t)
+				So(bLoss, ShouldEqual, aLoss)
+				So(bAcc, ShouldEqual, aAcc)
+			})
+		})
+	})
+
+	Convey("Given : 畳み込みレイヤーとAffineのみのニューラルネットワークの情報が保存されている時", t, func() {
+		nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+		nnLayers.SetInputShape(neuralNetwork.NewShape(1, 4, 4))
+		nnLayers.Add(neuralNetwork.NewConv2D(2, 3, 3))
+		nnLayers.Add(neuralNetwork.NewFlatten())
+		nnLayers.Add(neuralNetwork.NewAffineWithOutputSize(3))
+		modelPath := "model_conv_seed.db"
+		defer os.Remove(modelPath)
+		So(WriteNNLayers(modelPath, nnLayers), ShouldBeNil)
+
+		Convey("When : シードを設定してからNNを復元する", func() {
+			util.SetSeed(7)
+			reLayers, err := ReadNNLayers(modelPath)
+			So(err, ShouldBeNil)
+			next := util.NewRand().Int63()
+			util.SetSeed(7)
+			Convey("Then : 復元によってライブラリ全体の乱数が進まず、パラメーターが復元前と同一であること", func() {
+				So(next, ShouldEqual, util.NewRand().Int63())
+				for _, i := range []int{0, 2} {
+					bParams := nnLayers.GetLayers()[i].(neuralNetwork.NeuralNetworkLayer).GetParams()
+					aParams := reLayers.GetLayers()[i].(neuralNetwork.NeuralNetworkLayer).GetParams()
+					So(mat.Equal(aParams["w"], bParams["w"]), ShouldBeTrue)
+					So(mat.Equal(aParams["b"], bParams["b"]), ShouldBeTrue)
+				}
+			})
+		})
+	})
+}
+
+func TestModelHandlerLossLayer(t *testing.T) {
+	Convey("Given : 最終層をdelta2.5のHuberにしたニューラルネットワークの情報が与えられた時", t, func() {
+		nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+		nnLayers.Add(neuralNetwork.NewAffine(3, 2))
+		nnLayers.SetLastActivationLayer(neuralNetwork.NewHuber(neuralNetwork.WithHuberDelta(2.5)))
+
+		modelPath := "model_loss.db"
+		defer os.Remove(modelPath)
+
+		Convey("When : NNの情報を保存し、復元する", func() {
+			err := WriteNNLayers(modelPath, nnLayers)
+			So(err, ShouldBeNil)
+			reLayers, err := ReadNNLayers(modelPath)
+			So(err, ShouldBeNil)
+
+			Convey("Then : 最終層の種類と構成が復元前と同一であること", func() {
+				huber, ok := reLayers.GetLastActivationLayer().(*neuralNetwork.Huber)
+				So(ok, ShouldBeTrue)
+				So(huber.GetDelta(), ShouldEqual, 2.5)
+			})
+		})
+	})
+}
+
+func TestModelHandlerTransform(t *testing.T) {
+	Convey("Given : 学習データでFitした前処理とニューラルネットワークの情報が与えられた時", t, func() {
+		nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+		nnLayers.Add(neuralNetwork.NewAffine(4, 2))
+		x := mat.NewDense(5, 4, []float64{
+			1, 2, 0, 10,
+			3, 1, 5, 20,
+			2, 7, 1, 10,
+			8, 4, 2, 30,
+			5, 3, 9, 50,
+		})
+		pipeline := transform.NewPipeline(
+			transform.NewMinMaxScaler(transform.WithMinMaxScalerRange(-1, 1)),
+			transform.NewStandardScaler(),
+			transform.NewChannelNormalizer(neuralNetwork.NewShape(2, 1, 2)),
+			transform.NewPCAWhitening(transform.WithPCAWhiteningZCA(), transform.WithPCAWhiteningEpsilon(0.1)),
+		)
+		expected := pipeline.FitTransform(x)
+
+		modelPath := "model_transform.db"
+		defer os.Remove(modelPath)
+
+		Convey("When : 前処理とあわせて保存し、復元する", func() {
+			err := WriteNNLayersWithTransform(modelPath, nnLayers, pipeline)
+			So(err, ShouldBeNil)
+			reLayers, rePipeline, err := ReadNNLayersWithTransform(modelPath)
+			So(err, ShouldBeNil)
+
+			Convey("Then : 前処理の構成と変換結果が復元前と同一であること", func() {
+				So(len(reLayers.GetLayers()), ShouldEqual, 1)
+				So(len(rePipeline.GetTransformers()), ShouldEqual, 4)
+				min, max := rePipeline.GetTransformers()[0].(*transform.MinMaxScaler).GetRange()
+				So(min, ShouldEqual, -1)
+				So(max, ShouldEqual, 1)
+				pca := rePipeline.GetTransformers()[3].(*transform.PCAWhitening)
+				So(pca.IsZCA(), ShouldBeTrue)
+				So(pca.GetEpsilon(), ShouldEqual, 0.1)
+				So(mat.EqualApprox(rePipeline.Transform(x), expected, 1e-12), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 進捗状況と前処理をあわせて保存し、復元する", func() {
+			state := NNTrainingState{Epoch: 2, Iteration: 6}
+			err := WriteCheckpointWithTransform(modelPath, nnLayers, state, pipeline)
+			So(err, ShouldBeNil)
+			_, reState, rePipeline, err := ReadCheckpointWithTransform(modelPath)
+			So(err, ShouldBeNil)
+			_, inferencePipeline, err := ReadNNLayersWithTransform(modelPath)
+			So(err, ShouldBeNil)
+
+			Convey("Then : 進捗状況と前処理の変換結果が復元前と同一であり、推論用の読み込みでも前処理が復元されること", func() {
+				So(reState, ShouldResemble, state)
+				So(mat.EqualApprox(rePipeline.Transform(x), expected, 1e-12), ShouldBeTrue)
+				So(mat.EqualApprox(inferencePipeline.Transform(x), expected, 1e-12), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 前処理を含まないファイルから前処理を復元する", func() {
+			So(WriteNNLayers(modelPath, nnLayers), ShouldBeNil)
+			_, rePipeline, err := ReadNNLayersWithTransform(modelPath)
+			So(err, ShouldBeNil)
+
+			Convey("Then : 何も変換しない前処理となること", func() {
+				So(mat.Equal(rePipeline.Transform(x), x), ShouldBeTrue)
+			})
+		})
+
+		Convey("Then : Fitしていない前処理は保存できないこと", func() {
+			err := WriteNNLayersWithTransform(modelPath, nnLayers, transform.NewPipeline(transform.NewStandardScaler()))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestModelHandlerCheckpoint(t *testing.T) {
+	Convey("Given : Dropoutを含み、AdamWで2回学習したニューラルネットワークの情報が与えられた時", t, func() {
+		util.SetSeed(5)
+		nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+		nnLayers.Add(neuralNetwork.NewAffine(4, 3))
+		nnLayers.Add(neuralNetwork.NewInvertedDropout(0.5))
+		optimizer := neuralNetwork.NewAdamW(neuralNetwork.WithAdamWLearningRate(0.05), neuralNetwork.WithAdamWBeta1(0.8),
+			neuralNetwork.WithAdamWWeightDecay(0.2))
+		nnLayers.SetOptimizer(optimizer)
+
+		x := mat.NewDense(2, 4, util.CreateFloatArrayByStep(8, -1, 0.25))
+		label := mat.NewDense(2, 3, []float64{1, 0, 0, 0, 0, 1})
+		step := func(nnl *neuralNetwork.NeuralNetworkLayers) float64 {
+			loss, _ := nnl.Forward(x, label)
+			nnl.Backward()
+			nnl.Update()
+			return loss
+		}
+		for i := 0; i < 2; i++ {
+			step(nnLayers)
+		}
+
+		modelPath := "model_checkpoint.db"
+		defer os.Remove(modelPath)
+		state := NNTrainingState{
+			Epoch: 3, Iteration: 12, RandomState: 1<<63 + 1, HasRandomState: true,
+			DatasetRandomState: 42, HasDatasetRandomState: true,
+			SchedulerStates: map[string]float64{"learningRate": 0.025, "step": 3},
+			CallbackStates:  map[string]float64{"0.best": 0.5, "0.hasBest": 1},
+		}
+
+		Convey("When : 進捗状況とあわせて保存し、復元する", func() {
+			err := WriteCheckpoint(modelPath, nnLayers, state)
+			So(err, ShouldBeNil)
+			reLayers, reState, err := ReadCheckpoint(modelPath)
+			So(err, ShouldBeNil)
+
+			Convey("Then : 進捗状況が復元前と同一であること", func() {
+				So(reState, ShouldResemble, state)
+			})
+			Convey("Then : Optimizerのハイパーパラメーターが復元前と同一であること", func() {
+				reOptimizer, ok := reLayers.GetOptimizer().(*neuralNetwork.AdamW)
+				So(ok, ShouldBeTrue)
+				So(reOptimizer.GetLearningRate(), ShouldEqual, 0.05)
+				So(reOptimizer.GetBeta1(), ShouldEqual, 0.8)
+				So(reOptimizer.GetBeta2(), ShouldEqual, neuralNetwork.DefaultAdamBeta2)
+				So(reOptimizer.GetWeightDecay(), ShouldEqual, 0.2)
+			})
+			Convey("Then : 続けて学習した結果が、復元前のNNで学習した結果と同一であること", func() {
+				for i := 0; i < 3; i++ {
+					So(step(reLayers), ShouldEqual, step(nnLayers))
+				}
+				before, _ := convertNNModel(nnLayers)
+				after, _ := convertNNModel(reLayers)
+				So(reflect.DeepEqual(before, after), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : WriteNNLayersで保存し、ReadCheckpointで復元する", func() {
+			err := WriteNNLayers(modelPath, nnLayers)
+			So(err, ShouldBeNil)
+			_, reState, err := ReadCheckpoint(modelPath)
+			So(err, ShouldBeNil)
+
+			Convey("Then : 進捗状況は初期状態であること", func() {
+				So(reState, ShouldResemble, NNTrainingState{})
+			})
+		})
+	})
+
+	Convey("Given : ハイパーパラメーターを保存していない形式のSGDの情報が与えられた時", t, func() {
+		nnData := NewNNData()
+		nnData.Type = SgdType
+		Convey("When : Optimizerを復元する", func() {
+			optimizer := convertOptimizerFromNNData(nnData)
+			Convey("Then : デフォルトの学習率が設定されること", func() {
+				So(optimizer.GetLearningRate(), ShouldEqual, neuralNetwork.DefaultLearningRate)
+			})
+		})
+	})
+}
+
+func TestReproducibleTraining(t *testing.T) {
+	Convey("Given : 同じシードで2回学習を行う時", t, func() {
+		train := func(seed int64) *neuralNetwork.NeuralNetworkLayers {
+			util.SetSeed(seed)
+			nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+			nnLayers.SetInputShape(neuralNetwork.NewShape(1, 4, 4))
+			nnLayers.Add(neuralNetwork.NewConv2D(2, 3, 3, neuralNetwork.WithConv2DPadding(1)))
+			nnLayers.Add(neuralNetwork.NewRelu())
+			nnLayers.Add(neuralNetwork.NewFlatten())
+			nnLayers.Add(neuralNetwork.NewInvertedDropout(0.3))
+			nnLayers.Add(neuralNetwork.NewAffineWithOutputSize(3))
+			nnLayers.SetOptimizer(neuralNetwork.NewAdam())
+
+			x := mat.NewDense(20, 16, util.RandomFloatArray(-1, 1, 20*16))
+			labels := util.RandomIntArray(3, 20)
+			for i := 0; i < 10; i++ {
+				// ミニバッチをランダムに抽出して学習
+				indexes := util.RandomIntArray(20, 5)
+				bx := mat.NewDense(5, 16, nil)
+				bt := mat.NewDense(5, 3, nil)
+				for j, index := range indexes {
+					bx.SetRow(j, x.RawRowView(index))
+					bt.Set(j, labels[index], 1)
+				}
+				nnLayers.Forward(bx, bt)
+				nnLayers.Backward()
+				nnLayers.Update()
+			}
+			return nnLayers
+		}
+
+		Convey("When : 同じシードを設定して学習する", func() {
+			before, err := convertNNModel(train(7))
+			So(err, ShouldBeNil)
+			after, err := convertNNModel(train(7))
+			So(err, ShouldBeNil)
+			Convey("Then : 学習後のモデルがビット単位で同一であること", func() {
+				So(reflect.DeepEqual(before, after), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 異なるシードを設定して学習する", func() {
+			before, _ := convertNNModel(train(7))
+			after, _ := convertNNModel(train(8))
+			Convey("Then : 学習後のモデルが異なること", func() {
+				So(reflect.DeepEqual(before, after), ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -128,6 +128,11 @@ func (s *SoftmaxWithLoss) Forward(x mat.Matrix, t mat.Matrix) (loss float64, acc
 	return s.loss, accuracy
 }
 
+// Predict : 正解データなしでSoftmaxの出力（各クラスの確率）を取得
+func (s *SoftmaxWithLoss) Predict(x mat.Matrix) mat.Matrix {
+	return s.softmax(x)
+}
+
 func calcAccuracy(out mat.Matrix, t mat.Matrix) float64 {
 	correct := 0
 	r, _ := out.Dims()
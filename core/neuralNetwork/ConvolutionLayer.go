@@ -0,0 +1,308 @@
+package neuralNetwork
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Conv2D : 2次元の畳み込みを行う素子
+// 入力は(データ数, チャネル数*高さ*幅)の行列、出力は(データ数, フィルター数*出力の高さ*出力の幅)の行列とする
+type Conv2D struct {
+	w  mat.Matrix // (フィルター数, チャネル数*フィルターの高さ*フィルターの幅)
+	b  mat.Vector // (フィルター数)
+	dw mat.Matrix
+	db mat.Vector
+
+	inputShape   Shape
+	filterNum    int
+	filterHeight int
+	filterWidth  int
+	stride       int
+	padding      int
+
+	weightInitializer Initializer
+	biasInitializer   Initializer
+	rnd               *rand.Rand
+
+	col   mat.Matrix // Im2colで変換した入力データ（逆伝搬で利用）
+	batch int
+}
+
+// Conv2DOption : Conv2Dのオプション
+type Conv2DOption func(*Conv2D)
+
+const (
+	// DefaultConvStride : デフォルトのストライドの値
+	DefaultConvStride = 1
+	// DefaultConvPadding : デフォルトのパディングの値
+	DefaultConvPadding = 0
+)
+
+// NewConv2D : 2次元の畳み込みの素子を取得
+// filterNum, filterHeight, filterWidth : フィルター数・フィルターの高さ・幅
+// 入力画像の形状はNeuralNetworkLayersに追加した時点、またはWithConv2DInputShapeで決定する
+// 初期化時にオプション指定が可能
+func NewConv2D(filterNum, filterHeight, filterWidth int, options ...Conv2DOption) *Conv2D {
+	conv := newConv2D(filterNum, filterHeight, filterWidth, options)
+	if conv.rnd == nil {
+		conv.rnd = util.NewRand()
+	}
+	if !conv.inputShape.IsEmpty() {
+		conv.SetInputShape(conv.inputShape)
+	}
+	return conv
+}
+
+// NewConv2DWithParams : 学習済みの重み・バイアスを指定して2次元の畳み込みの素子を取得
+// w : (フィルター数, チャネル数*フィルターの高さ*フィルターの幅)の重み, b : (フィルター数)のバイアス
+// 乱数での初期化を行わないため、util.SetSeedで設定したライブラリ全体の乱数に影響しない
+// 初期化時にオプション指定が可能
+func NewConv2DWithParams(w mat.Matrix, b mat.Vector, filterHeight, filterWidth int, options ...Conv2DOption) *Conv2D {
+	filterNum, size := w.Dims()
+	if b.Len() != filterNum {
+		panic(fmt.Sprintf("バイアスの要素数%dとフィルター数%dがマッチしてません", b.Len(), filterNum))
+	}
+	conv := newConv2D(filterNum, filterHeight, filterWidth, options)
+	conv.w, conv.b = w, b
+	if !conv.inputShape.IsEmpty() {
+		if size != conv.inputShape.Channel*filterHeight*filterWidth {
+			panic(fmt.Sprintf("重みの列数%dと入力画像の形状%sがマッチしてません", size, conv.inputShape))
+		}
+		conv.SetInputShape(conv.inputShape)
+	}
+	return conv
+}
+
+// newConv2D : オプションを適用した、パラメーターが未初期化のConv2Dを取得
+func newConv2D(filterNum, filterHeight, filterWidth int, options []Conv2DOption) *Conv2D {
+	conv := Conv2D{
+		filterNum:    filterNum,
+		filterHeight: filterHeight,
+		filterWidth:  filterWidth,
+		stride:       DefaultConvStride,
+		padding:      DefaultConvPadding,
+
+		weightInitializer: NewDefaultInitializer(),
+		biasInitializer:   NewDefaultInitializer(),
+	}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&conv)
+	}
+	if conv.stride <= 0 || conv.padding < 0 {
+		panic("ストライドは1以上、パディングは0以上を指定してください")
+	}
+	return &conv
+}
+
+// WithConv2DStride : Conv2Dのストライド指定のオプションを取得
+func WithConv2DStride(stride int) Conv2DOption {
+	return func(conv *Conv2D) {
+		conv.stride = stride
+	}
+}
+
+// WithConv2DPadding : Conv2Dのパディング指定のオプションを取得
+func WithConv2DPadding(padding int) Conv2DOption {
+	return func(conv *Conv2D) {
+		conv.padding = padding
+	}
+}
+
+// WithConv2DInputShape : Conv2Dの入力画像の形状指定のオプションを取得
+func WithConv2DInputShape(shape Shape) Conv2DOption {
+	return func(conv *Conv2D) {
+		conv.inputShape = shape
+	}
+}
+
+// WithConv2DWeightInitializer : 重み（フィルター）の初期化方法指定のオプションを取得
+func WithConv2DWeightInitializer(initializer Initializer) Conv2DOption {
+	return func(conv *Conv2D) {
+		conv.weightInitializer = initializer
+	}
+}
+
+// WithConv2DBiasInitializer : バイアスの初期化方法指定のオプションを取得
+func WithConv2DBiasInitializer(initializer Initializer) Conv2DOption {
+	return func(conv *Conv2D) {
+		conv.biasInitializer = initializer
+	}
+}
+
+// WithConv2DSeed : 初期化に利用する乱数のシード指定のオプションを取得
+func WithConv2DSeed(seed int64) Conv2DOption {
+	return func(conv *Conv2D) {
+		conv.rnd = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithConv2DRand : 初期化に利用する乱数生成器指定のオプションを取得
+func WithConv2DRand(rnd *rand.Rand) Conv2DOption {
+	return func(conv *Conv2D) {
+		conv.rnd = rnd
+	}
+}
+
+// SetInputShape : 入力画像の形状を設定し、出力画像の形状を返す
+// 重みが未初期化の場合や、入力チャネル数が重みとマッチしない場合は重みを初期化し直す
+func (conv *Conv2D) SetInputShape(shape Shape) Shape {
+	size := shape.Channel * conv.filterHeight * conv.filterWidth
+	channelChanged := conv.w == nil
+	if !channelChanged {
+		_, wSize := conv.w.Dims()
+		channelChanged = wSize != size
+	}
+	conv.inputShape = shape
+
+	outH, outW := conv.outputImageSize()
+	if outH <= 0 || outW <= 0 {
+		panic(fmt.Sprintf("入力画像の形状%sに対してフィルターのサイズが大きすぎます", shape))
+	}
+
+	if channelChanged {
+		// 学習済みのパラメーターを指定して作成した場合は、乱数生成器を重みの初期化が必要になった時点で作成する
+		if conv.rnd == nil {
+			conv.rnd = util.NewRand()
+		}
+		// fanIn : 1フィルターあたりの入力数, fanOut : 1入力あたりの出力数
+		fanOut := conv.filterNum * conv.filterHeight * conv.filterWidth
+		conv.w = mat.NewDense(conv.filterNum, size,
+			conv.weightInitializer.Initialize(conv.rnd, conv.filterNum, size, size, fanOut))
+		conv.b = mat.NewVecDense(conv.filterNum,
+			conv.biasInitializer.Initialize(conv.rnd, conv.filterNum, 1, size, fanOut))
+	}
+	return NewShape(conv.filterNum, outH, outW)
+}
+
+func (conv *Conv2D) Forward(x mat.Matrix) mat.Matrix {
+	if conv.w == nil {
+		panic("Conv2Dの入力画像の形状が設定されていません")
+	}
+	batch, _ := x.Dims()
+	outH, outW := conv.outputImageSize()
+	conv.batch = batch
+
+	// (データ数*出力の高さ*出力の幅, チャネル数*フィルターの高さ*フィルターの幅)の行列に変換
+	conv.col = util.Im2col(x, conv.inputShape.Channel, conv.inputShape.Height, conv.inputShape.Width,
+		conv.filterHeight, conv.filterWidth, conv.stride, conv.padding)
+
+	// (データ数*出力の高さ*出力の幅, フィルター数)の行列を計算
+	out := mat.NewDense(batch*outH*outW, conv.filterNum, nil)
+	out.Mul(conv.col, conv.w.T())
+
+	// (データ数, フィルター数*出力の高さ*出力の幅)の並びに変換し、バイアスを加算
+	pixels := outH * outW
+	dense := mat.NewDense(batch, conv.filterNum*pixels, nil)
+	for n := 0; n < batch; n++ {
+		row := dense.RawRowView(n)
+		for p := 0; p < pixels; p++ {
+			src := out.RawRowView(n*pixels + p)
+			for f := 0; f < conv.filterNum; f++ {
+				row[f*pixels+p] = src[f] + conv.b.AtVec(f)
+			}
+		}
+	}
+	return dense
+}
+
+func (conv *Conv2D) Backward(dout mat.Matrix) mat.Matrix {
+	outH, outW := conv.outputImageSize()
+	pixels := outH * outW
+	batch := conv.batch
+
+	// doutを(データ数*出力の高さ*出力の幅, フィルター数)の並びに変換
+	src := mat.DenseCopyOf(dout)
+	d := mat.NewDense(batch*pixels, conv.filterNum, nil)
+	for n := 0; n < batch; n++ {
+		row := src.RawRowView(n)
+		for p := 0; p < pixels; p++ {
+			dst := d.RawRowView(n*pixels + p)
+			for f := 0; f < conv.filterNum; f++ {
+				dst[f] = row[f*pixels+p]
+			}
+		}
+	}
+
+	// dbの計算
+	db := mat.NewVecDense(conv.filterNum, nil)
+	r, _ := d.Dims()
+	for i := 0; i < r; i++ {
+		for f, v := range d.RawRowView(i) {
+			db.SetVec(f, db.AtVec(f)+v)
+		}
+	}
+	conv.db = db
+
+	// dwの計算
+	_, size := conv.w.Dims()
+	dw := mat.NewDense(conv.filterNum, size, nil)
+	dw.Mul(d.T(), conv.col)
+	conv.dw = dw
+
+	// dxの計算
+	dcol := mat.NewDense(batch*pixels, size, nil)
+	dcol.Mul(d, conv.w)
+	return util.Col2im(dcol, batch, conv.inputShape.Channel, conv.inputShape.Height, conv.inputShape.Width,
+		conv.filterHeight, conv.filterWidth, conv.stride, conv.padding)
+}
+
+func (conv *Conv2D) GetParams() map[string]mat.Matrix {
+	params := make(map[string]mat.Matrix)
+	params["w"] = conv.w
+	params["b"] = conv.b
+	return params
+}
+
+func (conv *Conv2D) GetGradients() map[string]mat.Matrix {
+	grads := make(map[string]mat.Matrix)
+	grads["w"] = conv.dw
+	grads["b"] = conv.db
+	return grads
+}
+
+func (conv *Conv2D) UpdateParams(params map[string]mat.Matrix) {
+	// パラメータのアップデート
+	conv.w = params["w"]
+	conv.b = mat.DenseCopyOf(params["b"]).ColView(0)
+
+	// 勾配のリセット
+	conv.dw = nil
+	conv.db = nil
+}
+
+// GetInputShape : 入力画像の形状を取得
+func (conv *Conv2D) GetInputShape() Shape {
+	return conv.inputShape
+}
+
+// GetFilterSize : フィルター数・フィルターの高さ・幅を取得
+func (conv *Conv2D) GetFilterSize() (num int, height int, width int) {
+	return conv.filterNum, conv.filterHeight, conv.filterWidth
+}
+
+// GetOutputShape : 出力画像の形状を取得
+func (conv *Conv2D) GetOutputShape() Shape {
+	outH, outW := conv.outputImageSize()
+	return NewShape(conv.filterNum, outH, outW)
+}
+
+// GetStride : ストライドを取得
+func (conv *Conv2D) GetStride() int {
+	return conv.stride
+}
+
+// GetPadding : パディングを取得
+func (conv *Conv2D) GetPadding() int {
+	return conv.padding
+}
+
+func (conv *Conv2D) outputImageSize() (height int, width int) {
+	height = util.ConvOutputSize(conv.inputShape.Height, conv.filterHeight, conv.stride, conv.padding)
+	width = util.ConvOutputSize(conv.inputShape.Width, conv.filterWidth, conv.stride, conv.padding)
+	return height, width
+}
@@ -0,0 +1,140 @@
+package neuralNetwork
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestConv2D(t *testing.T) {
+	Convey("Given : 入力が2チャネル・高さ4・幅5, フィルターが3個・高さ3・幅2の畳み込みレイヤーが与えられた時", t, func() {
+		c, h, w := 2, 4, 5
+		fn, fh, fw := 3, 3, 2
+		Convey("AND : ストライドは2, パディングは1とする", nil)
+		stride, padding := 2, 1
+		conv := NewConv2D(fn, fh, fw, WithConv2DStride(stride), WithConv2DPadding(padding), WithConv2DInputShape(NewShape(c, h, w)))
+
+		Convey("AND : 重みとバイアスを初期化", nil)
+		params := make(map[string]mat.Matrix)
+		params["w"] = mat.NewDense(fn, c*fh*fw, util.CreateFloatArrayByStep(fn*c*fh*fw, -1, 0.1))
+		params["b"] = mat.NewVecDense(fn, []float64{-1, 0, 1})
+		conv.UpdateParams(params)
+
+		Convey("When : 入力xを2個の画像とする", func() {
+			batch := 2
+			x := mat.NewDense(batch, c*h*w, util.CreateFloatArrayByStep(batch*c*h*w, -5, 0.25))
+			out := conv.Forward(x)
+			Convey("Then : Forward処理の出力が単純な畳み込みの結果と一致すること", func() {
+				So(conv.GetOutputShape(), ShouldResemble, NewShape(fn, 2, 3))
+				expected := conv2DForward(x, params["w"], params["b"], c, h, w, fh, fw, stride, padding)
+				So(mat.EqualApprox(out, expected, math.Pow10(-10)), ShouldBeTrue)
+			})
+
+			Convey("AND : 誤差doutが与えられた時", nil)
+			dout := mat.NewDense(batch, fn*2*3, util.CreateFloatArrayByStep(batch*fn*2*3, 1, -0.2))
+			Convey("Then : Backward処理の結果が単純な畳み込みの勾配と一致すること", func() {
+				dx := conv.Backward(dout)
+				expectedDx, expectedDw, expectedDb := conv2DBackward(x, dout, params["w"], c, h, w, fh, fw, stride, padding)
+				So(mat.EqualApprox(dx, expectedDx, math.Pow10(-10)), ShouldBeTrue)
+				So(mat.EqualApprox(conv.dw, expectedDw, math.Pow10(-10)), ShouldBeTrue)
+				So(mat.EqualApprox(conv.db, expectedDb, math.Pow10(-10)), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func conv2DForward(x, w mat.Matrix, b mat.Matrix, c, h, wid, fh, fw, stride, padding int) mat.Matrix {
+	batch, _ := x.Dims()
+	fn, _ := w.Dims()
+	outH := (h+2*padding-fh)/stride + 1
+	outW := (wid+2*padding-fw)/stride + 1
+	dense := mat.NewDense(batch, fn*outH*outW, nil)
+	for n := 0; n < batch; n++ {
+		for f := 0; f < fn; f++ {
+			for oy := 0; oy < outH; oy++ {
+				for ox := 0; ox < outW; ox++ {
+					sum := b.At(f, 0)
+					for ch := 0; ch < c; ch++ {
+						for ky := 0; ky < fh; ky++ {
+							for kx := 0; kx < fw; kx++ {
+								y := oy*stride + ky - padding
+								xx := ox*stride + kx - padding
+								if y < 0 || y >= h || xx < 0 || xx >= wid {
+									continue
+								}
+								sum += x.At(n, (ch*h+y)*wid+xx) * w.At(f, (ch*fh+ky)*fw+kx)
+							}
+						}
+					}
+					dense.Set(n, (f*outH+oy)*outW+ox, sum)
+				}
+			}
+		}
+	}
+	return dense
+}
+
+func conv2DBackward(x, dout, w mat.Matrix, c, h, wid, fh, fw, stride, padding int) (dx, dw mat.Matrix, db mat.Vector) {
+	batch, _ := x.Dims()
+	fn, size := w.Dims()
+	outH := (h+2*padding-fh)/stride + 1
+	outW := (wid+2*padding-fw)/stride + 1
+	dxDense := mat.NewDense(batch, c*h*wid, nil)
+	dwDense := mat.NewDense(fn, size, nil)
+	dbVec := mat.NewVecDense(fn, nil)
+	for n := 0; n < batch; n++ {
+		for f := 0; f < fn; f++ {
+			for oy := 0; oy < outH; oy++ {
+				for ox := 0; ox < outW; ox++ {
+					d := dout.At(n, (f*outH+oy)*outW+ox)
+					dbVec.SetVec(f, dbVec.AtVec(f)+d)
+					for ch := 0; ch < c; ch++ {
+						for ky := 0; ky < fh; ky++ {
+							for kx := 0; kx < fw; kx++ {
+								y := oy*stride + ky - padding
+								xx := ox*stride + kx - padding
+								if y < 0 || y >= h || xx < 0 || xx >= wid {
+									continue
+								}
+								xi := (ch*h+y)*wid + xx
+								wi := (ch*fh+ky)*fw + kx
+								dxDense.Set(n, xi, dxDense.At(n, xi)+d*w.At(f, wi))
+								dwDense.Set(f, wi, dwDense.At(f, wi)+d*x.At(n, xi))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return dxDense, dwDense, dbVec
+}
+
+func TestConv2DWithParams(t *testing.T) {
+	Convey("Given : フィルターが2個・高さ2・幅2, 入力が1チャネルの学習済みの重みとバイアスが与えられた時", t, func() {
+		w := mat.NewDense(2, 4, util.CreateFloatArrayByStep(8, 1, 1))
+		b := mat.NewVecDense(2, []float64{-1, 1})
+		Convey("When : 重みとバイアスを指定して畳み込みレイヤーを作成し、NNに追加する", func() {
+			util.SetSeed(6)
+			conv := NewConv2DWithParams(w, b, 2, 2)
+			nnLayers := NewDefaultNeuralNetworkLayers()
+			nnLayers.SetInputShape(NewShape(1, 3, 3))
+			nnLayers.Add(conv)
+			next := util.NewRand().Int63()
+			util.SetSeed(6)
+			Convey("Then : 指定した重みとバイアスが利用され、ライブラリ全体の乱数が進まないこと", func() {
+				So(conv.GetParams()["w"], ShouldEqual, w)
+				So(conv.GetParams()["b"], ShouldEqual, b)
+				So(nnLayers.GetOutputShape(), ShouldResemble, NewShape(2, 2, 2))
+				So(next, ShouldEqual, util.NewRand().Int63())
+			})
+		})
+		Convey("Then : バイアスの要素数・入力画像のチャネル数がマッチしない場合はpanicが発生すること", func() {
+			So(func() { NewConv2DWithParams(w, mat.NewVecDense(3, nil), 2, 2) }, ShouldPanic)
+			So(func() { NewConv2DWithParams(w, b, 2, 2, WithConv2DInputShape(NewShape(2, 3, 3))) }, ShouldPanic)
+		})
+	})
+}
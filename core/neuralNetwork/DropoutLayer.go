@@ -0,0 +1,159 @@
+package neuralNetwork
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Dropout : 学習時にランダムに選んだ要素の出力を0にするレイヤー
+// 推論時は全ての要素をそのまま利用する
+type Dropout struct {
+	ratio    float64 // 出力を0にする要素の割合
+	inverted bool
+	training bool
+	rnd      *rand.Rand
+	source   *util.SplitMix64Source // 乱数の状態の保存・復元用（WithDropoutRandで指定した場合はnil）
+	mask     *mat.Dense
+}
+
+// RandomStateLayer : 乱数の状態を持つレイヤーのIF
+// 学習を中断・再開する際に、乱数列を続きから再開するために利用する
+type RandomStateLayer interface {
+	// GetRandomState : 乱数の状態を取得（状態を取得できない乱数生成器の場合はfalse）
+	GetRandomState() (uint64, bool)
+	// SetRandomState : 乱数の状態を設定
+	SetRandomState(state uint64)
+}
+
+// DropoutOption : Dropoutのオプション
+type DropoutOption func(*Dropout)
+
+// NewDropout : Dropoutの素子を取得
+// ratio : 出力を0にする要素の割合（0以上1未満）
+// 推論時は学習時の出力の期待値に合わせるため、出力を(1 - ratio)倍する
+// 初期化時にオプション指定が可能
+func NewDropout(ratio float64, options ...DropoutOption) *Dropout {
+	return newDropout(ratio, false, options)
+}
+
+// NewInvertedDropout : Inverted Dropoutの素子を取得
+// ratio : 出力を0にする要素の割合（0以上1未満）
+// 学習時に残した要素を1 / (1 - ratio)倍するため、推論時は入力をそのまま出力する
+// 初期化時にオプション指定が可能
+func NewInvertedDropout(ratio float64, options ...DropoutOption) *Dropout {
+	return newDropout(ratio, true, options)
+}
+
+func newDropout(ratio float64, inverted bool, options []DropoutOption) *Dropout {
+	if ratio < 0 || ratio >= 1 {
+		panic(fmt.Sprintf("ratioは0以上1未満を指定してください : %v", ratio))
+	}
+	d := Dropout{ratio: ratio, inverted: inverted, training: true}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&d)
+	}
+	if d.rnd == nil {
+		d.rnd, d.source = util.NewRandWithSource()
+	}
+	return &d
+}
+
+// WithDropoutSeed : マスク生成に利用する乱数のシード指定のオプションを取得
+// 同じシードを指定した場合、同じ順序でマスクが生成される
+func WithDropoutSeed(seed int64) DropoutOption {
+	return func(d *Dropout) {
+		d.source = util.NewSource(seed)
+		d.rnd = rand.New(d.source)
+	}
+}
+
+// WithDropoutRand : マスク生成に利用する乱数生成器指定のオプションを取得
+func WithDropoutRand(rnd *rand.Rand) DropoutOption {
+	return func(d *Dropout) {
+		d.rnd = rnd
+		d.source = nil
+	}
+}
+
+func (d *Dropout) GetRandomState() (uint64, bool) {
+	if d.source == nil {
+		return 0, false
+	}
+	return d.source.GetState(), true
+}
+
+// SetRandomState : 乱数の状態を設定
+// WithDropoutRandで乱数生成器を指定していた場合は、SplitMix64Sourceを利用した乱数生成器に置き換える
+func (d *Dropout) SetRandomState(state uint64) {
+	if d.source == nil {
+		d.source = util.NewSource(0)
+		d.rnd = rand.New(d.source)
+	}
+	d.source.SetState(state)
+}
+
+func (d *Dropout) SetTrainingMode(training bool) {
+	d.training = training
+}
+
+func (d *Dropout) Forward(x mat.Matrix) mat.Matrix {
+	r, c := x.Dims()
+	out := mat.NewDense(r, c, nil)
+	if !d.training {
+		d.mask = nil
+		if d.inverted {
+			out.Copy(x)
+		} else {
+			out.Scale(1-d.ratio, x)
+		}
+		return out
+	}
+
+	// 学習時はratioの割合で要素を0にするマスクを作成する
+	scale := 1.0
+	if d.inverted {
+		scale = 1 / (1 - d.ratio)
+	}
+	mask := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if d.rnd.Float64() >= d.ratio {
+				mask.Set(i, j, scale)
+			}
+		}
+	}
+	d.mask = mask
+	out.MulElem(x, mask)
+	return out
+}
+
+func (d *Dropout) Backward(dout mat.Matrix) mat.Matrix {
+	r, c := dout.Dims()
+	dx := mat.NewDense(r, c, nil)
+	if d.mask == nil {
+		// 推論時のForward処理の逆伝搬
+		if d.inverted {
+			dx.Copy(dout)
+		} else {
+			dx.Scale(1-d.ratio, dout)
+		}
+		return dx
+	}
+	dx.MulElem(dout, d.mask)
+	return dx
+}
+
+// GetRatio : 出力を0にする要素の割合を取得
+func (d *Dropout) GetRatio() float64 {
+	return d.ratio
+}
+
+// IsInverted : Inverted Dropoutかどうか
+func (d *Dropout) IsInverted() bool {
+	return d.inverted
+}
@@ -0,0 +1,108 @@
+package neuralNetwork
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDropout(t *testing.T) {
+	Convey("Given : 割合0.5, シード1のDropoutレイヤーが与えられた時", t, func() {
+		d := NewDropout(0.5, WithDropoutSeed(1))
+		Convey("AND : 入力xを10*20行列とする", nil)
+		x := mat.NewDense(10, 20, util.CreateFloatArrayByStep(200, 1, 1))
+		Convey("When : 学習時のForward処理を行う", func() {
+			out := d.Forward(x)
+			Convey("Then : 各要素は0か入力値のままであること", func() {
+				zero := 0
+				for i := 0; i < 10; i++ {
+					for j := 0; j < 20; j++ {
+						if out.At(i, j) == 0 {
+							zero++
+							continue
+						}
+						So(out.At(i, j), ShouldEqual, x.At(i, j))
+					}
+				}
+				So(zero, ShouldBeBetween, 60, 140)
+			})
+			Convey("Then : 同じシードのDropoutでは同じ結果になること", func() {
+				other := NewDropout(0.5, WithDropoutSeed(1))
+				So(mat.Equal(other.Forward(x), out), ShouldBeTrue)
+			})
+
+			Convey("AND : 誤差doutが与えられた時", nil)
+			dout := mat.NewDense(10, 20, util.CreateFloatArrayByStep(200, 0.5, 0.5))
+			Convey("Then : Backward処理では、Forward処理で0にした要素の誤差のみ0になること", func() {
+				dx := d.Backward(dout)
+				for i := 0; i < 10; i++ {
+					for j := 0; j < 20; j++ {
+						if out.At(i, j) == 0 {
+							So(dx.At(i, j), ShouldEqual, 0)
+						} else {
+							So(dx.At(i, j), ShouldEqual, dout.At(i, j))
+						}
+					}
+				}
+			})
+		})
+		Convey("When : 乱数の状態を取得し、別のDropoutに設定する", func() {
+			state, ok := d.GetRandomState()
+			other := NewDropout(0.5, WithDropoutRand(rand.New(rand.NewSource(3))))
+			other.SetRandomState(state)
+			Convey("Then : 以降は同じマスクが生成されること", func() {
+				So(ok, ShouldBeTrue)
+				So(mat.Equal(other.Forward(x), d.Forward(x)), ShouldBeTrue)
+			})
+		})
+		Convey("When : 乱数生成器を指定したDropoutの乱数の状態を取得する", func() {
+			_, ok := NewDropout(0.5, WithDropoutRand(rand.New(rand.NewSource(3)))).GetRandomState()
+			Convey("Then : 状態が取得できないこと", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+		Convey("When : 推論時のForward処理を行う", func() {
+			d.SetTrainingMode(false)
+			out := d.Forward(x)
+			Convey("Then : 入力を(1 - 割合)倍した値が出力されること", func() {
+				expected := mat.NewDense(10, 20, nil)
+				expected.Scale(0.5, x)
+				So(mat.Equal(out, expected), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given : 割合0.2のInverted Dropoutレイヤーが与えられた時", t, func() {
+		d := NewInvertedDropout(0.2, WithDropoutSeed(2))
+		x := mat.NewDense(4, 5, util.CreateFloatArrayByStep(20, 1, 1))
+		Convey("When : 学習時のForward処理を行う", func() {
+			out := d.Forward(x)
+			Convey("Then : 残った要素は1 / (1 - 割合)倍されること", func() {
+				for i := 0; i < 4; i++ {
+					for j := 0; j < 5; j++ {
+						if out.At(i, j) != 0 {
+							So(out.At(i, j), ShouldAlmostEqual, x.At(i, j)/0.8, math.Pow10(-10))
+						}
+					}
+				}
+			})
+		})
+		Convey("When : 推論時のForward処理を行う", func() {
+			d.SetTrainingMode(false)
+			Convey("Then : 入力がそのまま出力されること", func() {
+				So(mat.Equal(d.Forward(x), x), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given : 範囲外の割合が与えられた時", t, func() {
+		Convey("Then : panicが発生すること", func() {
+			So(func() { NewDropout(1) }, ShouldPanic)
+			So(func() { NewInvertedDropout(-0.1) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,180 @@
+package neuralNetwork
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// DefaultGradientCheckEpsilon : 数値微分（中心差分）で利用する微小変化量のデフォルト値
+	DefaultGradientCheckEpsilon = 1e-5
+	// gradientCheckMinScale : 相対誤差を計算する際の分母の下限（勾配がほぼ0の場合に誤差が過大にならないようにする）
+	gradientCheckMinScale = 1e-7
+)
+
+// GradientErrors : 勾配チェックの結果（パラメーターのキー毎の、解析的な勾配と数値微分の勾配の最大相対誤差）
+type GradientErrors map[string]float64
+
+// Max : 全てのキーの中での最大相対誤差を取得
+func (errs GradientErrors) Max() float64 {
+	max := 0.0
+	for _, e := range errs {
+		max = math.Max(max, e)
+	}
+	return max
+}
+
+// GradientCheckOption : 勾配チェックのオプション
+type GradientCheckOption func(*gradientChecker)
+
+type gradientChecker struct {
+	epsilon float64
+}
+
+// WithGradientCheckEpsilon : 数値微分で利用する微小変化量指定のオプションを取得
+func WithGradientCheckEpsilon(epsilon float64) GradientCheckOption {
+	return func(gc *gradientChecker) {
+		gc.epsilon = epsilon
+	}
+}
+
+func newGradientChecker(options []GradientCheckOption) *gradientChecker {
+	gc := gradientChecker{epsilon: DefaultGradientCheckEpsilon}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&gc)
+	}
+	return &gc
+}
+
+// CheckLayerGradients : 1つの素子のBackward処理を数値微分で検証する
+// 損失を sum(Forward(x) * dout) とし、入力xの勾配はキー"x"、パラメーターの勾配はGetParamsのキーで結果を返す
+// Dropoutなど、Forward処理の度に結果が変わる素子は推論時の挙動に切り替えてから検証すること
+// BatchNormalizationの推論用の平均・分散など、Forward処理で更新される状態は検証後に元の値に戻す
+func CheckLayerGradients(layer NeuralNetworkBaseLayer, x mat.Matrix, dout mat.Matrix, options ...GradientCheckOption) GradientErrors {
+	gc := newGradientChecker(options)
+	defer saveStates([]NeuralNetworkBaseLayer{layer})()
+	input := mat.DenseCopyOf(x)
+	loss := func() float64 {
+		out := mat.DenseCopyOf(layer.Forward(input))
+		out.MulElem(out, dout)
+		return mat.Sum(out)
+	}
+
+	// 解析的な勾配を計算
+	layer.Forward(input)
+	dx := layer.Backward(dout)
+	errs := make(GradientErrors)
+	paramLayer, hasParams := layer.(NeuralNetworkLayer)
+	var grads map[string]mat.Matrix
+	if hasParams {
+		grads = util.CopyMatrixMap(paramLayer.GetGradients())
+	}
+
+	// 入力xの勾配を検証
+	errs["x"] = gc.compare(dx, input, func(i, j int, v float64) float64 {
+		input.Set(i, j, v)
+		return loss()
+	})
+
+	// パラメーターの勾配を検証
+	if hasParams {
+		for key, e := range gc.checkParams(paramLayer, grads, loss) {
+			errs[key] = e
+		}
+	}
+	return errs
+}
+
+// CheckNetworkGradients : NeuralNetworkLayers全体のBackward処理を数値微分で検証する
+// 損失は最終層のForward処理の結果とし、"素子のインデックス.パラメーターのキー"（例 : "0.w"）で結果を返す
+// Dropoutなど、Forward処理の度に結果が変わる素子を含む場合は推論時の挙動に切り替えてから検証すること
+// BatchNormalizationの推論用の平均・分散など、Forward処理で更新される状態は検証後に元の値に戻す
+func CheckNetworkGradients(nnl *NeuralNetworkLayers, x mat.Matrix, t mat.Matrix, options ...GradientCheckOption) GradientErrors {
+	gc := newGradientChecker(options)
+	defer saveStates(nnl.GetLayers())()
+	loss := func() float64 {
+		l, _ := nnl.Forward(x, t)
+		return l
+	}
+
+	// 解析的な勾配を計算
+	nnl.Forward(x, t)
+	nnl.Backward()
+	allGrads := make(map[int]map[string]mat.Matrix)
+	for i, layer := range nnl.GetLayers() {
+		if paramLayer, ok := layer.(NeuralNetworkLayer); ok {
+			allGrads[i] = util.CopyMatrixMap(paramLayer.GetGradients())
+		}
+	}
+
+	// 各素子のパラメーターの勾配を検証
+	errs := make(GradientErrors)
+	for i, layer := range nnl.GetLayers() {
+		grads, ok := allGrads[i]
+		if !ok {
+			continue
+		}
+		for key, e := range gc.checkParams(layer.(NeuralNetworkLayer), grads, loss) {
+			errs[fmt.Sprintf("%d.%s", i, key)] = e
+		}
+	}
+	return errs
+}
+
+// checkParams : 素子のパラメーターを1要素ずつ変化させて、勾配を検証する
+// 検証後はパラメーターを元の値に戻す
+func (gc *gradientChecker) checkParams(layer NeuralNetworkLayer, grads map[string]mat.Matrix, loss func() float64) GradientErrors {
+	original := util.CopyMatrixMap(layer.GetParams())
+	errs := make(GradientErrors)
+	for key := range original {
+		params := util.CopyMatrixMap(original)
+		target := params[key].(*mat.Dense)
+		errs[key] = gc.compare(grads[key], target, func(i, j int, v float64) float64 {
+			target.Set(i, j, v)
+			layer.UpdateParams(util.CopyMatrixMap(params))
+			return loss()
+		})
+	}
+	layer.UpdateParams(original)
+	return errs
+}
+
+// saveStates : StatefulLayerを実装した素子の状態のコピーを保存し、保存した状態に戻す関数を返す
+func saveStates(layers []NeuralNetworkBaseLayer) (restore func()) {
+	saved := make(map[StatefulLayer]map[string]mat.Matrix)
+	for _, layer := range layers {
+		if statefulLayer, ok := layer.(StatefulLayer); ok {
+			saved[statefulLayer] = util.CopyMatrixMap(statefulLayer.GetStates())
+		}
+	}
+	return func() {
+		for statefulLayer, states := range saved {
+			statefulLayer.SetStates(states)
+		}
+	}
+}
+
+// compare : targetの各要素を変化させた時の損失から数値微分の勾配を求め、解析的な勾配との最大相対誤差を返す
+// lossAt : targetの(i, j)要素をvにした時の損失を返す関数
+func (gc *gradientChecker) compare(grad mat.Matrix, target *mat.Dense, lossAt func(i, j int, v float64) float64) float64 {
+	maxErr := 0.0
+	r, c := target.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := target.At(i, j)
+			plus := lossAt(i, j, v+gc.epsilon)
+			minus := lossAt(i, j, v-gc.epsilon)
+			target.Set(i, j, v)
+			numerical := (plus - minus) / (2 * gc.epsilon)
+			analytical := grad.At(i, j)
+			scale := math.Max(math.Abs(analytical)+math.Abs(numerical), gradientCheckMinScale)
+			maxErr = math.Max(maxErr, math.Abs(analytical-numerical)/scale)
+		}
+	}
+	return maxErr
+}
@@ -0,0 +1,127 @@
+package neuralNetwork
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCheckLayerGradients(t *testing.T) {
+	Convey("Given : 入力xを3*8行列、誤差doutを各素子の出力と同じ形の行列とする", t, func() {
+		util.SetSeed(1)
+		x := mat.NewDense(3, 8, util.RandomFloatArray(-1, 1, 24))
+		doutOf := func(layer NeuralNetworkBaseLayer) mat.Matrix {
+			r, c := layer.Forward(x).Dims()
+			return mat.NewDense(r, c, util.RandomFloatArray(-1, 1, r*c))
+		}
+		tolerance := math.Pow10(-6)
+
+		Convey("When : 活性化関数の勾配チェックを行う", func() {
+			Convey("Then : 入力の勾配の相対誤差が小さいこと", func() {
+				for _, layer := range []NeuralNetworkBaseLayer{NewSigmoid(), NewTanh(), NewRelu()} {
+					So(CheckLayerGradients(layer, x, doutOf(layer))["x"], ShouldBeLessThan, tolerance)
+				}
+			})
+		})
+		Convey("When : Affineの勾配チェックを行う", func() {
+			affine := NewAffine(8, 5)
+			errs := CheckLayerGradients(affine, x, doutOf(affine))
+			Convey("Then : 入力・重み・バイアスの勾配の相対誤差が小さいこと", func() {
+				So(errs, ShouldContainKey, "w")
+				So(errs, ShouldContainKey, "b")
+				So(errs.Max(), ShouldBeLessThan, tolerance)
+			})
+		})
+		Convey("When : 2チャネル・2*2画像として畳み込み・プーリング・BatchNormalizationの勾配チェックを行う", func() {
+			shape := NewShape(2, 2, 2)
+			conv := NewConv2D(3, 2, 2, WithConv2DPadding(1), WithConv2DInputShape(shape))
+			maxPool := NewMaxPooling2D(2, 2, WithPooling2DInputShape(shape))
+			avgPool := NewAveragePooling2D(2, 2, WithPooling2DStride(1), WithPooling2DInputShape(shape))
+			bn := NewBatchNormalization()
+			bn.SetInputShape(shape)
+			Convey("Then : 各勾配の相対誤差が小さいこと", func() {
+				for _, layer := range []NeuralNetworkBaseLayer{conv, maxPool, avgPool, bn} {
+					So(CheckLayerGradients(layer, x, doutOf(layer)).Max(), ShouldBeLessThan, tolerance)
+				}
+			})
+		})
+		Convey("When : 学習時の挙動のBatchNormalizationの勾配チェックを行う", func() {
+			bn := NewBatchNormalization()
+			dout := doutOf(bn)
+			before := util.CopyMatrixMap(bn.GetStates())
+			fresh := NewBatchNormalization()
+			CheckLayerGradients(bn, x, dout)
+			CheckLayerGradients(fresh, x, dout)
+			Convey("Then : 勾配チェック後は推論用の平均・分散が元の値に戻っていること", func() {
+				after := bn.GetStates()
+				So(mat.Equal(after["runningMean"], before["runningMean"]), ShouldBeTrue)
+				So(mat.Equal(after["runningVar"], before["runningVar"]), ShouldBeTrue)
+				So(fresh.GetStates(), ShouldBeEmpty)
+			})
+		})
+		Convey("When : 推論時の挙動に切り替えたDropoutの勾配チェックを行う", func() {
+			dropout := NewDropout(0.5)
+			dropout.SetTrainingMode(false)
+			errs := CheckLayerGradients(dropout, x, doutOf(dropout))
+			Convey("Then : 入力の勾配の相対誤差が小さいこと", func() {
+				So(errs["x"], ShouldBeLessThan, tolerance)
+			})
+		})
+		Convey("When : Backward処理が誤っている素子の勾配チェックを行う", func() {
+			layer := &wrongGradientLayer{}
+			errs := CheckLayerGradients(layer, x, doutOf(layer))
+			Convey("Then : 相対誤差が大きくなること", func() {
+				So(errs["x"], ShouldBeGreaterThan, 0.1)
+			})
+		})
+	})
+}
+
+func TestCheckNetworkGradients(t *testing.T) {
+	Convey("Given : Affine, Tanh, Affineからなる2層のNNが与えられた時", t, func() {
+		util.SetSeed(2)
+		nnLayers := NewDefaultNeuralNetworkLayers()
+		nnLayers.Add(NewAffine(4, 6, WithAffineWeightInitializer(NewXavierNormalInitializer())))
+		nnLayers.Add(NewTanh())
+		nnLayers.Add(NewAffine(6, 3, WithAffineWeightInitializer(NewXavierNormalInitializer())))
+		x := mat.NewDense(5, 4, util.RandomFloatArray(-1, 1, 20))
+		tr := mat.NewDense(5, 3, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1, 1, 0, 0, 0, 1, 0})
+		Convey("When : SoftmaxWithLossを最終層として勾配チェックを行う", func() {
+			before := mat.DenseCopyOf(nnLayers.GetLayers()[0].(*Affine).GetParams()["w"])
+			errs := CheckNetworkGradients(nnLayers, x, tr)
+			Convey("Then : 全ての素子のパラメーターの勾配の相対誤差が小さいこと", func() {
+				So(len(errs), ShouldEqual, 4)
+				So(errs, ShouldContainKey, "0.w")
+				So(errs, ShouldContainKey, "2.b")
+				So(errs.Max(), ShouldBeLessThan, math.Pow10(-6))
+			})
+			Convey("Then : 勾配チェック後はパラメーターが元の値に戻っていること", func() {
+				So(mat.Equal(nnLayers.GetLayers()[0].(*Affine).GetParams()["w"], before), ShouldBeTrue)
+			})
+		})
+		Convey("When : MeanSquaredErrorを最終層として勾配チェックを行う", func() {
+			nnLayers.SetLastActivationLayer(NewMeanSquaredError())
+			Convey("Then : 全ての素子のパラメーターの勾配の相対誤差が小さいこと", func() {
+				So(CheckNetworkGradients(nnLayers, x, tr).Max(), ShouldBeLessThan, math.Pow10(-6))
+			})
+		})
+	})
+}
+
+// wrongGradientLayer : 勾配チェックの検証用に、Backward処理で誤った勾配を返す素子
+type wrongGradientLayer struct{}
+
+func (w *wrongGradientLayer) Forward(x mat.Matrix) mat.Matrix {
+	r, c := x.Dims()
+	out := mat.NewDense(r, c, nil)
+	out.MulElem(x, x)
+	return out
+}
+
+func (w *wrongGradientLayer) Backward(dout mat.Matrix) mat.Matrix {
+	// 正しくは 2 * x * dout
+	return mat.DenseCopyOf(dout)
+}
@@ -0,0 +1,170 @@
+package neuralNetwork
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Initializer : パラメーターの初期値を生成するIF
+type Initializer interface {
+	// Initialize : rows*cols個の初期値を行優先の並びで生成する
+	// fanIn, fanOut : 1出力あたりの入力数・1入力あたりの出力数（分散の調整に利用）
+	Initialize(rnd *rand.Rand, rows, cols, fanIn, fanOut int) []float64
+}
+
+const (
+	// DefaultInitializerStddev : デフォルトの初期化で利用する正規分布の標準偏差
+	DefaultInitializerStddev = 0.01
+)
+
+// NewDefaultInitializer : 重み・バイアスのデフォルトのInitializerを取得（標準偏差0.01の正規分布）
+func NewDefaultInitializer() Initializer {
+	return NewNormalInitializer(DefaultInitializerStddev)
+}
+
+// NormalInitializer : 平均0, 標準偏差stddevの正規分布で初期化する
+type NormalInitializer struct {
+	stddev float64
+}
+
+// NewNormalInitializer : NormalInitializerを取得
+func NewNormalInitializer(stddev float64) *NormalInitializer {
+	return &NormalInitializer{stddev: stddev}
+}
+
+func (n *NormalInitializer) Initialize(rnd *rand.Rand, rows, cols, fanIn, fanOut int) []float64 {
+	values := make([]float64, rows*cols)
+	for i := range values {
+		values[i] = rnd.NormFloat64() * n.stddev
+	}
+	return values
+}
+
+// UniformInitializer : [min, max)の一様分布で初期化する
+type UniformInitializer struct {
+	min float64
+	max float64
+}
+
+// NewUniformInitializer : UniformInitializerを取得
+func NewUniformInitializer(min, max float64) *UniformInitializer {
+	return &UniformInitializer{min: min, max: max}
+}
+
+func (u *UniformInitializer) Initialize(rnd *rand.Rand, rows, cols, fanIn, fanOut int) []float64 {
+	values := make([]float64, rows*cols)
+	for i := range values {
+		values[i] = rnd.Float64()*(u.max-u.min) + u.min
+	}
+	return values
+}
+
+// ZerosInitializer : 全て0で初期化する（バイアス向け）
+type ZerosInitializer struct{}
+
+// NewZerosInitializer : ZerosInitializerを取得
+func NewZerosInitializer() *ZerosInitializer {
+	return &ZerosInitializer{}
+}
+
+func (z *ZerosInitializer) Initialize(rnd *rand.Rand, rows, cols, fanIn, fanOut int) []float64 {
+	return make([]float64, rows*cols)
+}
+
+// VarianceScalingInitializer : fanIn, fanOutに応じて分散を調整した分布で初期化する
+// 分散は scale / fan （fanは入力数、または入力数と出力数の平均）
+type VarianceScalingInitializer struct {
+	scale   float64
+	fanAvg  bool // trueの場合は入力数と出力数の平均、falseの場合は入力数を利用
+	uniform bool // trueの場合は一様分布、falseの場合は正規分布を利用
+}
+
+// NewXavierNormalInitializer : Xavier(Glorot)の初期値（正規分布）のInitializerを取得
+// 分散は 2 / (fanIn + fanOut) で、SigmoidやTanhを活性化関数とする場合に向く
+func NewXavierNormalInitializer() *VarianceScalingInitializer {
+	return &VarianceScalingInitializer{scale: 1, fanAvg: true}
+}
+
+// NewXavierUniformInitializer : Xavier(Glorot)の初期値（一様分布）のInitializerを取得
+func NewXavierUniformInitializer() *VarianceScalingInitializer {
+	return &VarianceScalingInitializer{scale: 1, fanAvg: true, uniform: true}
+}
+
+// NewHeNormalInitializer : Heの初期値（正規分布）のInitializerを取得
+// 分散は 2 / fanIn で、Reluを活性化関数とする場合に向く
+func NewHeNormalInitializer() *VarianceScalingInitializer {
+	return &VarianceScalingInitializer{scale: 2}
+}
+
+// NewHeUniformInitializer : Heの初期値（一様分布）のInitializerを取得
+func NewHeUniformInitializer() *VarianceScalingInitializer {
+	return &VarianceScalingInitializer{scale: 2, uniform: true}
+}
+
+// NewLeCunNormalInitializer : LeCunの初期値（正規分布）のInitializerを取得
+// 分散は 1 / fanIn
+func NewLeCunNormalInitializer() *VarianceScalingInitializer {
+	return &VarianceScalingInitializer{scale: 1}
+}
+
+// NewLeCunUniformInitializer : LeCunの初期値（一様分布）のInitializerを取得
+func NewLeCunUniformInitializer() *VarianceScalingInitializer {
+	return &VarianceScalingInitializer{scale: 1, uniform: true}
+}
+
+func (v *VarianceScalingInitializer) Initialize(rnd *rand.Rand, rows, cols, fanIn, fanOut int) []float64 {
+	fan := float64(fanIn)
+	if v.fanAvg {
+		fan = float64(fanIn+fanOut) / 2
+	}
+	variance := v.scale / math.Max(1, fan)
+	if v.uniform {
+		// [-limit, limit)の一様分布の分散はlimit^2 / 3
+		limit := math.Sqrt(3 * variance)
+		return NewUniformInitializer(-limit, limit).Initialize(rnd, rows, cols, fanIn, fanOut)
+	}
+	return NewNormalInitializer(math.Sqrt(variance)).Initialize(rnd, rows, cols, fanIn, fanOut)
+}
+
+// OrthogonalInitializer : 直交行列（行・列のうち少ない方が正規直交）で初期化する
+// 正規分布の行列をQR分解して得られる直交行列をgain倍する
+type OrthogonalInitializer struct {
+	gain float64
+}
+
+// NewOrthogonalInitializer : OrthogonalInitializerを取得
+func NewOrthogonalInitializer(gain float64) *OrthogonalInitializer {
+	return &OrthogonalInitializer{gain: gain}
+}
+
+func (o *OrthogonalInitializer) Initialize(rnd *rand.Rand, rows, cols, fanIn, fanOut int) []float64 {
+	// QR分解は行数 >= 列数の行列に対して行うため、必要に応じて転置して扱う
+	m, n := rows, cols
+	if m < n {
+		m, n = n, m
+	}
+	a := mat.NewDense(m, n, NewNormalInitializer(1).Initialize(rnd, m, n, fanIn, fanOut))
+	var qr mat.QR
+	qr.Factorize(a)
+	q := qr.QTo(nil)
+	r := qr.RTo(nil)
+
+	// Rの対角成分の符号を掛けて、分布が一様になるようにする
+	values := make([]float64, rows*cols)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			v := q.At(i, j) * o.gain
+			if r.At(j, j) < 0 {
+				v = -v
+			}
+			if rows >= cols {
+				values[i*cols+j] = v
+			} else {
+				values[j*cols+i] = v
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,105 @@
+package neuralNetwork
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitializers(t *testing.T) {
+	Convey("Given : シード1の乱数生成器が与えられた時", t, func() {
+		rnd := rand.New(rand.NewSource(1))
+		Convey("When : 入力数200, 出力数100のHeの初期値（正規分布）で初期化する", func() {
+			values := NewHeNormalInitializer().Initialize(rnd, 200, 100, 200, 100)
+			Convey("Then : 標準偏差がsqrt(2 / 200)に近いこと", func() {
+				So(len(values), ShouldEqual, 200*100)
+				So(stddev(values), ShouldAlmostEqual, math.Sqrt(2.0/200), 0.005)
+			})
+		})
+		Convey("When : 入力数200, 出力数100のXavierの初期値（一様分布）で初期化する", func() {
+			values := NewXavierUniformInitializer().Initialize(rnd, 200, 100, 200, 100)
+			Convey("Then : 値がsqrt(6 / (200 + 100))以内で、標準偏差がsqrt(2 / (200 + 100))に近いこと", func() {
+				limit := math.Sqrt(6.0 / 300)
+				for _, v := range values {
+					So(math.Abs(v), ShouldBeLessThanOrEqualTo, limit)
+				}
+				So(stddev(values), ShouldAlmostEqual, math.Sqrt(2.0/300), 0.005)
+			})
+		})
+		Convey("When : 入力数50のLeCunの初期値（正規分布）で初期化する", func() {
+			values := NewLeCunNormalInitializer().Initialize(rnd, 50, 400, 50, 400)
+			Convey("Then : 標準偏差がsqrt(1 / 50)に近いこと", func() {
+				So(stddev(values), ShouldAlmostEqual, math.Sqrt(1.0/50), 0.005)
+			})
+		})
+		Convey("When : [-0.5, 0.5)の一様分布, 0で初期化する", func() {
+			uniform := NewUniformInitializer(-0.5, 0.5).Initialize(rnd, 10, 10, 10, 10)
+			zeros := NewZerosInitializer().Initialize(rnd, 3, 4, 3, 4)
+			Convey("Then : 指定した範囲・値で初期化されること", func() {
+				for _, v := range uniform {
+					So(v, ShouldBeBetweenOrEqual, -0.5, 0.5)
+				}
+				So(zeros, ShouldResemble, make([]float64, 12))
+			})
+		})
+		Convey("When : 直交行列で初期化する", func() {
+			Convey("Then : 縦長の行列では列ベクトルが正規直交であること", func() {
+				w := mat.NewDense(6, 3, NewOrthogonalInitializer(1).Initialize(rnd, 6, 3, 6, 3))
+				wtw := mat.NewDense(3, 3, nil)
+				wtw.Mul(w.T(), w)
+				So(mat.EqualApprox(wtw, identity(3), math.Pow10(-10)), ShouldBeTrue)
+			})
+			Convey("Then : 横長の行列では行ベクトルがgain倍の直交ベクトルであること", func() {
+				w := mat.NewDense(3, 6, NewOrthogonalInitializer(2).Initialize(rnd, 3, 6, 3, 6))
+				wwt := mat.NewDense(3, 3, nil)
+				wwt.Mul(w, w.T())
+				expected := mat.NewDense(3, 3, nil)
+				expected.Scale(4, identity(3))
+				So(mat.EqualApprox(wwt, expected, math.Pow10(-10)), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given : 同じシードを指定したAffineが2つ与えられた時", t, func() {
+		a1 := NewAffine(4, 3, WithAffineSeed(10), WithAffineWeightInitializer(NewHeNormalInitializer()))
+		a2 := NewAffine(4, 3, WithAffineSeed(10), WithAffineWeightInitializer(NewHeNormalInitializer()))
+		Convey("Then : 重み・バイアスが同一であること", func() {
+			So(mat.Equal(a1.GetParams()["w"], a2.GetParams()["w"]), ShouldBeTrue)
+			So(mat.Equal(a1.GetParams()["b"], a2.GetParams()["b"]), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given : バイアスを0で初期化するConv2Dが与えられた時", t, func() {
+		conv := NewConv2D(2, 3, 3, WithConv2DBiasInitializer(NewZerosInitializer()),
+			WithConv2DWeightInitializer(NewHeUniformInitializer()), WithConv2DInputShape(NewShape(2, 5, 5)))
+		Convey("Then : バイアスが0で、重みがHeの初期値の範囲内であること", func() {
+			So(mat.Sum(conv.GetParams()["b"]), ShouldEqual, 0)
+			limit := math.Sqrt(3 * 2.0 / (2 * 3 * 3))
+			So(mat.Max(conv.GetParams()["w"]), ShouldBeLessThanOrEqualTo, limit)
+			So(mat.Min(conv.GetParams()["w"]), ShouldBeGreaterThanOrEqualTo, -limit)
+		})
+	})
+}
+
+func stddev(values []float64) float64 {
+	mean, variance := 0.0, 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
+
+func identity(n int) *mat.Dense {
+	d := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		d.Set(i, i, 1)
+	}
+	return d
+}
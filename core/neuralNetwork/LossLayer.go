@@ -0,0 +1,276 @@
+package neuralNetwork
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LossLayer : ニューラルネットワークの最終層（出力の変換と損失の計算を行う素子）のIF
+type LossLayer interface {
+	// Forward : 入力xと正解データtから損失と正解率を算出
+	Forward(x mat.Matrix, t mat.Matrix) (loss float64, accuracy float64)
+	// Backward : 直前のForward処理の損失に対する入力xの勾配を算出
+	Backward() mat.Matrix
+	// Predict : 正解データなしで最終層の出力（Softmaxであれば各クラスの確率）を取得
+	Predict(x mat.Matrix) mat.Matrix
+}
+
+const (
+	// DefaultHuberDelta : Huber損失で二乗誤差と絶対誤差を切り替える閾値のデフォルト値
+	DefaultHuberDelta = 1.0
+	// DefaultMultiClassHingeMargin : 多クラスヒンジ損失のデフォルトのマージン
+	DefaultMultiClassHingeMargin = 1.0
+)
+
+// MeanSquaredError : 恒等関数と二乗和誤差による最終層（回帰向け）
+// 損失は 0.5 * Σ(x - t)^2 をバッチサイズで平均したもの
+// 正解率は定義できないため、常に0を返す
+type MeanSquaredError struct {
+	x mat.Matrix
+	t mat.Matrix
+}
+
+// NewMeanSquaredError : MeanSquaredErrorの素子を取得
+func NewMeanSquaredError() *MeanSquaredError {
+	m := &MeanSquaredError{}
+	return m
+}
+
+func (m *MeanSquaredError) Forward(x mat.Matrix, t mat.Matrix) (loss float64, accuracy float64) {
+	checkLossDims(x, t)
+	m.x = x
+	m.t = t
+	r, c := x.Dims()
+	diff := mat.NewDense(r, c, nil)
+	diff.Sub(x, t)
+	diff.MulElem(diff, diff)
+	return 0.5 * mat.Sum(diff) / float64(r), 0
+}
+
+func (m *MeanSquaredError) Backward() mat.Matrix {
+	r, c := m.x.Dims()
+	dense := mat.NewDense(r, c, nil)
+	dense.Sub(m.x, m.t)
+	dense.Scale(1/float64(r), dense)
+	return dense
+}
+
+func (m *MeanSquaredError) Predict(x mat.Matrix) mat.Matrix {
+	return mat.DenseCopyOf(x)
+}
+
+// SigmoidWithBinaryCrossEntropy : 要素毎のシグモイド関数と二値交差エントロピー誤差による最終層（マルチラベル分類向け）
+// 正解率は出力0.5を閾値としたラベル毎の正解率
+type SigmoidWithBinaryCrossEntropy struct {
+	out mat.Matrix
+	t   mat.Matrix
+}
+
+// NewSigmoidWithBinaryCrossEntropy : SigmoidWithBinaryCrossEntropyの素子を取得
+func NewSigmoidWithBinaryCrossEntropy() *SigmoidWithBinaryCrossEntropy {
+	s := &SigmoidWithBinaryCrossEntropy{}
+	return s
+}
+
+func (s *SigmoidWithBinaryCrossEntropy) Forward(x mat.Matrix, t mat.Matrix) (loss float64, accuracy float64) {
+	checkLossDims(x, t)
+	s.t = t
+	s.out = s.Predict(x)
+	r, c := x.Dims()
+	dense := mat.NewDense(r, c, nil)
+	correct := 0
+	dense.Apply(func(i, j int, v float64) float64 {
+		y := s.out.At(i, j)
+		if (y >= 0.5) == (v >= 0.5) {
+			correct++
+		}
+		return -(v*math.Log(y+delta) + (1-v)*math.Log(1-y+delta))
+	}, t)
+	return mat.Sum(dense) / float64(r), float64(correct) / float64(r*c)
+}
+
+func (s *SigmoidWithBinaryCrossEntropy) Backward() mat.Matrix {
+	r, c := s.t.Dims()
+	dense := mat.NewDense(r, c, nil)
+	dense.Sub(s.out, s.t)
+	dense.Scale(1/float64(r), dense)
+	return dense
+}
+
+func (s *SigmoidWithBinaryCrossEntropy) Predict(x mat.Matrix) mat.Matrix {
+	r, c := x.Dims()
+	dense := mat.NewDense(r, c, nil)
+	dense.Apply(func(i, j int, v float64) float64 {
+		return 1.0 / (1.0 + math.Exp(-v))
+	}, x)
+	return dense
+}
+
+// Huber : 恒等関数とHuber損失による最終層（外れ値を含む回帰向け）
+// 誤差の絶対値がdelta以下では二乗誤差、deltaより大きい場合は絶対誤差として扱う
+// 正解率は定義できないため、常に0を返す
+type Huber struct {
+	delta float64
+	x     mat.Matrix
+	t     mat.Matrix
+}
+
+// HuberOption : Huberのオプション
+type HuberOption func(*Huber)
+
+// NewHuber : Huberの素子を取得
+// 初期化時にオプション指定が可能
+func NewHuber(options ...HuberOption) *Huber {
+	h := Huber{delta: DefaultHuberDelta}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&h)
+	}
+	if h.delta <= 0 {
+		panic("deltaは0より大きい値を指定してください")
+	}
+	return &h
+}
+
+// WithHuberDelta : 二乗誤差と絶対誤差を切り替える閾値指定のオプションを取得
+func WithHuberDelta(delta float64) HuberOption {
+	return func(h *Huber) {
+		h.delta = delta
+	}
+}
+
+func (h *Huber) Forward(x mat.Matrix, t mat.Matrix) (loss float64, accuracy float64) {
+	checkLossDims(x, t)
+	h.x = x
+	h.t = t
+	r, c := x.Dims()
+	dense := mat.NewDense(r, c, nil)
+	dense.Apply(func(i, j int, v float64) float64 {
+		e := math.Abs(v - t.At(i, j))
+		if e <= h.delta {
+			return 0.5 * e * e
+		}
+		return h.delta * (e - 0.5*h.delta)
+	}, x)
+	return mat.Sum(dense) / float64(r), 0
+}
+
+func (h *Huber) Backward() mat.Matrix {
+	r, c := h.x.Dims()
+	dense := mat.NewDense(r, c, nil)
+	dense.Apply(func(i, j int, v float64) float64 {
+		e := v - h.t.At(i, j)
+		if math.Abs(e) > h.delta {
+			e = math.Copysign(h.delta, e)
+		}
+		return e / float64(r)
+	}, h.x)
+	return dense
+}
+
+func (h *Huber) Predict(x mat.Matrix) mat.Matrix {
+	return mat.DenseCopyOf(x)
+}
+
+// GetDelta : 二乗誤差と絶対誤差を切り替える閾値を取得
+func (h *Huber) GetDelta() float64 {
+	return h.delta
+}
+
+// MultiClassHinge : 多クラスヒンジ損失による最終層（SVM型の多クラス分類向け）
+// 損失は Σ(j≠正解) max(0, x_j - x_正解 + margin) をバッチサイズで平均したもの
+// 正解データtはone-hot形式で与えること
+type MultiClassHinge struct {
+	margin float64
+	x      mat.Matrix
+	t      mat.Matrix
+}
+
+// MultiClassHingeOption : MultiClassHingeのオプション
+type MultiClassHingeOption func(*MultiClassHinge)
+
+// NewMultiClassHinge : MultiClassHingeの素子を取得
+// 初期化時にオプション指定が可能
+func NewMultiClassHinge(options ...MultiClassHingeOption) *MultiClassHinge {
+	h := MultiClassHinge{margin: DefaultMultiClassHingeMargin}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&h)
+	}
+	return &h
+}
+
+// WithMultiClassHingeMargin : マージン指定のオプションを取得
+func WithMultiClassHingeMargin(margin float64) MultiClassHingeOption {
+	return func(h *MultiClassHinge) {
+		h.margin = margin
+	}
+}
+
+func (h *MultiClassHinge) Forward(x mat.Matrix, t mat.Matrix) (loss float64, accuracy float64) {
+	checkLossDims(x, t)
+	h.x = x
+	h.t = t
+	r, c := x.Dims()
+	sum := 0.0
+	for i := 0; i < r; i++ {
+		label := h.label(i)
+		for j := 0; j < c; j++ {
+			if j != label {
+				sum += math.Max(0, x.At(i, j)-x.At(i, label)+h.margin)
+			}
+		}
+	}
+	return sum / float64(r), calcAccuracy(x, t)
+}
+
+func (h *MultiClassHinge) Backward() mat.Matrix {
+	r, c := h.x.Dims()
+	dense := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		label := h.label(i)
+		for j := 0; j < c; j++ {
+			if j == label || h.x.At(i, j)-h.x.At(i, label)+h.margin <= 0 {
+				continue
+			}
+			// マージンを満たしていないクラスの値を下げ、正解クラスの値を上げる
+			dense.Set(i, j, 1/float64(r))
+			dense.Set(i, label, dense.At(i, label)-1/float64(r))
+		}
+	}
+	return dense
+}
+
+func (h *MultiClassHinge) Predict(x mat.Matrix) mat.Matrix {
+	return mat.DenseCopyOf(x)
+}
+
+// GetMargin : マージンを取得
+func (h *MultiClassHinge) GetMargin() float64 {
+	return h.margin
+}
+
+// label : i番目のデータの正解クラス（正解データの最大値のインデックス）を取得
+func (h *MultiClassHinge) label(i int) int {
+	_, c := h.t.Dims()
+	label := 0
+	for j := 1; j < c; j++ {
+		if h.t.At(i, j) > h.t.At(i, label) {
+			label = j
+		}
+	}
+	return label
+}
+
+// checkLossDims : 入力と正解データの行列の形が同じかを確認
+func checkLossDims(x mat.Matrix, t mat.Matrix) {
+	xr, xc := x.Dims()
+	tr, tc := t.Dims()
+	if xr != tr || xc != tc {
+		panic(fmt.Sprintf("入力(%d, %d)と正解データ(%d, %d)の行列の形がマッチしてません", xr, xc, tr, tc))
+	}
+}
@@ -0,0 +1,126 @@
+package neuralNetwork
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLossLayers(t *testing.T) {
+	Convey("Given : 入力xと正解データtを2*3行列とする", t, func() {
+		x := mat.NewDense(2, 3, []float64{0.5, -1, 2, 3, 0.2, -2.5})
+		tr := mat.NewDense(2, 3, []float64{1, 0, 0, 0, 0, 1})
+
+		Convey("When : MeanSquaredErrorでForward処理を行う", func() {
+			layer := NewMeanSquaredError()
+			loss, acc := layer.Forward(x, tr)
+			Convey("Then : 二乗和誤差の半分をバッチサイズで平均した値が出力されること", func() {
+				// (0.25 + 1 + 4 + 9 + 0.04 + 12.25) * 0.5 / 2
+				So(loss, ShouldAlmostEqual, 6.635, math.Pow10(-10))
+				So(acc, ShouldEqual, 0)
+			})
+			Convey("Then : Backward処理の結果が数値微分の結果と一致すること", func() {
+				So(mat.EqualApprox(layer.Backward(), numericalLossGradient(layer, x, tr), math.Pow10(-5)), ShouldBeTrue)
+			})
+			Convey("Then : Predictでは入力がそのまま出力されること", func() {
+				So(mat.Equal(layer.Predict(x), x), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : SigmoidWithBinaryCrossEntropyでForward処理を行う", func() {
+			layer := NewSigmoidWithBinaryCrossEntropy()
+			loss, acc := layer.Forward(x, tr)
+			Convey("Then : 二値交差エントロピー誤差とラベル毎の正解率が出力されること", func() {
+				expected := 0.0
+				for i := 0; i < 2; i++ {
+					for j := 0; j < 3; j++ {
+						y := 1 / (1 + math.Exp(-x.At(i, j)))
+						expected -= tr.At(i, j)*math.Log(y) + (1-tr.At(i, j))*math.Log(1-y)
+					}
+				}
+				So(loss, ShouldAlmostEqual, expected/2, math.Pow10(-5))
+				// 正解 : (0, 0), (0, 1) の2要素
+				So(acc, ShouldAlmostEqual, 2.0/6, math.Pow10(-10))
+			})
+			Convey("Then : Backward処理の結果が数値微分の結果と一致すること", func() {
+				So(mat.EqualApprox(layer.Backward(), numericalLossGradient(layer, x, tr), math.Pow10(-5)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : delta1.0のHuberでForward処理を行う", func() {
+			layer := NewHuber(WithHuberDelta(1.0))
+			loss, _ := layer.Forward(x, tr)
+			Convey("Then : 誤差の大きさに応じて二乗誤差・絶対誤差が使われること", func() {
+				// 0.125 + 0.5 + 1.5 + 2.5 + 0.02 + 3
+				So(loss, ShouldAlmostEqual, 7.645/2, math.Pow10(-10))
+			})
+			Convey("Then : Backward処理の結果が数値微分の結果と一致すること", func() {
+				So(mat.EqualApprox(layer.Backward(), numericalLossGradient(layer, x, tr), math.Pow10(-5)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : MultiClassHingeでForward処理を行う", func() {
+			layer := NewMultiClassHinge()
+			loss, acc := layer.Forward(x, tr)
+			Convey("Then : マージンを満たさないクラス分の損失と正解率が出力されること", func() {
+				// 1件目 : max(0, -1-0.5+1) + max(0, 2-0.5+1) = 2.5
+				// 2件目 : max(0, 3+2.5+1) + max(0, 0.2+2.5+1) = 10.2
+				So(loss, ShouldAlmostEqual, 12.7/2, math.Pow10(-10))
+				So(acc, ShouldEqual, 0)
+			})
+			Convey("Then : Backward処理の結果が数値微分の結果と一致すること", func() {
+				So(mat.EqualApprox(layer.Backward(), numericalLossGradient(layer, x, tr), math.Pow10(-5)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 行列の形が異なる正解データを与える", func() {
+			Convey("Then : panicが発生すること", func() {
+				So(func() { NewMeanSquaredError().Forward(x, mat.NewDense(2, 2, nil)) }, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given : 最終層をMeanSquaredErrorにしたNNが与えられた時", t, func() {
+		nnLayers := NewDefaultNeuralNetworkLayers()
+		nnLayers.Add(NewAffine(2, 1))
+		nnLayers.SetLastActivationLayer(NewMeanSquaredError())
+		Convey("When : y = x1 + 2 * x2 を学習する", func() {
+			x := mat.NewDense(4, 2, []float64{0, 0, 1, 0, 0, 1, 1, 1})
+			tr := mat.NewDense(4, 1, []float64{0, 1, 2, 3})
+			nnLayers.SetOptimizer(NewSGD(WithSGDLearningRate(0.1)))
+			first, _ := nnLayers.Forward(x, tr)
+			last := first
+			for i := 0; i < 200; i++ {
+				last, _ = nnLayers.Forward(x, tr)
+				nnLayers.Backward()
+				nnLayers.Update()
+			}
+			Convey("Then : 損失が減少すること", func() {
+				So(last, ShouldBeLessThan, first/10)
+			})
+		})
+	})
+}
+
+// numericalLossGradient : 損失の入力xに対する勾配を中心差分で求める
+func numericalLossGradient(layer LossLayer, x mat.Matrix, t mat.Matrix) mat.Matrix {
+	h := 1e-5
+	r, c := x.Dims()
+	grad := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			plus := mat.DenseCopyOf(x)
+			plus.Set(i, j, x.At(i, j)+h)
+			minus := mat.DenseCopyOf(x)
+			minus.Set(i, j, x.At(i, j)-h)
+			lossPlus, _ := layer.Forward(plus, t)
+			lossMinus, _ := layer.Forward(minus, t)
+			grad.Set(i, j, (lossPlus-lossMinus)/(2*h))
+		}
+	}
+	// 元の入力でForward処理をやり直し、Backward処理に備える
+	layer.Forward(x, t)
+	return grad
+}
@@ -1,6 +1,9 @@
 package neuralNetwork
 
 import (
+	"fmt"
+	"math/rand"
+
 	"github.com/goMLLibrary/core/util"
 	"gonum.org/v1/gonum/mat"
 )
@@ -25,29 +28,118 @@ type NeuralNetworkLayer interface {
 }
 
 type Affine struct {
-	w  mat.Matrix
-	b  mat.Vector
-	x  mat.Matrix
-	dw mat.Matrix
-	db mat.Vector
+	w          mat.Matrix
+	b          mat.Vector
+	x          mat.Matrix
+	dw         mat.Matrix
+	db         mat.Vector
+	outputSize int
+
+	weightInitializer Initializer
+	biasInitializer   Initializer
+	rnd               *rand.Rand
 }
 
+// AffineOption : Affineのオプション
+type AffineOption func(*Affine)
+
 // NewAffine : アフィン変換の素子を取得
-func NewAffine(inputSize, outputSize int) *Affine {
-	w := mat.NewDense(inputSize, outputSize, util.NormRandomArray(0.01, outputSize*inputSize))
-	b := mat.NewVecDense(outputSize, util.NormRandomArray(0.01, outputSize))
-	a := Affine{}
-	a.w = w
-	a.b = b
+// 初期化時にオプション指定が可能
+func NewAffine(inputSize, outputSize int, options ...AffineOption) *Affine {
+	a := NewAffineWithOutputSize(outputSize, options...)
+	a.initParams(inputSize)
+	return a
+}
+
+// NewAffineWithOutputSize : 出力サイズのみ指定してアフィン変換の素子を取得
+// 入力サイズはNeuralNetworkLayersに追加した時点の入力データの形状から決定する
+// 初期化時にオプション指定が可能
+func NewAffineWithOutputSize(outputSize int, options ...AffineOption) *Affine {
+	a := Affine{
+		outputSize:        outputSize,
+		weightInitializer: NewDefaultInitializer(),
+		biasInitializer:   NewDefaultInitializer(),
+	}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&a)
+	}
+	if a.rnd == nil {
+		a.rnd = util.NewRand()
+	}
 	return &a
 }
 
-func newAffine(w mat.Matrix, b mat.Vector) *Affine {
-	a := Affine{w: w, b: b}
+// WithAffineWeightInitializer : 重みの初期化方法指定のオプションを取得
+func WithAffineWeightInitializer(initializer Initializer) AffineOption {
+	return func(a *Affine) {
+		a.weightInitializer = initializer
+	}
+}
+
+// WithAffineBiasInitializer : バイアスの初期化方法指定のオプションを取得
+func WithAffineBiasInitializer(initializer Initializer) AffineOption {
+	return func(a *Affine) {
+		a.biasInitializer = initializer
+	}
+}
+
+// WithAffineSeed : 初期化に利用する乱数のシード指定のオプションを取得
+func WithAffineSeed(seed int64) AffineOption {
+	return func(a *Affine) {
+		a.rnd = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithAffineRand : 初期化に利用する乱数生成器指定のオプションを取得
+func WithAffineRand(rnd *rand.Rand) AffineOption {
+	return func(a *Affine) {
+		a.rnd = rnd
+	}
+}
+
+// initParams : 入力サイズに合わせて重み・バイアスを初期化する
+func (aff *Affine) initParams(inputSize int) {
+	aff.w = mat.NewDense(inputSize, aff.outputSize,
+		aff.weightInitializer.Initialize(aff.rnd, inputSize, aff.outputSize, inputSize, aff.outputSize))
+	aff.b = mat.NewVecDense(aff.outputSize,
+		aff.biasInitializer.Initialize(aff.rnd, aff.outputSize, 1, inputSize, aff.outputSize))
+}
+
+// NewAffineWithParams : 学習済みの重み・バイアスを指定してアフィン変換の素子を取得
+// w : (入力サイズ, 出力サイズ)の重み, b : (出力サイズ)のバイアス
+// 乱数での初期化を行わないため、util.SetSeedで設定したライブラリ全体の乱数に影響しない
+func NewAffineWithParams(w mat.Matrix, b mat.Vector) *Affine {
+	_, outputSize := w.Dims()
+	if b.Len() != outputSize {
+		panic(fmt.Sprintf("バイアスの要素数%dと出力サイズ%dがマッチしてません", b.Len(), outputSize))
+	}
+	a := Affine{
+		w:                 w,
+		b:                 b,
+		outputSize:        outputSize,
+		weightInitializer: NewDefaultInitializer(),
+		biasInitializer:   NewDefaultInitializer(),
+	}
 	return &a
 }
 
+// SetInputShape : 入力データの形状を設定し、出力データの形状を返す
+// 入力サイズが未決定の場合は、入力データの要素数に合わせて重みを初期化する
+func (aff *Affine) SetInputShape(shape Shape) Shape {
+	if aff.w == nil {
+		aff.initParams(shape.Size())
+	} else if inputSize, _ := aff.w.Dims(); inputSize != shape.Size() {
+		panic(fmt.Sprintf("Affineの入力サイズ%dと入力データの形状%sがマッチしてません", inputSize, shape))
+	}
+	return NewFlatShape(aff.outputSize)
+}
+
 func (aff *Affine) Forward(x mat.Matrix) mat.Matrix {
+	if aff.w == nil {
+		panic("Affineの入力サイズが決定していません")
+	}
 	aff.x = x
 	batchSize, _ := aff.x.Dims()
 	_, outputSize := aff.w.Dims()
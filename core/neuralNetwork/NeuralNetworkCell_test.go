@@ -14,7 +14,7 @@ func TestAffine(t *testing.T) {
 		w := mat.NewDense(3, 2, util.CreateFloatArrayByStep(6, 1, 1))
 		Convey("AND : バイアスが2次元, 初期値は-2,-1とする", nil)
 		b := mat.NewVecDense(2, []float64{-2, -1})
-		aff := newAffine(w, b)
+		aff := NewAffineWithParams(w, b)
 		Convey("When : 入力xを2*3行列とし、値を5-10とする", func() {
 			x := mat.NewDense(2, 3, util.CreateFloatArrayByStep(6, 5, 1))
 			out := aff.Forward(x)
@@ -1,12 +1,21 @@
 package neuralNetwork
 
-import "gonum.org/v1/gonum/mat"
+import (
+	"fmt"
+
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
 
 // NeuralNetworkLayers : ニューラルネットワークの素子を複数持つ多重層
 type NeuralNetworkLayers struct {
 	layers              []NeuralNetworkBaseLayer
-	lastActivationLayer *SoftmaxWithLoss
+	lastActivationLayer LossLayer
 	optimizer           Optimizer
+	scheduler           LearningRateScheduler
+	inputShape          Shape
+	outputShape         Shape
+	training            bool
 }
 
 // NewDefaultNeuralNetworkLayers : NeuralNetworkLayersのインスタンスを作成
@@ -15,12 +24,77 @@ func NewDefaultNeuralNetworkLayers() *NeuralNetworkLayers {
 	nnl.layers = make([]NeuralNetworkBaseLayer, 0)
 	nnl.lastActivationLayer = NewSoftmaxWithLoss()
 	nnl.optimizer = NewSGD()
+	nnl.training = true
 	return &nnl
 }
 
 // Add : ニューラルネットワークの素子を追加
+// 入力データの形状が設定済みの場合は、追加した素子に形状を伝搬する
 func (nnl *NeuralNetworkLayers) Add(layer NeuralNetworkBaseLayer) {
 	nnl.layers = append(nnl.layers, layer)
+	setLayerTrainingMode(layer, nnl.training)
+	if !nnl.outputShape.IsEmpty() {
+		nnl.outputShape = propagateShape(layer, nnl.outputShape)
+	}
+}
+
+// SetInputShape : 入力データの形状を設定し、追加済みの各素子に形状を伝搬する
+func (nnl *NeuralNetworkLayers) SetInputShape(shape Shape) {
+	nnl.inputShape = shape
+	nnl.outputShape = shape
+	for _, layer := range nnl.layers {
+		nnl.outputShape = propagateShape(layer, nnl.outputShape)
+	}
+}
+
+// GetInputShape : 入力データの形状を取得（未設定の場合は空の形状）
+func (nnl *NeuralNetworkLayers) GetInputShape() Shape {
+	return nnl.inputShape
+}
+
+// GetOutputShape : 最終層の手前の素子が出力するデータの形状を取得（未設定の場合は空の形状）
+func (nnl *NeuralNetworkLayers) GetOutputShape() Shape {
+	return nnl.outputShape
+}
+
+// SetTrainingMode : 学習時の挙動に切り替える（デフォルト）
+func (nnl *NeuralNetworkLayers) SetTrainingMode() {
+	nnl.setTrainingMode(true)
+}
+
+// SetEvaluationMode : 推論（評価）時の挙動に切り替える
+// BatchNormalizationなど学習時と推論時で挙動が異なる素子に反映される
+func (nnl *NeuralNetworkLayers) SetEvaluationMode() {
+	nnl.setTrainingMode(false)
+}
+
+// IsTraining : 学習時の挙動かどうか
+func (nnl *NeuralNetworkLayers) IsTraining() bool {
+	return nnl.training
+}
+
+func (nnl *NeuralNetworkLayers) setTrainingMode(training bool) {
+	nnl.training = training
+	for _, layer := range nnl.layers {
+		setLayerTrainingMode(layer, training)
+	}
+}
+
+// setLayerTrainingMode : 学習時と推論時で挙動が異なる素子であれば、挙動を切り替える
+func setLayerTrainingMode(layer NeuralNetworkBaseLayer, training bool) {
+	if modeLayer, ok := layer.(TrainingModeLayer); ok {
+		modeLayer.SetTrainingMode(training)
+	}
+}
+
+// propagateShape : 素子に入力データの形状を設定し、出力データの形状を返す
+func propagateShape(layer NeuralNetworkBaseLayer, shape Shape) Shape {
+	shapedLayer, ok := layer.(ShapedLayer)
+	if !ok {
+		// 形状を変えない素子のため、入力の形状をそのまま返す
+		return shape
+	}
+	return shapedLayer.SetInputShape(shape)
 }
 
 // SetOptimizer : optimizerの設定
@@ -28,18 +102,62 @@ func (nnl *NeuralNetworkLayers) SetOptimizer(optimizer Optimizer) {
 	nnl.optimizer = optimizer
 }
 
-// SetLastActivationLayer : ニューラルネットワークの最終層を設定
-func (nnl *NeuralNetworkLayers) SetLastActivationLayer(layer *SoftmaxWithLoss) {
+// SetScheduler : 学習率のスケジューラーの設定
+// 設定したスケジューラーはUpdateの度（1iteration毎）に1ステップ進める
+// epoch毎に進める場合は設定せず、学習のループ内でStepを呼ぶこと
+func (nnl *NeuralNetworkLayers) SetScheduler(scheduler LearningRateScheduler) {
+	nnl.scheduler = scheduler
+}
+
+// SetLastActivationLayer : ニューラルネットワークの最終層を設定（デフォルトはSoftmaxWithLoss）
+// 回帰の場合はMeanSquaredErrorやHuber, マルチラベル分類の場合はSigmoidWithBinaryCrossEntropyなどを設定する
+func (nnl *NeuralNetworkLayers) SetLastActivationLayer(layer LossLayer) {
 	nnl.lastActivationLayer = layer
 }
 
 // Forward : 順伝搬処理の実施
 func (nnl *NeuralNetworkLayers) Forward(x mat.Matrix, t mat.Matrix) (loss float64, accuracy float64) {
+	return nnl.lastActivationLayer.Forward(nnl.forwardLayers(x), t)
+}
+
+// Predict : 正解データなしで推論を行い、最終層に入力する値（ロジット）を取得
+// 推論時の挙動で処理し、処理後は元の挙動に戻す
+func (nnl *NeuralNetworkLayers) Predict(x mat.Matrix) mat.Matrix {
+	training := nnl.training
+	if training {
+		nnl.SetEvaluationMode()
+		defer nnl.SetTrainingMode()
+	}
+	return nnl.forwardLayers(x)
+}
+
+// PredictProba : 正解データなしで推論を行い、最終層の出力を取得
+// 最終層がSoftmaxWithLossであれば各クラスの確率、SigmoidWithBinaryCrossEntropyであれば各ラベルの確率となる
+func (nnl *NeuralNetworkLayers) PredictProba(x mat.Matrix) mat.Matrix {
+	return nnl.lastActivationLayer.Predict(nnl.Predict(x))
+}
+
+// PredictClass : 正解データなしで推論を行い、各データの予測クラス（確率が最大のインデックス）を取得
+func (nnl *NeuralNetworkLayers) PredictClass(x mat.Matrix) []int {
+	out := mat.DenseCopyOf(nnl.Predict(x))
+	r, _ := out.Dims()
+	classes := make([]int, r)
+	for i := 0; i < r; i++ {
+		classes[i], _ = util.MaxValue(out.RawRowView(i))
+	}
+	return classes
+}
+
+// forwardLayers : 最終層を除く各素子の順伝搬処理を実施
+func (nnl *NeuralNetworkLayers) forwardLayers(x mat.Matrix) mat.Matrix {
+	if _, c := x.Dims(); !nnl.inputShape.IsEmpty() && c != nnl.inputShape.Size() {
+		panic(fmt.Sprintf("入力データの要素数%dと入力データの形状%sがマッチしてません", c, nnl.inputShape))
+	}
 	var input mat.Matrix = mat.DenseCopyOf(x)
 	for _, layer := range nnl.layers {
 		input = layer.Forward(input)
 	}
-	return nnl.lastActivationLayer.Forward(input, t)
+	return input
 }
 
 // Backward : 逆伝搬処理の実施
@@ -63,10 +181,15 @@ func (nnl *NeuralNetworkLayers) Update() {
 		// 各パラメーターの更新処理
 		params := neuralNetworkLayer.GetParams()
 		grads := neuralNetworkLayer.GetGradients()
-		nnl.optimizer.Update(params, grads)
+		nnl.optimizer.Update(i, params, grads)
 		neuralNetworkLayer.UpdateParams(params)
 		nnl.layers[i] = neuralNetworkLayer
 	}
+
+	// 学習率のスケジューラーを進める
+	if nnl.scheduler != nil {
+		nnl.scheduler.Step()
+	}
 }
 
 // GetLayers : レイヤー情報を取得
@@ -79,7 +202,12 @@ func (nnl *NeuralNetworkLayers) GetOptimizer() Optimizer {
 	return nnl.optimizer
 }
 
+// GetScheduler : 学習率のスケジューラーを取得（未設定の場合はnil）
+func (nnl *NeuralNetworkLayers) GetScheduler() LearningRateScheduler {
+	return nnl.scheduler
+}
+
 // GetLastActivationLayer : 最終的な活性化レイヤーを取得
-func (nnl *NeuralNetworkLayers) GetLastActivationLayer() *SoftmaxWithLoss {
+func (nnl *NeuralNetworkLayers) GetLastActivationLayer() LossLayer {
 	return nnl.lastActivationLayer
 }
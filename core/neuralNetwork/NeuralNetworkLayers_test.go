@@ -0,0 +1,58 @@
+package neuralNetwork
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestNeuralNetworkLayersPredict(t *testing.T) {
+	Convey("Given : 2*3のAffineとDropoutを持つNNが与えられた時", t, func() {
+		nnLayers := NewDefaultNeuralNetworkLayers()
+		affine := NewAffine(2, 3)
+		params := make(map[string]mat.Matrix)
+		params["w"] = mat.NewDense(2, 3, []float64{1, 0, -1, 0, 1, 2})
+		params["b"] = mat.NewVecDense(3, []float64{0, 0, 0})
+		affine.UpdateParams(params)
+		nnLayers.Add(affine)
+		nnLayers.Add(NewDropout(0.5, WithDropoutSeed(1)))
+
+		Convey("AND : 入力xを2*2行列とする", nil)
+		// 推論時のロジット : [1, 1, 1] * 0.5, [3, -1, -5] * 0.5
+		x := mat.NewDense(2, 2, []float64{1, 1, 3, -1})
+		Convey("When : 正解データなしでPredictを行う", func() {
+			out := nnLayers.Predict(x)
+			Convey("Then : 推論時の挙動でロジットが出力されること", func() {
+				expected := mat.NewDense(2, 3, []float64{0.5, 0.5, 0.5, 1.5, -0.5, -2.5})
+				So(mat.EqualApprox(out, expected, math.Pow10(-10)), ShouldBeTrue)
+			})
+			Convey("Then : 処理後は学習時の挙動に戻っていること", func() {
+				So(nnLayers.IsTraining(), ShouldBeTrue)
+			})
+		})
+		Convey("When : PredictProbaを行う", func() {
+			proba := nnLayers.PredictProba(x)
+			Convey("Then : 各データの確率の合計が1になること", func() {
+				for i := 0; i < 2; i++ {
+					So(mat.Sum(proba.(*mat.Dense).RowView(i)), ShouldAlmostEqual, 1, math.Pow10(-10))
+				}
+				So(proba.At(0, 0), ShouldAlmostEqual, 1.0/3, math.Pow10(-10))
+			})
+		})
+		Convey("When : PredictClassを行う", func() {
+			classes := nnLayers.PredictClass(x)
+			Convey("Then : 各データの最大値のインデックスが出力されること", func() {
+				So(classes, ShouldResemble, []int{0, 0})
+			})
+		})
+		Convey("When : 推論時の挙動に切り替えた後にPredictを行う", func() {
+			nnLayers.SetEvaluationMode()
+			nnLayers.Predict(x)
+			Convey("Then : 推論時の挙動のままであること", func() {
+				So(nnLayers.IsTraining(), ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,265 @@
+package neuralNetwork
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TrainingModeLayer : 学習時と推論時で挙動が異なるレイヤーのIF
+type TrainingModeLayer interface {
+	// SetTrainingMode : trueの場合は学習時、falseの場合は推論時の挙動に切り替える
+	SetTrainingMode(training bool)
+}
+
+// StatefulLayer : 学習対象のパラメーター以外に、推論に必要な状態を持つレイヤーのIF
+type StatefulLayer interface {
+	// GetStates : 各種状態を取得
+	GetStates() map[string]mat.Matrix
+	// SetStates : 各種状態を更新
+	SetStates(map[string]mat.Matrix)
+}
+
+const (
+	// DefaultBatchNormalizationMomentum : 推論用の平均・分散を更新する際のデフォルトの減衰率
+	DefaultBatchNormalizationMomentum = 0.9
+	// DefaultBatchNormalizationEpsilon : 0除算を防ぐための微小値のデフォルト値
+	DefaultBatchNormalizationEpsilon = 1e-5
+)
+
+// BatchNormalization : ミニバッチ単位で入力を正規化するレイヤー
+// 入力データの形状が画像（高さ・幅が1以外）の場合はチャネル毎、1次元の場合は要素毎に正規化する
+type BatchNormalization struct {
+	gamma  mat.Vector
+	beta   mat.Vector
+	dgamma mat.Vector
+	dbeta  mat.Vector
+
+	runningMean *mat.VecDense
+	runningVar  *mat.VecDense
+	momentum    float64
+	epsilon     float64
+	training    bool
+	groupSize   int // 1チャネルあたりの要素数（高さ*幅）
+
+	// 逆伝搬で利用する値
+	xn  *mat.Dense
+	std []float64
+}
+
+// BatchNormalizationOption : BatchNormalizationのオプション
+type BatchNormalizationOption func(*BatchNormalization)
+
+// NewBatchNormalization : BatchNormalizationの素子を取得
+// 正規化の単位はNeuralNetworkLayersに追加した時点の入力データの形状から決定する
+// 形状が未設定の場合は、最初のForward処理時に要素毎の正規化として初期化する
+// 初期化時にオプション指定が可能
+func NewBatchNormalization(options ...BatchNormalizationOption) *BatchNormalization {
+	bn := BatchNormalization{
+		momentum:  DefaultBatchNormalizationMomentum,
+		epsilon:   DefaultBatchNormalizationEpsilon,
+		training:  true,
+		groupSize: 1,
+	}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&bn)
+	}
+	return &bn
+}
+
+// WithBatchNormalizationMomentum : 推論用の平均・分散を更新する際の減衰率指定のオプションを取得
+func WithBatchNormalizationMomentum(momentum float64) BatchNormalizationOption {
+	return func(bn *BatchNormalization) {
+		bn.momentum = momentum
+	}
+}
+
+// WithBatchNormalizationEpsilon : 0除算を防ぐための微小値指定のオプションを取得
+func WithBatchNormalizationEpsilon(epsilon float64) BatchNormalizationOption {
+	return func(bn *BatchNormalization) {
+		bn.epsilon = epsilon
+	}
+}
+
+// SetInputShape : 入力データの形状を設定し、出力データの形状を返す
+func (bn *BatchNormalization) SetInputShape(shape Shape) Shape {
+	bn.groupSize = shape.Height * shape.Width
+	bn.initParams(shape.Channel)
+	return shape
+}
+
+func (bn *BatchNormalization) SetTrainingMode(training bool) {
+	bn.training = training
+}
+
+// initParams : チャネル数に合わせてパラメーター・推論用の平均・分散を初期化する
+// 既にチャネル数分設定されているものはそのまま利用する
+func (bn *BatchNormalization) initParams(channel int) {
+	if bn.gamma == nil || bn.gamma.Len() != channel {
+		bn.gamma = filledVecDense(channel, 1)
+		bn.beta = mat.NewVecDense(channel, nil)
+	}
+	if bn.runningMean == nil || bn.runningMean.Len() != channel {
+		bn.runningMean = mat.NewVecDense(channel, nil)
+		bn.runningVar = filledVecDense(channel, 1)
+	}
+}
+
+func filledVecDense(n int, value float64) *mat.VecDense {
+	vec := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		vec.SetVec(i, value)
+	}
+	return vec
+}
+
+func (bn *BatchNormalization) Forward(x mat.Matrix) mat.Matrix {
+	batch, col := x.Dims()
+	if bn.gamma == nil {
+		bn.initParams(col)
+	} else if bn.runningMean == nil {
+		bn.initParams(bn.gamma.Len())
+	}
+	channel := bn.gamma.Len()
+	if col != channel*bn.groupSize {
+		panic(fmt.Sprintf("BatchNormalizationのチャネル数%dと入力データの要素数%dがマッチしてません", channel, col))
+	}
+
+	src := mat.DenseCopyOf(x)
+	xn := mat.NewDense(batch, col, nil)
+	out := mat.NewDense(batch, col, nil)
+	bn.std = make([]float64, channel)
+	count := float64(batch * bn.groupSize)
+	for c := 0; c < channel; c++ {
+		mean, variance := bn.runningMean.AtVec(c), bn.runningVar.AtVec(c)
+		if bn.training {
+			// ミニバッチ内の平均・分散を計算し、推論用の平均・分散を更新
+			mean, variance = 0, 0
+			bn.eachElement(batch, c, func(n, j int) {
+				mean += src.At(n, j)
+			})
+			mean /= count
+			bn.eachElement(batch, c, func(n, j int) {
+				variance += math.Pow(src.At(n, j)-mean, 2)
+			})
+			variance /= count
+			bn.runningMean.SetVec(c, bn.momentum*bn.runningMean.AtVec(c)+(1-bn.momentum)*mean)
+			bn.runningVar.SetVec(c, bn.momentum*bn.runningVar.AtVec(c)+(1-bn.momentum)*variance)
+		}
+
+		// 正規化した後、スケール・シフトを行う
+		std := math.Sqrt(variance + bn.epsilon)
+		bn.std[c] = std
+		bn.eachElement(batch, c, func(n, j int) {
+			v := (src.At(n, j) - mean) / std
+			xn.Set(n, j, v)
+			out.Set(n, j, bn.gamma.AtVec(c)*v+bn.beta.AtVec(c))
+		})
+	}
+	bn.xn = xn
+	return out
+}
+
+func (bn *BatchNormalization) Backward(dout mat.Matrix) mat.Matrix {
+	batch, col := dout.Dims()
+	channel := bn.gamma.Len()
+	dx := mat.NewDense(batch, col, nil)
+	dgamma := mat.NewVecDense(channel, nil)
+	dbeta := mat.NewVecDense(channel, nil)
+	count := float64(batch * bn.groupSize)
+	for c := 0; c < channel; c++ {
+		sumDout, sumDoutXn := 0.0, 0.0
+		bn.eachElement(batch, c, func(n, j int) {
+			sumDout += dout.At(n, j)
+			sumDoutXn += dout.At(n, j) * bn.xn.At(n, j)
+		})
+		dbeta.SetVec(c, sumDout)
+		dgamma.SetVec(c, sumDoutXn)
+
+		gamma := bn.gamma.AtVec(c)
+		std := bn.std[c]
+		bn.eachElement(batch, c, func(n, j int) {
+			if !bn.training {
+				// 推論時は平均・分散が定数のため、スケールのみ考慮する
+				dx.Set(n, j, dout.At(n, j)*gamma/std)
+				return
+			}
+			v := count*dout.At(n, j) - sumDout - bn.xn.At(n, j)*sumDoutXn
+			dx.Set(n, j, gamma*v/(count*std))
+		})
+	}
+	bn.dgamma = dgamma
+	bn.dbeta = dbeta
+	return dx
+}
+
+// eachElement : 指定したチャネルに属する要素毎に処理を行う
+func (bn *BatchNormalization) eachElement(batch int, channel int, fn func(n, j int)) {
+	for n := 0; n < batch; n++ {
+		for j := channel * bn.groupSize; j < (channel+1)*bn.groupSize; j++ {
+			fn(n, j)
+		}
+	}
+}
+
+func (bn *BatchNormalization) GetParams() map[string]mat.Matrix {
+	params := make(map[string]mat.Matrix)
+	params["gamma"] = bn.gamma
+	params["beta"] = bn.beta
+	return params
+}
+
+func (bn *BatchNormalization) GetGradients() map[string]mat.Matrix {
+	grads := make(map[string]mat.Matrix)
+	grads["gamma"] = bn.dgamma
+	grads["beta"] = bn.dbeta
+	return grads
+}
+
+func (bn *BatchNormalization) UpdateParams(params map[string]mat.Matrix) {
+	// パラメータのアップデート
+	if bn.gamma == nil {
+		r, _ := params["gamma"].Dims()
+		bn.initParams(r)
+	}
+	bn.gamma = mat.DenseCopyOf(params["gamma"]).ColView(0)
+	bn.beta = mat.DenseCopyOf(params["beta"]).ColView(0)
+
+	// 勾配のリセット
+	bn.dgamma = nil
+	bn.dbeta = nil
+}
+
+// GetStates : 推論用の平均・分散を取得（入力の形状が決まる前は空のmapを返す）
+func (bn *BatchNormalization) GetStates() map[string]mat.Matrix {
+	states := make(map[string]mat.Matrix)
+	if bn.runningMean == nil {
+		return states
+	}
+	states["runningMean"] = bn.runningMean
+	states["runningVar"] = bn.runningVar
+	return states
+}
+
+// SetStates : 推論用の平均・分散を設定（空のmapの場合は初期化前の状態に戻す）
+func (bn *BatchNormalization) SetStates(states map[string]mat.Matrix) {
+	if states["runningMean"] == nil || states["runningVar"] == nil {
+		bn.runningMean, bn.runningVar = nil, nil
+		return
+	}
+	bn.runningMean = mat.VecDenseCopyOf(mat.DenseCopyOf(states["runningMean"]).ColView(0))
+	bn.runningVar = mat.VecDenseCopyOf(mat.DenseCopyOf(states["runningVar"]).ColView(0))
+}
+
+// GetMomentum : 推論用の平均・分散を更新する際の減衰率を取得
+func (bn *BatchNormalization) GetMomentum() float64 {
+	return bn.momentum
+}
+
+// GetEpsilon : 0除算を防ぐための微小値を取得
+func (bn *BatchNormalization) GetEpsilon() float64 {
+	return bn.epsilon
+}
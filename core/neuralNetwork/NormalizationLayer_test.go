@@ -0,0 +1,143 @@
+package neuralNetwork
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBatchNormalization(t *testing.T) {
+	Convey("Given : BatchNormalizationレイヤーが一つ与えられた時", t, func() {
+		bn := NewBatchNormalization(WithBatchNormalizationMomentum(0.5), WithBatchNormalizationEpsilon(0))
+		Convey("AND : 入力xを4*2行列とする", nil)
+		// [1, 10]
+		// [2, 20]
+		// [3, 30]
+		// [6, 60]
+		x := mat.NewDense(4, 2, []float64{1, 10, 2, 20, 3, 30, 6, 60})
+		Convey("When : 学習時のForward処理を行う", func() {
+			out := bn.Forward(x)
+			Convey("Then : 要素毎に平均0, 分散1に正規化されること", func() {
+				for j := 0; j < 2; j++ {
+					mean, variance := columnMeanVariance(out, j)
+					checkValue(mean, 0, math.Pow10(-10))
+					checkValue(variance, 1, math.Pow10(-10))
+				}
+			})
+			Convey("Then : 推論用の平均・分散が更新されること", func() {
+				states := bn.GetStates()
+				// mean = [3, 30], variance = [3.5, 350]
+				So(mat.EqualApprox(states["runningMean"], mat.NewVecDense(2, []float64{1.5, 15}), math.Pow10(-10)), ShouldBeTrue)
+				So(mat.EqualApprox(states["runningVar"], mat.NewVecDense(2, []float64{2.25, 175.5}), math.Pow10(-10)), ShouldBeTrue)
+			})
+
+			Convey("AND : 誤差doutが与えられた時", nil)
+			dout := mat.NewDense(4, 2, []float64{0.5, -1, 1, 2, -0.5, 0.3, 2, 1})
+			Convey("Then : Backward処理の結果が数値微分の結果と一致すること", func() {
+				dx := bn.Backward(dout)
+				expected := numericalInputGradient(func(x mat.Matrix) mat.Matrix {
+					return NewBatchNormalization(WithBatchNormalizationEpsilon(0)).Forward(x)
+				}, x, dout)
+				So(mat.EqualApprox(dx, expected, math.Pow10(-5)), ShouldBeTrue)
+				grads := bn.GetGradients()
+				So(mat.EqualApprox(grads["beta"], mat.NewVecDense(2, []float64{3, 2.3}), math.Pow10(-10)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 推論用の平均・分散を設定し、推論時のForward処理を行う", func() {
+			states := make(map[string]mat.Matrix)
+			states["runningMean"] = mat.NewVecDense(2, []float64{2, 20})
+			states["runningVar"] = mat.NewVecDense(2, []float64{4, 100})
+			bn.SetStates(states)
+			bn.SetTrainingMode(false)
+			out := bn.Forward(x)
+			Convey("Then : 推論用の平均・分散で正規化されること", func() {
+				expected := mat.NewDense(4, 2, []float64{-0.5, -1, 0, 0, 0.5, 1, 2, 4})
+				So(mat.EqualApprox(out, expected, math.Pow10(-10)), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given : 入力データの形状が2チャネル・高さ2・幅2のBatchNormalizationレイヤーが与えられた時", t, func() {
+		bn := NewBatchNormalization()
+		bn.SetInputShape(NewShape(2, 2, 2))
+		Convey("When : 学習時のForward処理を行う", func() {
+			x := mat.NewDense(3, 8, util.CreateFloatArrayByStep(24, 0, 1.5))
+			out := bn.Forward(x)
+			Convey("Then : パラメーターはチャネル数分であること", func() {
+				So(bn.GetParams()["gamma"].(mat.Vector).Len(), ShouldEqual, 2)
+			})
+			Convey("Then : チャネル毎に平均0に正規化されること", func() {
+				for c := 0; c < 2; c++ {
+					sum := 0.0
+					for n := 0; n < 3; n++ {
+						for j := c * 4; j < (c+1)*4; j++ {
+							sum += out.At(n, j)
+						}
+					}
+					checkValue(sum, 0, math.Pow10(-10))
+				}
+			})
+		})
+	})
+}
+
+func TestNeuralNetworkLayersTrainingMode(t *testing.T) {
+	Convey("Given : BatchNormalizationを含むNNが与えられた時", t, func() {
+		nnLayers := NewDefaultNeuralNetworkLayers()
+		bn := NewBatchNormalization()
+		nnLayers.Add(bn)
+		Convey("Then : デフォルトは学習時の挙動であること", func() {
+			So(nnLayers.IsTraining(), ShouldBeTrue)
+			So(bn.training, ShouldBeTrue)
+		})
+		Convey("When : 推論時の挙動に切り替える", func() {
+			nnLayers.SetEvaluationMode()
+			Convey("Then : 各素子にも反映されること", func() {
+				So(nnLayers.IsTraining(), ShouldBeFalse)
+				So(bn.training, ShouldBeFalse)
+			})
+			Convey("AND : 切り替え後に追加した素子にも反映されること", func() {
+				bn2 := NewBatchNormalization()
+				nnLayers.Add(bn2)
+				So(bn2.training, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func columnMeanVariance(m mat.Matrix, j int) (mean float64, variance float64) {
+	r, _ := m.Dims()
+	for i := 0; i < r; i++ {
+		mean += m.At(i, j)
+	}
+	mean /= float64(r)
+	for i := 0; i < r; i++ {
+		variance += math.Pow(m.At(i, j)-mean, 2)
+	}
+	return mean, variance / float64(r)
+}
+
+// numericalInputGradient : sum(forward(x) * dout)のxに対する勾配を中心差分で求める
+func numericalInputGradient(forward func(x mat.Matrix) mat.Matrix, x mat.Matrix, dout mat.Matrix) mat.Matrix {
+	h := 1e-5
+	r, c := x.Dims()
+	grad := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			plus := mat.DenseCopyOf(x)
+			plus.Set(i, j, x.At(i, j)+h)
+			minus := mat.DenseCopyOf(x)
+			minus.Set(i, j, x.At(i, j)-h)
+			fPlus := mat.NewDense(r, c, nil)
+			fPlus.MulElem(forward(plus), dout)
+			fMinus := mat.NewDense(r, c, nil)
+			fMinus.MulElem(forward(minus), dout)
+			grad.Set(i, j, (mat.Sum(fPlus)-mat.Sum(fMinus))/(2*h))
+		}
+	}
+	return grad
+}
@@ -1,15 +1,40 @@
 package neuralNetwork
 
 import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
 	"gonum.org/v1/gonum/mat"
 )
 
 // Optimizer : パラメーターと勾配情報からパラメーターの最適化を行うIF
 type Optimizer interface {
 	// Update : パラメーターを勾配情報を元に最適化(更新)する
-	Update(params map[string]mat.Matrix, grads map[string]mat.Matrix)
+	// layerIndex : 更新対象のレイヤーの番号. 内部状態を持つOptimizerはレイヤー毎に状態を保持する
+	Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix)
+	// GetLearningRate : 現在の学習率を取得
+	GetLearningRate() float64
+	// SetLearningRate : 学習率を変更する（学習率のスケジューラーから利用）
+	SetLearningRate(lr float64)
+}
+
+// StatefulOptimizer : 速度・モーメントなどの内部状態を持つOptimizerのIF
+// 学習を中断・再開する際に、内部状態を保存・復元するために利用する
+type StatefulOptimizer interface {
+	Optimizer
+	// GetStates : 内部状態を取得
+	// キーは"レイヤーの番号.パラメーターのキー.バッファー名"（更新回数は"レイヤーの番号.パラメーターのキー.step"の1*1の行列）
+	GetStates() map[string]mat.Matrix
+	// SetStates : 内部状態を設定（それまでの内部状態は破棄する）
+	SetStates(states map[string]mat.Matrix)
 }
 
+// optimizerStepKey : 内部状態のうち、更新回数を表すバッファー名
+const optimizerStepKey = "step"
+
 // SGD : 確率的勾配降下法を行うOptimizer
 type SGD struct {
 	lr float64
@@ -21,6 +46,20 @@ type SGDOption func(*SGD)
 const (
 	// DefaultLearningRate : デフォルトの学習率の値
 	DefaultLearningRate = 0.01
+	// DefaultAdaptiveLearningRate : RMSProp, Adam, AdamWのデフォルトの学習率の値
+	DefaultAdaptiveLearningRate = 0.001
+	// DefaultMomentum : Momentum, Nesterovのデフォルトのモーメンタム係数
+	DefaultMomentum = 0.9
+	// DefaultRMSPropDecayRate : RMSPropのデフォルトの減衰率
+	DefaultRMSPropDecayRate = 0.9
+	// DefaultAdamBeta1 : Adam, AdamWのデフォルトの1次モーメントの減衰率
+	DefaultAdamBeta1 = 0.9
+	// DefaultAdamBeta2 : Adam, AdamWのデフォルトの2次モーメントの減衰率
+	DefaultAdamBeta2 = 0.999
+	// DefaultAdamWWeightDecay : AdamWのデフォルトの重み減衰率
+	DefaultAdamWWeightDecay = 0.01
+	// DefaultEpsilon : 0除算を防ぐための微小値のデフォルト値
+	DefaultEpsilon = 1e-8
 )
 
 // NewSGD : SGDを取得するAPI
@@ -43,7 +82,15 @@ func WithSGDLearningRate(lr float64) SGDOption {
 	}
 }
 
-func (sgd *SGD) Update(params map[string]mat.Matrix, grads map[string]mat.Matrix) {
+func (sgd *SGD) GetLearningRate() float64 {
+	return sgd.lr
+}
+
+func (sgd *SGD) SetLearningRate(lr float64) {
+	sgd.lr = lr
+}
+
+func (sgd *SGD) Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix) {
 	for key, _ := range params {
 		//r, c := params[key].Dims()
 		dense := mat.DenseCopyOf(params[key])
@@ -60,3 +107,598 @@ func (sgd *SGD) Update(params map[string]mat.Matrix, grads map[string]mat.Matrix
 		params[key] = dense
 	}
 }
+
+// parameterState : 1つのパラメーターに対する最適化の内部状態
+type parameterState struct {
+	buffers map[string]*mat.Dense // 速度・モーメントなどのバッファー
+	step    int                   // 更新回数
+}
+
+// buffer : 指定した名前のバッファーを取得（無ければr*cのゼロ行列を作成）
+func (ps *parameterState) buffer(name string, r, c int) []float64 {
+	b, ok := ps.buffers[name]
+	if !ok {
+		b = mat.NewDense(r, c, nil)
+		ps.buffers[name] = b
+	}
+	return b.RawMatrix().Data
+}
+
+// optimizerState : レイヤー毎・パラメーターのキー毎に最適化の内部状態を保持する
+type optimizerState struct {
+	states map[int]map[string]*parameterState
+}
+
+func newOptimizerState() optimizerState {
+	return optimizerState{states: make(map[int]map[string]*parameterState)}
+}
+
+// get : 指定したレイヤー・パラメーターのキーの内部状態を取得（無ければ作成）
+func (st *optimizerState) get(layerIndex int, key string) *parameterState {
+	layerStates, ok := st.states[layerIndex]
+	if !ok {
+		layerStates = make(map[string]*parameterState)
+		st.states[layerIndex] = layerStates
+	}
+	ps, ok := layerStates[key]
+	if !ok {
+		ps = &parameterState{buffers: make(map[string]*mat.Dense)}
+		layerStates[key] = ps
+	}
+	return ps
+}
+
+// getStates : 内部状態をレイヤーの番号・パラメーターのキー・バッファー名を繋げたキーで取得
+func (st *optimizerState) getStates() map[string]mat.Matrix {
+	states := make(map[string]mat.Matrix)
+	for layerIndex, layerStates := range st.states {
+		for key, ps := range layerStates {
+			prefix := fmt.Sprintf("%d.%s.", layerIndex, key)
+			for name, b := range ps.buffers {
+				states[prefix+name] = mat.DenseCopyOf(b)
+			}
+			states[prefix+optimizerStepKey] = mat.NewDense(1, 1, []float64{float64(ps.step)})
+		}
+	}
+	return states
+}
+
+// setStates : getStatesで取得した形式の内部状態を設定
+func (st *optimizerState) setStates(states map[string]mat.Matrix) {
+	st.states = make(map[int]map[string]*parameterState)
+
+	// 同じ入力に対して同じ順序で処理するため、キーをソートしておく
+	keys := make([]string, 0, len(states))
+	for key := range states {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, stateKey := range keys {
+		// パラメーターのキーに"."が含まれる場合を考慮し、先頭と末尾で分割する
+		first := strings.Index(stateKey, ".")
+		last := strings.LastIndex(stateKey, ".")
+		if first < 0 || first == last {
+			panic(fmt.Sprintf("Optimizerの内部状態のキーが不正です : %s", stateKey))
+		}
+		layerIndex, err := strconv.Atoi(stateKey[:first])
+		if err != nil {
+			panic(fmt.Sprintf("Optimizerの内部状態のキーが不正です : %s", stateKey))
+		}
+		ps := st.get(layerIndex, stateKey[first+1:last])
+		name := stateKey[last+1:]
+		if name == optimizerStepKey {
+			ps.step = int(states[stateKey].At(0, 0))
+			continue
+		}
+		ps.buffers[name] = mat.DenseCopyOf(states[stateKey])
+	}
+}
+
+// updateEach : パラメーター毎に更新処理を行う
+// fn : 内部状態, パラメーター, 勾配, 行列の形状を受け取り、パラメーターの値を更新する
+func (st *optimizerState) updateEach(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix,
+	fn func(ps *parameterState, p []float64, g []float64, r, c int)) {
+	for key := range params {
+		dense := mat.DenseCopyOf(params[key])
+		r, c := dense.Dims()
+		grad := mat.DenseCopyOf(grads[key]).RawMatrix().Data
+		ps := st.get(layerIndex, key)
+		ps.step++
+		fn(ps, dense.RawMatrix().Data, grad, r, c)
+
+		// paramに戻す
+		params[key] = dense
+	}
+}
+
+// Momentum : モーメンタム付きの確率的勾配降下法を行うOptimizer
+type Momentum struct {
+	lr       float64
+	momentum float64
+	state    optimizerState
+}
+
+// MomentumOption : Momentumのオプション
+type MomentumOption func(*Momentum)
+
+// NewMomentum : Momentumを取得するAPI
+// 初期化時にオプション指定が可能
+func NewMomentum(options ...MomentumOption) *Momentum {
+	m := Momentum{lr: DefaultLearningRate, momentum: DefaultMomentum, state: newOptimizerState()}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&m)
+	}
+	return &m
+}
+
+// WithMomentumLearningRate : Momentumの学習率指定のオプションを取得
+func WithMomentumLearningRate(lr float64) MomentumOption {
+	return func(m *Momentum) {
+		m.lr = lr
+	}
+}
+
+// WithMomentumCoefficient : Momentumのモーメンタム係数指定のオプションを取得
+func WithMomentumCoefficient(momentum float64) MomentumOption {
+	return func(m *Momentum) {
+		m.momentum = momentum
+	}
+}
+
+func (m *Momentum) GetLearningRate() float64 {
+	return m.lr
+}
+
+func (m *Momentum) SetLearningRate(lr float64) {
+	m.lr = lr
+}
+
+// GetCoefficient : モーメンタム係数を取得
+func (m *Momentum) GetCoefficient() float64 {
+	return m.momentum
+}
+
+func (m *Momentum) GetStates() map[string]mat.Matrix {
+	return m.state.getStates()
+}
+
+func (m *Momentum) SetStates(states map[string]mat.Matrix) {
+	m.state.setStates(states)
+}
+
+func (m *Momentum) Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix) {
+	m.state.updateEach(layerIndex, params, grads, func(ps *parameterState, p []float64, g []float64, r, c int) {
+		v := ps.buffer("velocity", r, c)
+		for i := range p {
+			// v = momentum * v - lr * g, p = p + v
+			v[i] = m.momentum*v[i] - m.lr*g[i]
+			p[i] += v[i]
+		}
+	})
+}
+
+// Nesterov : Nesterovの加速勾配法を行うOptimizer
+type Nesterov struct {
+	lr       float64
+	momentum float64
+	state    optimizerState
+}
+
+// NesterovOption : Nesterovのオプション
+type NesterovOption func(*Nesterov)
+
+// NewNesterov : Nesterovを取得するAPI
+// 初期化時にオプション指定が可能
+func NewNesterov(options ...NesterovOption) *Nesterov {
+	n := Nesterov{lr: DefaultLearningRate, momentum: DefaultMomentum, state: newOptimizerState()}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&n)
+	}
+	return &n
+}
+
+// WithNesterovLearningRate : Nesterovの学習率指定のオプションを取得
+func WithNesterovLearningRate(lr float64) NesterovOption {
+	return func(n *Nesterov) {
+		n.lr = lr
+	}
+}
+
+// WithNesterovCoefficient : Nesterovのモーメンタム係数指定のオプションを取得
+func WithNesterovCoefficient(momentum float64) NesterovOption {
+	return func(n *Nesterov) {
+		n.momentum = momentum
+	}
+}
+
+func (n *Nesterov) GetLearningRate() float64 {
+	return n.lr
+}
+
+func (n *Nesterov) SetLearningRate(lr float64) {
+	n.lr = lr
+}
+
+// GetCoefficient : モーメンタム係数を取得
+func (n *Nesterov) GetCoefficient() float64 {
+	return n.momentum
+}
+
+func (n *Nesterov) GetStates() map[string]mat.Matrix {
+	return n.state.getStates()
+}
+
+func (n *Nesterov) SetStates(states map[string]mat.Matrix) {
+	n.state.setStates(states)
+}
+
+func (n *Nesterov) Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix) {
+	n.state.updateEach(layerIndex, params, grads, func(ps *parameterState, p []float64, g []float64, r, c int) {
+		v := ps.buffer("velocity", r, c)
+		for i := range p {
+			// v = momentum * v - lr * g, p = p + momentum * v - lr * g
+			v[i] = n.momentum*v[i] - n.lr*g[i]
+			p[i] += n.momentum*v[i] - n.lr*g[i]
+		}
+	})
+}
+
+// AdaGrad : 勾配の2乗和で学習率を調整するOptimizer
+type AdaGrad struct {
+	lr      float64
+	epsilon float64
+	state   optimizerState
+}
+
+// AdaGradOption : AdaGradのオプション
+type AdaGradOption func(*AdaGrad)
+
+// NewAdaGrad : AdaGradを取得するAPI
+// 初期化時にオプション指定が可能
+func NewAdaGrad(options ...AdaGradOption) *AdaGrad {
+	a := AdaGrad{lr: DefaultLearningRate, epsilon: DefaultEpsilon, state: newOptimizerState()}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&a)
+	}
+	return &a
+}
+
+// WithAdaGradLearningRate : AdaGradの学習率指定のオプションを取得
+func WithAdaGradLearningRate(lr float64) AdaGradOption {
+	return func(a *AdaGrad) {
+		a.lr = lr
+	}
+}
+
+// WithAdaGradEpsilon : AdaGradの微小値指定のオプションを取得
+func WithAdaGradEpsilon(epsilon float64) AdaGradOption {
+	return func(a *AdaGrad) {
+		a.epsilon = epsilon
+	}
+}
+
+func (a *AdaGrad) GetLearningRate() float64 {
+	return a.lr
+}
+
+func (a *AdaGrad) SetLearningRate(lr float64) {
+	a.lr = lr
+}
+
+// GetEpsilon : 微小値を取得
+func (a *AdaGrad) GetEpsilon() float64 {
+	return a.epsilon
+}
+
+func (a *AdaGrad) GetStates() map[string]mat.Matrix {
+	return a.state.getStates()
+}
+
+func (a *AdaGrad) SetStates(states map[string]mat.Matrix) {
+	a.state.setStates(states)
+}
+
+func (a *AdaGrad) Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix) {
+	a.state.updateEach(layerIndex, params, grads, func(ps *parameterState, p []float64, g []float64, r, c int) {
+		h := ps.buffer("h", r, c)
+		for i := range p {
+			h[i] += g[i] * g[i]
+			p[i] -= a.lr * g[i] / (math.Sqrt(h[i]) + a.epsilon)
+		}
+	})
+}
+
+// RMSProp : 勾配の2乗の指数移動平均で学習率を調整するOptimizer
+type RMSProp struct {
+	lr        float64
+	decayRate float64
+	epsilon   float64
+	state     optimizerState
+}
+
+// RMSPropOption : RMSPropのオプション
+type RMSPropOption func(*RMSProp)
+
+// NewRMSProp : RMSPropを取得するAPI
+// 初期化時にオプション指定が可能
+func NewRMSProp(options ...RMSPropOption) *RMSProp {
+	rp := RMSProp{lr: DefaultAdaptiveLearningRate, decayRate: DefaultRMSPropDecayRate, epsilon: DefaultEpsilon, state: newOptimizerState()}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&rp)
+	}
+	return &rp
+}
+
+// WithRMSPropLearningRate : RMSPropの学習率指定のオプションを取得
+func WithRMSPropLearningRate(lr float64) RMSPropOption {
+	return func(rp *RMSProp) {
+		rp.lr = lr
+	}
+}
+
+// WithRMSPropDecayRate : RMSPropの減衰率指定のオプションを取得
+func WithRMSPropDecayRate(decayRate float64) RMSPropOption {
+	return func(rp *RMSProp) {
+		rp.decayRate = decayRate
+	}
+}
+
+// WithRMSPropEpsilon : RMSPropの微小値指定のオプションを取得
+func WithRMSPropEpsilon(epsilon float64) RMSPropOption {
+	return func(rp *RMSProp) {
+		rp.epsilon = epsilon
+	}
+}
+
+func (rp *RMSProp) GetLearningRate() float64 {
+	return rp.lr
+}
+
+func (rp *RMSProp) SetLearningRate(lr float64) {
+	rp.lr = lr
+}
+
+// GetDecayRate : 減衰率を取得
+func (rp *RMSProp) GetDecayRate() float64 {
+	return rp.decayRate
+}
+
+// GetEpsilon : 微小値を取得
+func (rp *RMSProp) GetEpsilon() float64 {
+	return rp.epsilon
+}
+
+func (rp *RMSProp) GetStates() map[string]mat.Matrix {
+	return rp.state.getStates()
+}
+
+func (rp *RMSProp) SetStates(states map[string]mat.Matrix) {
+	rp.state.setStates(states)
+}
+
+func (rp *RMSProp) Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix) {
+	rp.state.updateEach(layerIndex, params, grads, func(ps *parameterState, p []float64, g []float64, r, c int) {
+		h := ps.buffer("h", r, c)
+		for i := range p {
+			h[i] = rp.decayRate*h[i] + (1-rp.decayRate)*g[i]*g[i]
+			p[i] -= rp.lr * g[i] / (math.Sqrt(h[i]) + rp.epsilon)
+		}
+	})
+}
+
+// Adam : 勾配の1次・2次モーメントの推定値で更新量を調整するOptimizer
+type Adam struct {
+	lr      float64
+	beta1   float64
+	beta2   float64
+	epsilon float64
+	state   optimizerState
+}
+
+// AdamOption : Adamのオプション
+type AdamOption func(*Adam)
+
+// NewAdam : Adamを取得するAPI
+// 初期化時にオプション指定が可能
+func NewAdam(options ...AdamOption) *Adam {
+	a := Adam{lr: DefaultAdaptiveLearningRate, beta1: DefaultAdamBeta1, beta2: DefaultAdamBeta2, epsilon: DefaultEpsilon, state: newOptimizerState()}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&a)
+	}
+	return &a
+}
+
+// WithAdamLearningRate : Adamの学習率指定のオプションを取得
+func WithAdamLearningRate(lr float64) AdamOption {
+	return func(a *Adam) {
+		a.lr = lr
+	}
+}
+
+// WithAdamBeta1 : Adamの1次モーメントの減衰率指定のオプションを取得
+func WithAdamBeta1(beta1 float64) AdamOption {
+	return func(a *Adam) {
+		a.beta1 = beta1
+	}
+}
+
+// WithAdamBeta2 : Adamの2次モーメントの減衰率指定のオプションを取得
+func WithAdamBeta2(beta2 float64) AdamOption {
+	return func(a *Adam) {
+		a.beta2 = beta2
+	}
+}
+
+// WithAdamEpsilon : Adamの微小値指定のオプションを取得
+func WithAdamEpsilon(epsilon float64) AdamOption {
+	return func(a *Adam) {
+		a.epsilon = epsilon
+	}
+}
+
+func (a *Adam) GetLearningRate() float64 {
+	return a.lr
+}
+
+func (a *Adam) SetLearningRate(lr float64) {
+	a.lr = lr
+}
+
+// GetBeta1 : 1次モーメントの減衰率を取得
+func (a *Adam) GetBeta1() float64 {
+	return a.beta1
+}
+
+// GetBeta2 : 2次モーメントの減衰率を取得
+func (a *Adam) GetBeta2() float64 {
+	return a.beta2
+}
+
+// GetEpsilon : 微小値を取得
+func (a *Adam) GetEpsilon() float64 {
+	return a.epsilon
+}
+
+func (a *Adam) GetStates() map[string]mat.Matrix {
+	return a.state.getStates()
+}
+
+func (a *Adam) SetStates(states map[string]mat.Matrix) {
+	a.state.setStates(states)
+}
+
+func (a *Adam) Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix) {
+	a.state.updateEach(layerIndex, params, grads, func(ps *parameterState, p []float64, g []float64, r, c int) {
+		adamUpdate(ps, p, g, r, c, a.lr, a.beta1, a.beta2, a.epsilon, 0)
+	})
+}
+
+// AdamW : 重み減衰をモーメントの推定から分離したAdam
+type AdamW struct {
+	lr          float64
+	beta1       float64
+	beta2       float64
+	epsilon     float64
+	weightDecay float64
+	state       optimizerState
+}
+
+// AdamWOption : AdamWのオプション
+type AdamWOption func(*AdamW)
+
+// NewAdamW : AdamWを取得するAPI
+// 初期化時にオプション指定が可能
+func NewAdamW(options ...AdamWOption) *AdamW {
+	a := AdamW{lr: DefaultAdaptiveLearningRate, beta1: DefaultAdamBeta1, beta2: DefaultAdamBeta2, epsilon: DefaultEpsilon,
+		weightDecay: DefaultAdamWWeightDecay, state: newOptimizerState()}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&a)
+	}
+	return &a
+}
+
+// WithAdamWLearningRate : AdamWの学習率指定のオプションを取得
+func WithAdamWLearningRate(lr float64) AdamWOption {
+	return func(a *AdamW) {
+		a.lr = lr
+	}
+}
+
+// WithAdamWBeta1 : AdamWの1次モーメントの減衰率指定のオプションを取得
+func WithAdamWBeta1(beta1 float64) AdamWOption {
+	return func(a *AdamW) {
+		a.beta1 = beta1
+	}
+}
+
+// WithAdamWBeta2 : AdamWの2次モーメントの減衰率指定のオプションを取得
+func WithAdamWBeta2(beta2 float64) AdamWOption {
+	return func(a *AdamW) {
+		a.beta2 = beta2
+	}
+}
+
+// WithAdamWEpsilon : AdamWの微小値指定のオプションを取得
+func WithAdamWEpsilon(epsilon float64) AdamWOption {
+	return func(a *AdamW) {
+		a.epsilon = epsilon
+	}
+}
+
+// WithAdamWWeightDecay : AdamWの重み減衰率指定のオプションを取得
+func WithAdamWWeightDecay(weightDecay float64) AdamWOption {
+	return func(a *AdamW) {
+		a.weightDecay = weightDecay
+	}
+}
+
+func (a *AdamW) GetLearningRate() float64 {
+	return a.lr
+}
+
+func (a *AdamW) SetLearningRate(lr float64) {
+	a.lr = lr
+}
+
+// GetBeta1 : 1次モーメントの減衰率を取得
+func (a *AdamW) GetBeta1() float64 {
+	return a.beta1
+}
+
+// GetBeta2 : 2次モーメントの減衰率を取得
+func (a *AdamW) GetBeta2() float64 {
+	return a.beta2
+}
+
+// GetEpsilon : 微小値を取得
+func (a *AdamW) GetEpsilon() float64 {
+	return a.epsilon
+}
+
+// GetWeightDecay : 重み減衰率を取得
+func (a *AdamW) GetWeightDecay() float64 {
+	return a.weightDecay
+}
+
+func (a *AdamW) GetStates() map[string]mat.Matrix {
+	return a.state.getStates()
+}
+
+func (a *AdamW) SetStates(states map[string]mat.Matrix) {
+	a.state.setStates(states)
+}
+
+func (a *AdamW) Update(layerIndex int, params map[string]mat.Matrix, grads map[string]mat.Matrix) {
+	a.state.updateEach(layerIndex, params, grads, func(ps *parameterState, p []float64, g []float64, r, c int) {
+		adamUpdate(ps, p, g, r, c, a.lr, a.beta1, a.beta2, a.epsilon, a.weightDecay)
+	})
+}
+
+// adamUpdate : Adam(weightDecayが0以外の場合はAdamW)の更新処理
+func adamUpdate(ps *parameterState, p []float64, g []float64, r, c int, lr, beta1, beta2, epsilon, weightDecay float64) {
+	m := ps.buffer("m", r, c)
+	v := ps.buffer("v", r, c)
+
+	// バイアス補正の係数
+	correction1 := 1 - math.Pow(beta1, float64(ps.step))
+	correction2 := 1 - math.Pow(beta2, float64(ps.step))
+	for i := range p {
+		m[i] = beta1*m[i] + (1-beta1)*g[i]
+		v[i] = beta2*v[i] + (1-beta2)*g[i]*g[i]
+		mHat := m[i] / correction1
+		vHat := v[i] / correction2
+		p[i] -= lr * (mHat/(math.Sqrt(vHat)+epsilon) + weightDecay*p[i])
+	}
+}
@@ -1,6 +1,7 @@
 package neuralNetwork
 
 import (
+	"math"
 	"testing"
 
 	"github.com/goMLLibrary/core/util"
@@ -25,7 +26,7 @@ func TestSGD(t *testing.T) {
 		Convey("When : SGDの学習率0.1で初期化", func() {
 			sgd := NewSGD(WithSGDLearningRate(0.1))
 			Convey("Then : Optimizerでupdateを実施", func() {
-				sgd.Update(params, grads)
+				sgd.Update(0, params, grads)
 				// param["w"]
 				// [1, 1.95, 2.9]
 				// [3.85, 4.8, 5.75]
@@ -41,3 +42,144 @@ func TestSGD(t *testing.T) {
 		})
 	})
 }
+
+func TestStatefulOptimizers(t *testing.T) {
+	Convey("Given : 重みw=[1, -2], 勾配dw=[0.5, -1]が与えられた時", t, func() {
+		w := []float64{1, -2}
+		dw := []float64{0.5, -1}
+		newParams := func() (map[string]mat.Matrix, map[string]mat.Matrix) {
+			params := map[string]mat.Matrix{"w": mat.NewDense(1, 2, append([]float64{}, w...))}
+			grads := map[string]mat.Matrix{"w": mat.NewDense(1, 2, dw)}
+			return params, grads
+		}
+		optimizers := map[string]struct {
+			optimizer Optimizer
+			expected  func(p, g float64, steps int) float64
+		}{
+			"Momentum": {NewMomentum(WithMomentumLearningRate(0.1), WithMomentumCoefficient(0.9)), momentumExpected(0.1, 0.9, false)},
+			"Nesterov": {NewNesterov(WithNesterovLearningRate(0.1), WithNesterovCoefficient(0.9)), momentumExpected(0.1, 0.9, true)},
+			"AdaGrad":  {NewAdaGrad(WithAdaGradLearningRate(0.1)), adaGradExpected(0.1, DefaultEpsilon)},
+			"RMSProp":  {NewRMSProp(WithRMSPropLearningRate(0.1), WithRMSPropDecayRate(0.5)), rmsPropExpected(0.1, 0.5, DefaultEpsilon)},
+			"Adam":     {NewAdam(WithAdamLearningRate(0.1)), adamExpected(0.1, DefaultAdamBeta1, DefaultAdamBeta2, DefaultEpsilon, 0)},
+			"AdamW":    {NewAdamW(WithAdamWLearningRate(0.1), WithAdamWWeightDecay(0.1)), adamExpected(0.1, DefaultAdamBeta1, DefaultAdamBeta2, DefaultEpsilon, 0.1)},
+		}
+		for name, testCase := range optimizers {
+			optimizer := testCase.optimizer
+			expected := testCase.expected
+			Convey("When : "+name+"でレイヤー0のパラメーターを3回更新する", func() {
+				params, grads := newParams()
+				for i := 0; i < 3; i++ {
+					optimizer.Update(0, params, grads)
+					params["w"] = mat.DenseCopyOf(params["w"])
+				}
+				Convey("Then : 内部状態を考慮した更新結果になること", func() {
+					for j := range w {
+						checkValue(params["w"].At(0, j), expected(w[j], dw[j], 3), math.Pow10(-10))
+					}
+				})
+				Convey("AND : レイヤー1のパラメーターを1回更新する", nil)
+				params1, grads1 := newParams()
+				optimizer.Update(1, params1, grads1)
+				Convey("Then : レイヤー0の内部状態の影響を受けないこと", func() {
+					for j := range w {
+						checkValue(params1["w"].At(0, j), expected(w[j], dw[j], 1), math.Pow10(-10))
+					}
+				})
+			})
+		}
+	})
+}
+
+func TestOptimizerStates(t *testing.T) {
+	Convey("Given : 重みw=[1, -2], 勾配dw=[0.5, -1]でレイヤー0, 2のパラメーターを2回更新したAdamが与えられた時", t, func() {
+		newParams := func() (map[string]mat.Matrix, map[string]mat.Matrix) {
+			params := map[string]mat.Matrix{"w": mat.NewDense(1, 2, []float64{1, -2})}
+			grads := map[string]mat.Matrix{"w": mat.NewDense(1, 2, []float64{0.5, -1})}
+			return params, grads
+		}
+		adam := NewAdam(WithAdamLearningRate(0.1))
+		params0, grads0 := newParams()
+		params2, grads2 := newParams()
+		for i := 0; i < 2; i++ {
+			adam.Update(0, params0, grads0)
+			adam.Update(2, params2, grads2)
+		}
+		Convey("When : 内部状態を取得する", func() {
+			states := adam.GetStates()
+			Convey("Then : レイヤー・パラメーター毎のバッファーと更新回数が取得できること", func() {
+				So(len(states), ShouldEqual, 6)
+				So(states["0.w.step"].At(0, 0), ShouldEqual, 2)
+				r, c := states["2.w.m"].Dims()
+				So(r, ShouldEqual, 1)
+				So(c, ShouldEqual, 2)
+			})
+		})
+		Convey("When : 内部状態を別のAdamに設定し、両方のAdamで1回ずつ更新する", func() {
+			other := NewAdam(WithAdamLearningRate(0.1))
+			other.SetStates(adam.GetStates())
+			otherParams := map[string]mat.Matrix{"w": mat.DenseCopyOf(params0["w"])}
+			adam.Update(0, params0, grads0)
+			other.Update(0, otherParams, grads0)
+			Convey("Then : 同じ更新結果になること", func() {
+				So(mat.Equal(otherParams["w"], params0["w"]), ShouldBeTrue)
+			})
+		})
+		Convey("When : 不正なキーの内部状態を設定する", func() {
+			Convey("Then : panicが発生すること", func() {
+				So(func() { adam.SetStates(map[string]mat.Matrix{"w.m": mat.NewDense(1, 1, nil)}) }, ShouldPanic)
+				So(func() { adam.SetStates(map[string]mat.Matrix{"a.w.m": mat.NewDense(1, 1, nil)}) }, ShouldPanic)
+			})
+		})
+	})
+}
+
+func momentumExpected(lr, momentum float64, nesterov bool) func(p, g float64, steps int) float64 {
+	return func(p, g float64, steps int) float64 {
+		v := 0.0
+		for i := 0; i < steps; i++ {
+			v = momentum*v - lr*g
+			if nesterov {
+				p += momentum*v - lr*g
+			} else {
+				p += v
+			}
+		}
+		return p
+	}
+}
+
+func adaGradExpected(lr, epsilon float64) func(p, g float64, steps int) float64 {
+	return func(p, g float64, steps int) float64 {
+		h := 0.0
+		for i := 0; i < steps; i++ {
+			h += g * g
+			p -= lr * g / (math.Sqrt(h) + epsilon)
+		}
+		return p
+	}
+}
+
+func rmsPropExpected(lr, decayRate, epsilon float64) func(p, g float64, steps int) float64 {
+	return func(p, g float64, steps int) float64 {
+		h := 0.0
+		for i := 0; i < steps; i++ {
+			h = decayRate*h + (1-decayRate)*g*g
+			p -= lr * g / (math.Sqrt(h) + epsilon)
+		}
+		return p
+	}
+}
+
+func adamExpected(lr, beta1, beta2, epsilon, weightDecay float64) func(p, g float64, steps int) float64 {
+	return func(p, g float64, steps int) float64 {
+		m, v := 0.0, 0.0
+		for i := 1; i <= steps; i++ {
+			m = beta1*m + (1-beta1)*g
+			v = beta2*v + (1-beta2)*g*g
+			mHat := m / (1 - math.Pow(beta1, float64(i)))
+			vHat := v / (1 - math.Pow(beta2, float64(i)))
+			p -= lr * (mHat/(math.Sqrt(vHat)+epsilon) + weightDecay*p)
+		}
+		return p
+	}
+}
@@ -0,0 +1,244 @@
+package neuralNetwork
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// pooling2D : 2次元のプーリング処理に関する共通の構成情報
+// 入力は(データ数, チャネル数*高さ*幅)の行列、出力は(データ数, チャネル数*出力の高さ*出力の幅)の行列とする
+type pooling2D struct {
+	inputShape Shape
+	poolHeight int
+	poolWidth  int
+	stride     int
+	padding    int
+	batch      int
+}
+
+// Pooling2DOption : プーリングレイヤーのオプション
+type Pooling2DOption func(*pooling2D)
+
+func newPooling2D(poolHeight, poolWidth int, options []Pooling2DOption) pooling2D {
+	p := pooling2D{
+		poolHeight: poolHeight,
+		poolWidth:  poolWidth,
+		padding:    0,
+	}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&p)
+	}
+
+	// ストライドのデフォルトはプーリングの窓サイズ（窓が重ならない）とする
+	// ストライドは高さ・幅で共通のため、窓が正方形でない場合は窓が重ならないストライドが無く、省略できない
+	if p.stride == 0 {
+		if poolHeight != poolWidth {
+			panic(fmt.Sprintf("窓のサイズ(%d, %d)が正方形でない場合は、WithPooling2DStrideでストライドを指定してください", poolHeight, poolWidth))
+		}
+		p.stride = poolHeight
+	}
+	if p.stride <= 0 || p.padding < 0 {
+		panic("ストライドは1以上、パディングは0以上を指定してください")
+	}
+	if !p.inputShape.IsEmpty() {
+		p.SetInputShape(p.inputShape)
+	}
+	return p
+}
+
+// WithPooling2DStride : プーリングレイヤーのストライド指定のオプションを取得（0の場合はデフォルトの窓サイズとする）
+func WithPooling2DStride(stride int) Pooling2DOption {
+	return func(p *pooling2D) {
+		p.stride = stride
+	}
+}
+
+// WithPooling2DPadding : プーリングレイヤーのパディング指定のオプションを取得
+func WithPooling2DPadding(padding int) Pooling2DOption {
+	return func(p *pooling2D) {
+		p.padding = padding
+	}
+}
+
+// WithPooling2DInputShape : プーリングレイヤーの入力画像の形状指定のオプションを取得
+func WithPooling2DInputShape(shape Shape) Pooling2DOption {
+	return func(p *pooling2D) {
+		p.inputShape = shape
+	}
+}
+
+// SetInputShape : 入力画像の形状を設定し、出力画像の形状を返す
+func (p *pooling2D) SetInputShape(shape Shape) Shape {
+	p.inputShape = shape
+	outH, outW := p.outputImageSize()
+	if outH <= 0 || outW <= 0 {
+		panic(fmt.Sprintf("入力画像の形状%sに対してプーリングのサイズが大きすぎます", shape))
+	}
+	return NewShape(shape.Channel, outH, outW)
+}
+
+// GetInputShape : 入力画像の形状を取得
+func (p *pooling2D) GetInputShape() Shape {
+	return p.inputShape
+}
+
+// GetPoolSize : プーリングの窓の高さ・幅を取得
+func (p *pooling2D) GetPoolSize() (height int, width int) {
+	return p.poolHeight, p.poolWidth
+}
+
+// GetOutputShape : 出力画像の形状を取得
+func (p *pooling2D) GetOutputShape() Shape {
+	outH, outW := p.outputImageSize()
+	return NewShape(p.inputShape.Channel, outH, outW)
+}
+
+// GetStride : ストライドを取得
+func (p *pooling2D) GetStride() int {
+	return p.stride
+}
+
+// GetPadding : パディングを取得
+func (p *pooling2D) GetPadding() int {
+	return p.padding
+}
+
+func (p *pooling2D) outputImageSize() (height int, width int) {
+	height = util.ConvOutputSize(p.inputShape.Height, p.poolHeight, p.stride, p.padding)
+	width = util.ConvOutputSize(p.inputShape.Width, p.poolWidth, p.stride, p.padding)
+	return height, width
+}
+
+// im2col : 入力データをチャネル毎に分解し、(データ数*チャネル数*出力の高さ*出力の幅, 窓の高さ*窓の幅)の行列に変換
+func (p *pooling2D) im2col(x mat.Matrix) *mat.Dense {
+	if p.inputShape.IsEmpty() {
+		panic("プーリングレイヤーの入力画像の形状が設定されていません")
+	}
+	batch, _ := x.Dims()
+	p.batch = batch
+	s := p.inputShape
+
+	// (データ数, チャネル数*高さ*幅)と(データ数*チャネル数, 高さ*幅)は同じ並びのため、そのまま読み替える
+	images := mat.NewDense(batch*s.Channel, s.Height*s.Width, mat.DenseCopyOf(x).RawMatrix().Data)
+	return mat.DenseCopyOf(util.Im2col(images, 1, s.Height, s.Width, p.poolHeight, p.poolWidth, p.stride, p.padding))
+}
+
+// col2im : im2colの逆変換を行い、(データ数, チャネル数*高さ*幅)の行列に戻す
+func (p *pooling2D) col2im(dcol mat.Matrix) mat.Matrix {
+	s := p.inputShape
+	images := util.Col2im(dcol, p.batch*s.Channel, 1, s.Height, s.Width, p.poolHeight, p.poolWidth, p.stride, p.padding)
+	return mat.NewDense(p.batch, s.Size(), mat.DenseCopyOf(images).RawMatrix().Data)
+}
+
+// isPadding : im2colで変換した行列のrow行目・col列目の要素がパディング部分かどうか
+func (p *pooling2D) isPadding(row int, col int) bool {
+	outH, outW := p.outputImageSize()
+	oy, ox := row/outW%outH, row%outW
+	y := oy*p.stride + col/p.poolWidth - p.padding
+	x := ox*p.stride + col%p.poolWidth - p.padding
+	return y < 0 || y >= p.inputShape.Height || x < 0 || x >= p.inputShape.Width
+}
+
+// output : 窓毎の計算結果を(データ数, チャネル数*出力の高さ*出力の幅)の行列に変換
+func (p *pooling2D) output(values []float64) mat.Matrix {
+	return mat.NewDense(p.batch, len(values)/p.batch, values)
+}
+
+// MaxPooling2D : 窓内の最大値を出力するプーリングレイヤー
+type MaxPooling2D struct {
+	pooling2D
+	argMax []int // 各窓での最大値の位置（逆伝搬で利用）
+}
+
+// NewMaxPooling2D : 最大値プーリングの素子を取得
+// poolHeight, poolWidth : プーリングの窓の高さ・幅
+// 入力画像の形状はNeuralNetworkLayersに追加した時点、またはWithPooling2DInputShapeで決定する
+// 全ての窓に入力の画素が含まれるように、パディングは窓の高さ・幅の半分以下とする
+// 初期化時にオプション指定が可能
+func NewMaxPooling2D(poolHeight, poolWidth int, options ...Pooling2DOption) *MaxPooling2D {
+	m := MaxPooling2D{}
+	m.pooling2D = newPooling2D(poolHeight, poolWidth, options)
+	if m.padding*2 > poolHeight || m.padding*2 > poolWidth {
+		panic("最大値プーリングのパディングは窓の高さ・幅の半分以下を指定してください")
+	}
+	return &m
+}
+
+func (m *MaxPooling2D) Forward(x mat.Matrix) mat.Matrix {
+	col := m.im2col(x)
+	r, _ := col.Dims()
+	values := make([]float64, r)
+	m.argMax = make([]int, r)
+	for i := 0; i < r; i++ {
+		row := col.RawRowView(i)
+		// 入力が負の値の場合もパディング部分が最大値とならないように、パディング部分は-∞とする
+		if m.padding > 0 {
+			for k := range row {
+				if m.isPadding(i, k) {
+					row[k] = math.Inf(-1)
+				}
+			}
+		}
+		m.argMax[i], values[i] = util.MaxValue(row)
+	}
+	return m.output(values)
+}
+
+func (m *MaxPooling2D) Backward(dout mat.Matrix) mat.Matrix {
+	douts := mat.DenseCopyOf(dout).RawMatrix().Data
+
+	// 最大値を出力した位置にだけ誤差を伝搬する
+	dcol := mat.NewDense(len(m.argMax), m.poolHeight*m.poolWidth, nil)
+	for i, key := range m.argMax {
+		dcol.Set(i, key, douts[i])
+	}
+	return m.col2im(dcol)
+}
+
+// AveragePooling2D : 窓内の平均値を出力するプーリングレイヤー
+type AveragePooling2D struct {
+	pooling2D
+}
+
+// NewAveragePooling2D : 平均値プーリングの素子を取得
+// poolHeight, poolWidth : プーリングの窓の高さ・幅
+// 入力画像の形状はNeuralNetworkLayersに追加した時点、またはWithPooling2DInputShapeで決定する
+// 初期化時にオプション指定が可能
+func NewAveragePooling2D(poolHeight, poolWidth int, options ...Pooling2DOption) *AveragePooling2D {
+	a := AveragePooling2D{}
+	a.pooling2D = newPooling2D(poolHeight, poolWidth, options)
+	return &a
+}
+
+func (a *AveragePooling2D) Forward(x mat.Matrix) mat.Matrix {
+	col := a.im2col(x)
+	r, c := col.Dims()
+	values := make([]float64, r)
+	for i := 0; i < r; i++ {
+		sum := 0.0
+		for _, v := range col.RawRowView(i) {
+			sum += v
+		}
+		values[i] = sum / float64(c)
+	}
+	return a.output(values)
+}
+
+func (a *AveragePooling2D) Backward(dout mat.Matrix) mat.Matrix {
+	douts := mat.DenseCopyOf(dout).RawMatrix().Data
+	size := a.poolHeight * a.poolWidth
+
+	// 窓内の各要素に誤差を均等に分配する
+	dcol := mat.NewDense(len(douts), size, nil)
+	for i, d := range douts {
+		for k := 0; k < size; k++ {
+			dcol.Set(i, k, d/float64(size))
+		}
+	}
+	return a.col2im(dcol)
+}
@@ -0,0 +1,122 @@
+package neuralNetwork
+
+import (
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMaxPooling2D(t *testing.T) {
+	Convey("Given : 入力が2チャネル・高さ4・幅4, 窓サイズ2*2の最大値プーリングレイヤーが与えられた時", t, func() {
+		c, h, w := 2, 4, 4
+		pool := NewMaxPooling2D(2, 2, WithPooling2DInputShape(NewShape(c, h, w)))
+		Convey("When : 入力xを2個の画像とし、値を0-63とする", func() {
+			batch := 2
+			x := mat.NewDense(batch, c*h*w, util.CreateFloatArrayByStep(batch*c*h*w, 0, 1))
+			out := pool.Forward(x)
+			Convey("Then : Forward処理を行う. 各窓の右下の値が出力されること", func() {
+				So(pool.GetOutputShape(), ShouldResemble, NewShape(c, 2, 2))
+				expected := mat.NewDense(batch, c*2*2, []float64{
+					5, 7, 13, 15, 21, 23, 29, 31,
+					37, 39, 45, 47, 53, 55, 61, 63,
+				})
+				So(mat.Equal(out, expected), ShouldBeTrue)
+			})
+
+			Convey("AND : 誤差doutを1-16とする", nil)
+			dout := mat.NewDense(batch, c*2*2, util.CreateFloatArrayByStep(batch*c*2*2, 1, 1))
+			Convey("Then : Backward処理を行う. 最大値の位置にだけ誤差が伝搬されること", func() {
+				dx := pool.Backward(dout)
+				r, col := dx.Dims()
+				So(r, ShouldEqual, batch)
+				So(col, ShouldEqual, c*h*w)
+				for n := 0; n < batch; n++ {
+					for ch := 0; ch < c; ch++ {
+						for i, pos := range []int{5, 7, 13, 15} {
+							So(dx.At(n, ch*h*w+pos), ShouldEqual, dout.At(n, ch*4+i))
+						}
+					}
+				}
+				So(mat.Sum(dx), ShouldEqual, mat.Sum(dout))
+			})
+		})
+	})
+}
+
+func TestPooling2DStride(t *testing.T) {
+	Convey("Given : 入力が1チャネル・高さ4・幅6の画像が与えられた時", t, func() {
+		shape := NewShape(1, 4, 6)
+		Convey("When : ストライドを指定せずに窓サイズ2*2のプーリングレイヤーを作成する", func() {
+			pool := NewMaxPooling2D(2, 2, WithPooling2DInputShape(shape))
+			Convey("Then : ストライドは窓サイズとなり、窓が重ならないこと", func() {
+				So(pool.GetStride(), ShouldEqual, 2)
+				So(pool.GetOutputShape(), ShouldResemble, NewShape(1, 2, 3))
+			})
+		})
+		Convey("When : ストライドを指定して窓サイズ2*3のプーリングレイヤーを作成する", func() {
+			pool := NewAveragePooling2D(2, 3, WithPooling2DStride(1), WithPooling2DInputShape(shape))
+			Convey("Then : 指定したストライドで出力画像の形状が決まること", func() {
+				So(pool.GetOutputShape(), ShouldResemble, NewShape(1, 3, 4))
+			})
+		})
+		Convey("Then : 窓が正方形でない場合にストライドを省略するとpanicが発生すること", func() {
+			So(func() { NewMaxPooling2D(2, 3) }, ShouldPanic)
+			So(func() { NewAveragePooling2D(3, 2) }, ShouldPanic)
+		})
+	})
+}
+
+func TestMaxPooling2DWithPadding(t *testing.T) {
+	Convey("Given : 入力が1チャネル・高さ3・幅3, 窓サイズ2*2, パディング1の最大値プーリングレイヤーが与えられた時", t, func() {
+		c, h, w := 1, 3, 3
+		pool := NewMaxPooling2D(2, 2, WithPooling2DPadding(1), WithPooling2DInputShape(NewShape(c, h, w)))
+		Convey("When : 入力xの値を全て負の値(-1〜-9)とする", func() {
+			x := mat.NewDense(1, c*h*w, util.CreateFloatArrayByStep(c*h*w, -1, -1))
+			out := pool.Forward(x)
+			Convey("Then : Forward処理を行う. パディング部分を除いた各窓の最大値が出力されること", func() {
+				So(pool.GetOutputShape(), ShouldResemble, NewShape(c, 2, 2))
+				So(mat.Equal(out, mat.NewDense(1, 4, []float64{-1, -2, -4, -5})), ShouldBeTrue)
+			})
+
+			Convey("AND : 誤差doutを1-4とする", nil)
+			dout := mat.NewDense(1, 4, []float64{1, 2, 3, 4})
+			Convey("Then : Backward処理を行う. 入力の最大値の位置にだけ誤差が伝搬されること", func() {
+				dx := pool.Backward(dout)
+				So(mat.Equal(dx, mat.NewDense(1, c*h*w, []float64{1, 2, 0, 3, 4, 0, 0, 0, 0})), ShouldBeTrue)
+			})
+		})
+		Convey("Then : パディングが窓サイズの半分より大きい場合はpanicが発生すること", func() {
+			So(func() { NewMaxPooling2D(2, 2, WithPooling2DPadding(2)) }, ShouldPanic)
+		})
+	})
+}
+
+func TestAveragePooling2D(t *testing.T) {
+	Convey("Given : 入力が1チャネル・高さ4・幅4, 窓サイズ2*2の平均値プーリングレイヤーが与えられた時", t, func() {
+		c, h, w := 1, 4, 4
+		pool := NewAveragePooling2D(2, 2, WithPooling2DInputShape(NewShape(c, h, w)))
+		Convey("When : 入力xを1個の画像とし、値を0-15とする", func() {
+			x := mat.NewDense(1, c*h*w, util.CreateFloatArrayByStep(c*h*w, 0, 1))
+			out := pool.Forward(x)
+			Convey("Then : Forward処理を行う. 各窓の平均値が出力されること", func() {
+				expected := mat.NewDense(1, 4, []float64{2.5, 4.5, 10.5, 12.5})
+				So(mat.Equal(out, expected), ShouldBeTrue)
+			})
+
+			Convey("AND : 誤差doutを4, 8, 12, 16とする", nil)
+			dout := mat.NewDense(1, 4, []float64{4, 8, 12, 16})
+			Convey("Then : Backward処理を行う. 窓内の各要素に誤差が均等に分配されること", func() {
+				dx := pool.Backward(dout)
+				expected := mat.NewDense(1, 16, []float64{
+					1, 1, 2, 2,
+					1, 1, 2, 2,
+					3, 3, 4, 4,
+					3, 3, 4, 4,
+				})
+				So(mat.Equal(dx, expected), ShouldBeTrue)
+			})
+		})
+	})
+}
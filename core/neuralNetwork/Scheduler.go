@@ -0,0 +1,437 @@
+package neuralNetwork
+
+import (
+	"math"
+	"strings"
+)
+
+// LearningRateScheduler : 学習の進行に合わせてOptimizerの学習率を変更するIF
+// Stepを1iteration毎に呼ぶか1epoch毎に呼ぶかは利用側で決める
+type LearningRateScheduler interface {
+	// Step : スケジューラーを1ステップ進め、Optimizerの学習率を更新する
+	Step()
+	// GetLearningRate : 現在の学習率を取得
+	GetLearningRate() float64
+	// GetStates : 学習を再開するための状態（ステップ数など）を取得
+	GetStates() map[string]float64
+	// SetStates : GetStatesで取得した状態を設定し、Optimizerの学習率も取得した時点の値に戻す
+	SetStates(states map[string]float64)
+}
+
+// MetricScheduler : 検証データの評価値を元にOptimizerの学習率を変更するIF
+type MetricScheduler interface {
+	// StepWithMetric : 評価値（検証データの損失など）を与えてスケジューラーを1ステップ進め、Optimizerの学習率を更新する
+	StepWithMetric(metric float64)
+	// GetLearningRate : 現在の学習率を取得
+	GetLearningRate() float64
+	// GetStates : 学習を再開するための状態（最良の評価値など）を取得
+	GetStates() map[string]float64
+	// SetStates : GetStatesで取得した状態を設定し、Optimizerの学習率も取得した時点の値に戻す
+	SetStates(states map[string]float64)
+}
+
+// schedulerStateLearningRate : 各スケジューラーの状態で、Optimizerの学習率を表すキー
+const schedulerStateLearningRate = "learningRate"
+
+const (
+	// DefaultStepDecayGamma : StepDecayのデフォルトの減衰率
+	DefaultStepDecayGamma = 0.1
+	// DefaultCosineAnnealingTMult : CosineAnnealingWarmRestartsのデフォルトの周期の倍率
+	DefaultCosineAnnealingTMult = 1
+	// DefaultReduceLROnPlateauFactor : ReduceLROnPlateauのデフォルトの減衰率
+	DefaultReduceLROnPlateauFactor = 0.1
+	// DefaultReduceLROnPlateauPatience : ReduceLROnPlateauのデフォルトの許容ステップ数
+	DefaultReduceLROnPlateauPatience = 10
+	// DefaultLinearWarmupStartFactor : LinearWarmupのデフォルトのウォームアップ開始時の学習率の倍率
+	DefaultLinearWarmupStartFactor = 1.0 / 3
+)
+
+// StepDecay : 一定ステップ毎に学習率をgamma倍するスケジューラー
+type StepDecay struct {
+	optimizer Optimizer
+	baseLR    float64
+	stepSize  int
+	gamma     float64
+	step      int
+}
+
+// StepDecayOption : StepDecayのオプション
+type StepDecayOption func(*StepDecay)
+
+// NewStepDecay : StepDecayを取得するAPI
+// optimizer : 学習率を変更するOptimizer（作成時点の学習率を初期値とする）
+// stepSize : 学習率を減衰させるステップ間隔
+// 初期化時にオプション指定が可能
+func NewStepDecay(optimizer Optimizer, stepSize int, options ...StepDecayOption) *StepDecay {
+	if stepSize <= 0 {
+		panic("stepSizeは1以上を指定してください")
+	}
+	s := StepDecay{optimizer: optimizer, baseLR: optimizer.GetLearningRate(), stepSize: stepSize, gamma: DefaultStepDecayGamma}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&s)
+	}
+	return &s
+}
+
+// WithStepDecayGamma : StepDecayの減衰率指定のオプションを取得
+func WithStepDecayGamma(gamma float64) StepDecayOption {
+	return func(s *StepDecay) {
+		s.gamma = gamma
+	}
+}
+
+func (s *StepDecay) Step() {
+	s.step++
+	s.optimizer.SetLearningRate(s.baseLR * math.Pow(s.gamma, float64(s.step/s.stepSize)))
+}
+
+func (s *StepDecay) GetLearningRate() float64 {
+	return s.optimizer.GetLearningRate()
+}
+
+func (s *StepDecay) GetStates() map[string]float64 {
+	return map[string]float64{
+		schedulerStateLearningRate: s.optimizer.GetLearningRate(),
+		"baseLR":                   s.baseLR,
+		"step":                     float64(s.step),
+	}
+}
+
+func (s *StepDecay) SetStates(states map[string]float64) {
+	s.baseLR = states["baseLR"]
+	s.step = int(states["step"])
+	s.optimizer.SetLearningRate(states[schedulerStateLearningRate])
+}
+
+// ExponentialDecay : 1ステップ毎に学習率をgamma倍するスケジューラー
+type ExponentialDecay struct {
+	optimizer Optimizer
+	baseLR    float64
+	gamma     float64
+	step      int
+}
+
+// NewExponentialDecay : ExponentialDecayを取得するAPI
+// optimizer : 学習率を変更するOptimizer（作成時点の学習率を初期値とする）
+// gamma : 1ステップ毎の減衰率
+func NewExponentialDecay(optimizer Optimizer, gamma float64) *ExponentialDecay {
+	e := ExponentialDecay{optimizer: optimizer, baseLR: optimizer.GetLearningRate(), gamma: gamma}
+	return &e
+}
+
+func (e *ExponentialDecay) Step() {
+	e.step++
+	e.optimizer.SetLearningRate(e.baseLR * math.Pow(e.gamma, float64(e.step)))
+}
+
+func (e *ExponentialDecay) GetLearningRate() float64 {
+	return e.optimizer.GetLearningRate()
+}
+
+func (e *ExponentialDecay) GetStates() map[string]float64 {
+	return map[string]float64{
+		schedulerStateLearningRate: e.optimizer.GetLearningRate(),
+		"baseLR":                   e.baseLR,
+		"step":                     float64(e.step),
+	}
+}
+
+func (e *ExponentialDecay) SetStates(states map[string]float64) {
+	e.baseLR = states["baseLR"]
+	e.step = int(states["step"])
+	e.optimizer.SetLearningRate(states[schedulerStateLearningRate])
+}
+
+// CosineAnnealingWarmRestarts : コサインカーブに沿って学習率を減衰させ、周期毎に初期値に戻すスケジューラー
+type CosineAnnealingWarmRestarts struct {
+	optimizer Optimizer
+	baseLR    float64
+	minLR     float64
+	period    int // 現在の周期
+	tMult     int // 再スタート毎の周期の倍率
+	current   int // 現在の周期内でのステップ数
+}
+
+// CosineAnnealingOption : CosineAnnealingWarmRestartsのオプション
+type CosineAnnealingOption func(*CosineAnnealingWarmRestarts)
+
+// NewCosineAnnealingWarmRestarts : CosineAnnealingWarmRestartsを取得するAPI
+// optimizer : 学習率を変更するOptimizer（作成時点の学習率を初期値とする）
+// t0 : 最初の周期のステップ数
+// 初期化時にオプション指定が可能
+func NewCosineAnnealingWarmRestarts(optimizer Optimizer, t0 int, options ...CosineAnnealingOption) *CosineAnnealingWarmRestarts {
+	if t0 <= 0 {
+		panic("t0は1以上を指定してください")
+	}
+	c := CosineAnnealingWarmRestarts{optimizer: optimizer, baseLR: optimizer.GetLearningRate(), period: t0, tMult: DefaultCosineAnnealingTMult}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&c)
+	}
+	if c.tMult < 1 {
+		panic("tMultは1以上を指定してください")
+	}
+	return &c
+}
+
+// WithCosineAnnealingTMult : 再スタート毎の周期の倍率指定のオプションを取得
+func WithCosineAnnealingTMult(tMult int) CosineAnnealingOption {
+	return func(c *CosineAnnealingWarmRestarts) {
+		c.tMult = tMult
+	}
+}
+
+// WithCosineAnnealingMinLearningRate : 学習率の最小値指定のオプションを取得
+func WithCosineAnnealingMinLearningRate(minLR float64) CosineAnnealingOption {
+	return func(c *CosineAnnealingWarmRestarts) {
+		c.minLR = minLR
+	}
+}
+
+func (c *CosineAnnealingWarmRestarts) Step() {
+	c.current++
+	if c.current >= c.period {
+		// 周期の終わりで学習率を初期値に戻す
+		c.current = 0
+		c.period *= c.tMult
+	}
+	lr := c.minLR + (c.baseLR-c.minLR)*(1+math.Cos(math.Pi*float64(c.current)/float64(c.period)))/2
+	c.optimizer.SetLearningRate(lr)
+}
+
+func (c *CosineAnnealingWarmRestarts) GetLearningRate() float64 {
+	return c.optimizer.GetLearningRate()
+}
+
+func (c *CosineAnnealingWarmRestarts) GetStates() map[string]float64 {
+	return map[string]float64{
+		schedulerStateLearningRate: c.optimizer.GetLearningRate(),
+		"baseLR":                   c.baseLR,
+		"period":                   float64(c.period),
+		"current":                  float64(c.current),
+	}
+}
+
+func (c *CosineAnnealingWarmRestarts) SetStates(states map[string]float64) {
+	c.baseLR = states["baseLR"]
+	c.period = int(states["period"])
+	c.current = int(states["current"])
+	c.optimizer.SetLearningRate(states[schedulerStateLearningRate])
+}
+
+// LinearWarmup : 学習初期に学習率を線形に増加させるスケジューラー
+// ウォームアップ終了後は、指定したスケジューラーに処理を引き継ぐ
+type LinearWarmup struct {
+	optimizer   Optimizer
+	baseLR      float64
+	warmupSteps int
+	startFactor float64
+	after       LearningRateScheduler
+	step        int
+}
+
+// LinearWarmupOption : LinearWarmupのオプション
+type LinearWarmupOption func(*LinearWarmup)
+
+// NewLinearWarmup : LinearWarmupを取得するAPI
+// optimizer : 学習率を変更するOptimizer（作成時点の学習率をウォームアップ後の学習率とする）
+// warmupSteps : ウォームアップのステップ数
+// 初期化時にオプション指定が可能
+func NewLinearWarmup(optimizer Optimizer, warmupSteps int, options ...LinearWarmupOption) *LinearWarmup {
+	if warmupSteps <= 0 {
+		panic("warmupStepsは1以上を指定してください")
+	}
+	l := LinearWarmup{
+		optimizer:   optimizer,
+		baseLR:      optimizer.GetLearningRate(),
+		warmupSteps: warmupSteps,
+		startFactor: DefaultLinearWarmupStartFactor,
+	}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&l)
+	}
+
+	// ウォームアップ開始時の学習率を設定
+	optimizer.SetLearningRate(l.baseLR * l.startFactor)
+	return &l
+}
+
+// WithLinearWarmupStartFactor : ウォームアップ開始時の学習率の倍率指定のオプションを取得（デフォルトは1/3）
+// 0を指定した場合、最初のステップまでの更新は学習率0となりパラメーターが変化しない
+func WithLinearWarmupStartFactor(startFactor float64) LinearWarmupOption {
+	return func(l *LinearWarmup) {
+		l.startFactor = startFactor
+	}
+}
+
+// WithLinearWarmupScheduler : ウォームアップ終了後に利用するスケジューラー指定のオプションを取得
+// スケジューラーはLinearWarmupより先に、同じOptimizerに対して作成しておくこと
+func WithLinearWarmupScheduler(after LearningRateScheduler) LinearWarmupOption {
+	return func(l *LinearWarmup) {
+		l.after = after
+	}
+}
+
+func (l *LinearWarmup) Step() {
+	l.step++
+	if l.step <= l.warmupSteps {
+		factor := l.startFactor + (1-l.startFactor)*float64(l.step)/float64(l.warmupSteps)
+		l.optimizer.SetLearningRate(l.baseLR * factor)
+		return
+	}
+	if l.after != nil {
+		l.after.Step()
+	}
+}
+
+func (l *LinearWarmup) GetLearningRate() float64 {
+	return l.optimizer.GetLearningRate()
+}
+
+// GetStates : 学習を再開するための状態を取得
+// ウォームアップ終了後のスケジューラーの状態は"after."から始まるキーで含める
+func (l *LinearWarmup) GetStates() map[string]float64 {
+	states := map[string]float64{
+		schedulerStateLearningRate: l.optimizer.GetLearningRate(),
+		"baseLR":                   l.baseLR,
+		"step":                     float64(l.step),
+	}
+	if l.after != nil {
+		for key, v := range l.after.GetStates() {
+			states["after."+key] = v
+		}
+	}
+	return states
+}
+
+func (l *LinearWarmup) SetStates(states map[string]float64) {
+	l.baseLR = states["baseLR"]
+	l.step = int(states["step"])
+	if l.after != nil {
+		afterStates := make(map[string]float64)
+		for key, v := range states {
+			if strings.HasPrefix(key, "after.") {
+				afterStates[strings.TrimPrefix(key, "after.")] = v
+			}
+		}
+		l.after.SetStates(afterStates)
+	}
+	l.optimizer.SetLearningRate(states[schedulerStateLearningRate])
+}
+
+// ReduceLROnPlateau : 評価値の改善が一定ステップ見られない場合に学習率を減衰させるスケジューラー
+type ReduceLROnPlateau struct {
+	optimizer Optimizer
+	factor    float64
+	patience  int
+	minDelta  float64
+	minLR     float64
+	maximize  bool
+	best      float64
+	badSteps  int
+	hasBest   bool
+}
+
+// ReduceLROnPlateauOption : ReduceLROnPlateauのオプション
+type ReduceLROnPlateauOption func(*ReduceLROnPlateau)
+
+// NewReduceLROnPlateau : ReduceLROnPlateauを取得するAPI
+// optimizer : 学習率を変更するOptimizer
+// デフォルトでは評価値は小さいほど良いもの（損失など）として扱う
+// 初期化時にオプション指定が可能
+func NewReduceLROnPlateau(optimizer Optimizer, options ...ReduceLROnPlateauOption) *ReduceLROnPlateau {
+	r := ReduceLROnPlateau{optimizer: optimizer, factor: DefaultReduceLROnPlateauFactor, patience: DefaultReduceLROnPlateauPatience}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&r)
+	}
+	return &r
+}
+
+// WithReduceLROnPlateauFactor : 学習率の減衰率指定のオプションを取得
+func WithReduceLROnPlateauFactor(factor float64) ReduceLROnPlateauOption {
+	return func(r *ReduceLROnPlateau) {
+		r.factor = factor
+	}
+}
+
+// WithReduceLROnPlateauPatience : 改善が見られなくても学習率を維持するステップ数指定のオプションを取得
+func WithReduceLROnPlateauPatience(patience int) ReduceLROnPlateauOption {
+	return func(r *ReduceLROnPlateau) {
+		r.patience = patience
+	}
+}
+
+// WithReduceLROnPlateauMinDelta : 改善とみなす評価値の最小変化量指定のオプションを取得
+func WithReduceLROnPlateauMinDelta(minDelta float64) ReduceLROnPlateauOption {
+	return func(r *ReduceLROnPlateau) {
+		r.minDelta = minDelta
+	}
+}
+
+// WithReduceLROnPlateauMinLearningRate : 学習率の最小値指定のオプションを取得
+func WithReduceLROnPlateauMinLearningRate(minLR float64) ReduceLROnPlateauOption {
+	return func(r *ReduceLROnPlateau) {
+		r.minLR = minLR
+	}
+}
+
+// WithReduceLROnPlateauMaximize : 評価値を大きいほど良いもの（正解率など）として扱うオプションを取得
+func WithReduceLROnPlateauMaximize() ReduceLROnPlateauOption {
+	return func(r *ReduceLROnPlateau) {
+		r.maximize = true
+	}
+}
+
+func (r *ReduceLROnPlateau) StepWithMetric(metric float64) {
+	if !r.hasBest || r.isImproved(metric) {
+		r.best = metric
+		r.hasBest = true
+		r.badSteps = 0
+		return
+	}
+
+	r.badSteps++
+	if r.badSteps > r.patience {
+		lr := math.Max(r.optimizer.GetLearningRate()*r.factor, r.minLR)
+		r.optimizer.SetLearningRate(lr)
+		r.badSteps = 0
+	}
+}
+
+func (r *ReduceLROnPlateau) GetLearningRate() float64 {
+	return r.optimizer.GetLearningRate()
+}
+
+func (r *ReduceLROnPlateau) GetStates() map[string]float64 {
+	states := map[string]float64{
+		schedulerStateLearningRate: r.optimizer.GetLearningRate(),
+		"best":                     r.best,
+		"badSteps":                 float64(r.badSteps),
+		"hasBest":                  0,
+	}
+	if r.hasBest {
+		states["hasBest"] = 1
+	}
+	return states
+}
+
+func (r *ReduceLROnPlateau) SetStates(states map[string]float64) {
+	r.best = states["best"]
+	r.badSteps = int(states["badSteps"])
+	r.hasBest = states["hasBest"] != 0
+	r.optimizer.SetLearningRate(states[schedulerStateLearningRate])
+}
+
+func (r *ReduceLROnPlateau) isImproved(metric float64) bool {
+	if r.maximize {
+		return metric > r.best+r.minDelta
+	}
+	return metric < r.best-r.minDelta
+}
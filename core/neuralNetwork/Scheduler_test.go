@@ -0,0 +1,193 @@
+package neuralNetwork
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestStepDecay(t *testing.T) {
+	Convey("Given : 学習率1のSGDに対して、2ステップ毎に0.5倍するStepDecayが与えられた時", t, func() {
+		sgd := NewSGD(WithSGDLearningRate(1))
+		scheduler := NewStepDecay(sgd, 2, WithStepDecayGamma(0.5))
+		Convey("When : 5ステップ進める", func() {
+			lrs := stepScheduler(scheduler, 5)
+			Convey("Then : 学習率が1, 0.5, 0.5, 0.25, 0.25と変化すること", func() {
+				So(lrs, ShouldResemble, []float64{1, 0.5, 0.5, 0.25, 0.25})
+				So(sgd.GetLearningRate(), ShouldEqual, 0.25)
+			})
+		})
+	})
+}
+
+func TestExponentialDecay(t *testing.T) {
+	Convey("Given : 学習率1のAdamに対して、1ステップ毎に0.5倍するExponentialDecayが与えられた時", t, func() {
+		adam := NewAdam(WithAdamLearningRate(1))
+		scheduler := NewExponentialDecay(adam, 0.5)
+		Convey("When : 3ステップ進める", func() {
+			lrs := stepScheduler(scheduler, 3)
+			Convey("Then : 学習率が0.5, 0.25, 0.125と変化すること", func() {
+				So(lrs, ShouldResemble, []float64{0.5, 0.25, 0.125})
+			})
+		})
+	})
+}
+
+func TestCosineAnnealingWarmRestarts(t *testing.T) {
+	Convey("Given : 学習率1のSGDに対して、周期2・倍率2・最小値0のCosineAnnealingWarmRestartsが与えられた時", t, func() {
+		sgd := NewSGD(WithSGDLearningRate(1))
+		scheduler := NewCosineAnnealingWarmRestarts(sgd, 2, WithCosineAnnealingTMult(2))
+		Convey("When : 6ステップ進める", func() {
+			lrs := stepScheduler(scheduler, 6)
+			Convey("Then : 周期2で初期値に戻った後、周期4でコサインカーブに沿って減衰すること", func() {
+				expected := []float64{0.5, 1, (1 + math.Cos(math.Pi/4)) / 2, 0.5, (1 + math.Cos(3*math.Pi/4)) / 2, 1}
+				for i, lr := range lrs {
+					checkValue(lr, expected[i], math.Pow10(-10))
+				}
+			})
+		})
+	})
+}
+
+func TestLinearWarmup(t *testing.T) {
+	Convey("Given : 学習率1のSGDに対して、4ステップのLinearWarmupが与えられた時", t, func() {
+		sgd := NewSGD(WithSGDLearningRate(1))
+		Convey("AND : ウォームアップ後は1ステップ毎に0.5倍する", nil)
+		Convey("AND : ウォームアップ開始時の倍率は0とする", nil)
+		scheduler := NewLinearWarmup(sgd, 4, WithLinearWarmupStartFactor(0), WithLinearWarmupScheduler(NewExponentialDecay(sgd, 0.5)))
+		Convey("Then : 作成直後の学習率は0であること", func() {
+			So(sgd.GetLearningRate(), ShouldEqual, 0)
+		})
+		Convey("When : 6ステップ進める", func() {
+			lrs := stepScheduler(scheduler, 6)
+			Convey("Then : 学習率が線形に増加した後、減衰すること", func() {
+				So(lrs, ShouldResemble, []float64{0.25, 0.5, 0.75, 1, 0.5, 0.25})
+			})
+		})
+	})
+
+	Convey("Given : 学習率1のSGDに対して、デフォルトの倍率の4ステップのLinearWarmupが与えられた時", t, func() {
+		sgd := NewSGD(WithSGDLearningRate(1))
+		scheduler := NewLinearWarmup(sgd, 4)
+		Convey("When : 重み1・勾配1のパラメーターを1回更新し、スケジューラーを4ステップ進める", func() {
+			params := map[string]mat.Matrix{"w": mat.NewDense(1, 1, []float64{1})}
+			sgd.Update(0, params, map[string]mat.Matrix{"w": mat.NewDense(1, 1, []float64{1})})
+			lrs := stepScheduler(scheduler, 4)
+			Convey("Then : 最初の更新は学習率1/3で行われ、その後学習率が1まで線形に増加すること", func() {
+				So(params["w"].At(0, 0), ShouldAlmostEqual, 1-1.0/3, 1e-12)
+				for i, expected := range []float64{0.5, 2.0 / 3, 5.0 / 6, 1} {
+					So(lrs[i], ShouldAlmostEqual, expected, 1e-12)
+				}
+			})
+		})
+	})
+}
+
+func TestReduceLROnPlateau(t *testing.T) {
+	Convey("Given : 学習率1のSGDに対して、許容ステップ数1・減衰率0.5のReduceLROnPlateauが与えられた時", t, func() {
+		sgd := NewSGD(WithSGDLearningRate(1))
+		scheduler := NewReduceLROnPlateau(sgd, WithReduceLROnPlateauPatience(1), WithReduceLROnPlateauFactor(0.5),
+			WithReduceLROnPlateauMinLearningRate(0.3))
+		Convey("When : 検証データの損失を1, 0.8, 0.9, 0.85, 0.7, 0.75, 0.75, 0.75, 0.75と与える", func() {
+			lrs := make([]float64, 0)
+			for _, loss := range []float64{1, 0.8, 0.9, 0.85, 0.7, 0.75, 0.75, 0.75, 0.75} {
+				scheduler.StepWithMetric(loss)
+				lrs = append(lrs, scheduler.GetLearningRate())
+			}
+			Convey("Then : 改善が2回続けて見られなかった時に学習率が減衰し、最小値を下回らないこと", func() {
+				So(lrs, ShouldResemble, []float64{1, 1, 1, 0.5, 0.5, 0.5, 0.3, 0.3, 0.3})
+			})
+		})
+	})
+}
+
+func TestSchedulerStates(t *testing.T) {
+	Convey("Given : 学習率1のSGDに対する各スケジューラーの作成関数が与えられた時", t, func() {
+		newSchedulers := map[string]func(Optimizer) LearningRateScheduler{
+			"StepDecay": func(o Optimizer) LearningRateScheduler {
+				return NewStepDecay(o, 2, WithStepDecayGamma(0.5))
+			},
+			"ExponentialDecay": func(o Optimizer) LearningRateScheduler {
+				return NewExponentialDecay(o, 0.5)
+			},
+			"CosineAnnealingWarmRestarts": func(o Optimizer) LearningRateScheduler {
+				return NewCosineAnnealingWarmRestarts(o, 2, WithCosineAnnealingTMult(2))
+			},
+			"LinearWarmup": func(o Optimizer) LearningRateScheduler {
+				return NewLinearWarmup(o, 2, WithLinearWarmupScheduler(NewStepDecay(o, 2, WithStepDecayGamma(0.5))))
+			},
+		}
+		Convey("When : 3ステップ進めて状態を取得し、減衰後の学習率のOptimizerで作り直したスケジューラーに設定する", func() {
+			Convey("Then : 中断せずに進めた場合と同じ学習率で続きから進むこと", func() {
+				for name, newScheduler := range newSchedulers {
+					expected := stepScheduler(newScheduler(NewSGD(WithSGDLearningRate(1))), 8)
+
+					sgd := NewSGD(WithSGDLearningRate(1))
+					first := newScheduler(sgd)
+					lrs := stepScheduler(first, 3)
+					states := first.GetStates()
+
+					// 再開時はOptimizerの学習率が減衰後の値となっている
+					resumed := newScheduler(NewSGD(WithSGDLearningRate(sgd.GetLearningRate())))
+					resumed.SetStates(states)
+					So(resumed.GetLearningRate(), ShouldEqual, lrs[2])
+					lrs = append(lrs, stepScheduler(resumed, 5)...)
+					So(name+":"+fmt.Sprint(lrs), ShouldEqual, name+":"+fmt.Sprint(expected))
+				}
+			})
+		})
+	})
+
+	Convey("Given : 許容ステップ数1・減衰率0.5のReduceLROnPlateauが与えられた時", t, func() {
+		newScheduler := func(o Optimizer) *ReduceLROnPlateau {
+			return NewReduceLROnPlateau(o, WithReduceLROnPlateauPatience(1), WithReduceLROnPlateauFactor(0.5))
+		}
+		losses := []float64{1, 0.8, 0.9, 0.85, 0.9, 0.95}
+		Convey("When : 4ステップ進めて状態を取得し、作り直したスケジューラーに設定して続きを進める", func() {
+			expected := newScheduler(NewSGD(WithSGDLearningRate(1)))
+			for _, loss := range losses {
+				expected.StepWithMetric(loss)
+			}
+			first := newScheduler(NewSGD(WithSGDLearningRate(1)))
+			for _, loss := range losses[:4] {
+				first.StepWithMetric(loss)
+			}
+			resumed := newScheduler(NewSGD(WithSGDLearningRate(1)))
+			resumed.SetStates(first.GetStates())
+			for _, loss := range losses[4:] {
+				resumed.StepWithMetric(loss)
+			}
+			Convey("Then : 最良の評価値と改善しなかったステップ数が引き継がれ、中断せずに進めた場合と同じ学習率となること", func() {
+				So(resumed.GetLearningRate(), ShouldEqual, 0.25)
+				So(resumed.GetStates(), ShouldResemble, expected.GetStates())
+			})
+		})
+	})
+}
+
+func TestNeuralNetworkLayersScheduler(t *testing.T) {
+	Convey("Given : スケジューラーを設定したNNが与えられた時", t, func() {
+		nnLayers := NewDefaultNeuralNetworkLayers()
+		nnLayers.SetOptimizer(NewSGD(WithSGDLearningRate(1)))
+		nnLayers.SetScheduler(NewExponentialDecay(nnLayers.GetOptimizer(), 0.5))
+		Convey("When : Updateを2回実施する", func() {
+			nnLayers.Update()
+			nnLayers.Update()
+			Convey("Then : Update毎にスケジューラーが進むこと", func() {
+				So(nnLayers.GetOptimizer().GetLearningRate(), ShouldEqual, 0.25)
+			})
+		})
+	})
+}
+
+func stepScheduler(scheduler LearningRateScheduler, count int) []float64 {
+	lrs := make([]float64, 0, count)
+	for i := 0; i < count; i++ {
+		scheduler.Step()
+		lrs = append(lrs, scheduler.GetLearningRate())
+	}
+	return lrs
+}
@@ -0,0 +1,111 @@
+package neuralNetwork
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Shape : 1データ分のテンソルの形状（チャネル数・高さ・幅）
+// 行列の各行には、チャネル→高さ→幅の順にデータが並んでいるものとする
+// 1次元のベクトルデータは(要素数, 1, 1)の形状で表す
+type Shape struct {
+	Channel int
+	Height  int
+	Width   int
+}
+
+// NewShape : テンソルの形状を作成
+func NewShape(channel int, height int, width int) Shape {
+	return Shape{Channel: channel, Height: height, Width: width}
+}
+
+// NewFlatShape : 1次元のベクトルデータの形状を作成
+func NewFlatShape(size int) Shape {
+	return Shape{Channel: size, Height: 1, Width: 1}
+}
+
+// Size : 1データ分の要素数を取得
+func (s Shape) Size() int {
+	return s.Channel * s.Height * s.Width
+}
+
+// IsFlat : 1次元のベクトルデータの形状かどうか
+func (s Shape) IsFlat() bool {
+	return s.Height == 1 && s.Width == 1
+}
+
+// IsEmpty : 形状が未設定かどうか
+func (s Shape) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+func (s Shape) String() string {
+	return fmt.Sprintf("(%d, %d, %d)", s.Channel, s.Height, s.Width)
+}
+
+// ShapedLayer : 入力データの形状に応じて構成が決まるレイヤーのIF
+// IFを実装していないレイヤーは入力と出力の形状が同じものとして扱う
+type ShapedLayer interface {
+	// SetInputShape : 入力データの形状を設定し、出力データの形状を返す
+	SetInputShape(shape Shape) Shape
+}
+
+// Flatten : 入力データを1次元のベクトルデータとして扱うように形状を変換するレイヤー
+type Flatten struct {
+}
+
+// NewFlatten : Flattenの素子を取得
+func NewFlatten() *Flatten {
+	f := &Flatten{}
+	return f
+}
+
+func (f *Flatten) SetInputShape(shape Shape) Shape {
+	return NewFlatShape(shape.Size())
+}
+
+func (f *Flatten) Forward(x mat.Matrix) mat.Matrix {
+	// 各行のデータの並びは変わらないため、そのまま出力する
+	return x
+}
+
+func (f *Flatten) Backward(dout mat.Matrix) mat.Matrix {
+	return dout
+}
+
+// Reshape : 入力データを指定した形状として扱うように変換するレイヤー
+type Reshape struct {
+	shape Shape
+}
+
+// NewReshape : Reshapeの素子を取得
+// shape : 変換後の形状
+func NewReshape(shape Shape) *Reshape {
+	r := &Reshape{shape: shape}
+	return r
+}
+
+func (r *Reshape) SetInputShape(shape Shape) Shape {
+	if shape.Size() != r.shape.Size() {
+		panic(fmt.Sprintf("入力データの形状%sを%sに変換できません", shape, r.shape))
+	}
+	return r.shape
+}
+
+func (r *Reshape) Forward(x mat.Matrix) mat.Matrix {
+	_, c := x.Dims()
+	if c != r.shape.Size() {
+		panic(fmt.Sprintf("入力データの要素数%dを%sに変換できません", c, r.shape))
+	}
+	return x
+}
+
+func (r *Reshape) Backward(dout mat.Matrix) mat.Matrix {
+	return dout
+}
+
+// GetShape : 変換後の形状を取得
+func (r *Reshape) GetShape() Shape {
+	return r.shape
+}
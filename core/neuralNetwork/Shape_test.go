@@ -0,0 +1,84 @@
+package neuralNetwork
+
+import (
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFlattenAndReshape(t *testing.T) {
+	Convey("Given : 4チャネル・高さ2・幅3の形状が与えられた時", t, func() {
+		shape := NewShape(4, 2, 3)
+		Convey("When : Flattenに形状を設定する", func() {
+			out := NewFlatten().SetInputShape(shape)
+			Convey("Then : 要素数24の1次元の形状が出力されること", func() {
+				So(out, ShouldResemble, NewFlatShape(24))
+				So(out.IsFlat(), ShouldBeTrue)
+			})
+		})
+		Convey("When : 2チャネル・高さ3・幅4へのReshapeに形状を設定する", func() {
+			reshape := NewReshape(NewShape(2, 3, 4))
+			out := reshape.SetInputShape(shape)
+			Convey("Then : 変換後の形状が出力されること", func() {
+				So(out, ShouldResemble, NewShape(2, 3, 4))
+			})
+			Convey("Then : Forward, Backward処理では行列がそのまま出力されること", func() {
+				x := mat.NewDense(2, 24, util.CreateFloatArrayByStep(48, 0, 1))
+				So(mat.Equal(reshape.Forward(x), x), ShouldBeTrue)
+				So(mat.Equal(reshape.Backward(x), x), ShouldBeTrue)
+			})
+		})
+		Convey("When : 要素数の異なる形状へのReshapeに形状を設定する", func() {
+			reshape := NewReshape(NewShape(5, 5, 1))
+			Convey("Then : panicが発生すること", func() {
+				So(func() { reshape.SetInputShape(shape) }, ShouldPanic)
+			})
+		})
+	})
+}
+
+func TestNeuralNetworkLayersShape(t *testing.T) {
+	Convey("Given : 入力データの形状を1チャネル・高さ8・幅8としたNNが与えられた時", t, func() {
+		nnLayers := NewDefaultNeuralNetworkLayers()
+		nnLayers.SetInputShape(NewShape(1, 8, 8))
+		Convey("When : 畳み込み・プーリング・Flatten・Affineの順に素子を追加する", func() {
+			conv := NewConv2D(4, 3, 3, WithConv2DPadding(1))
+			pool := NewMaxPooling2D(2, 2)
+			affine := NewAffineWithOutputSize(10)
+			nnLayers.Add(conv)
+			nnLayers.Add(NewRelu())
+			nnLayers.Add(pool)
+			nnLayers.Add(NewFlatten())
+			nnLayers.Add(affine)
+			Convey("Then : 各素子に形状が伝搬されていること", func() {
+				So(conv.GetInputShape(), ShouldResemble, NewShape(1, 8, 8))
+				So(pool.GetInputShape(), ShouldResemble, NewShape(4, 8, 8))
+				So(pool.GetOutputShape(), ShouldResemble, NewShape(4, 4, 4))
+				r, c := affine.GetParams()["w"].Dims()
+				So(r, ShouldEqual, 4*4*4)
+				So(c, ShouldEqual, 10)
+				So(nnLayers.GetOutputShape(), ShouldResemble, NewFlatShape(10))
+			})
+			Convey("Then : Forward処理が行えること", func() {
+				x := mat.NewDense(2, 64, util.CreateFloatArrayByStep(128, 0, 0.01))
+				t := mat.NewDense(2, 10, nil)
+				t.Set(0, 1, 1)
+				t.Set(1, 3, 1)
+				loss, _ := nnLayers.Forward(x, t)
+				So(loss, ShouldBeGreaterThan, 0)
+			})
+			Convey("Then : 形状と異なる要素数の入力データではpanicが発生すること", func() {
+				x := mat.NewDense(2, 63, nil)
+				t := mat.NewDense(2, 10, nil)
+				So(func() { nnLayers.Forward(x, t) }, ShouldPanic)
+			})
+		})
+		Convey("When : 入力サイズの異なるAffineを追加する", func() {
+			Convey("Then : panicが発生すること", func() {
+				So(func() { nnLayers.Add(NewAffine(32, 10)) }, ShouldPanic)
+			})
+		})
+	})
+}
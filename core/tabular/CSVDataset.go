@@ -0,0 +1,398 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goMLLibrary/core/dataset"
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// MissingStrategy : 欠損値の扱い
+type MissingStrategy int
+
+const (
+	// MissingError : 欠損値があればエラーとする
+	MissingError MissingStrategy = iota
+	// MissingDrop : 欠損値を含む行を除外する
+	MissingDrop
+	// MissingMean : 特徴量の欠損値を列の平均値で補完する
+	MissingMean
+	// MissingMedian : 特徴量の欠損値を列の中央値で補完する
+	MissingMedian
+	// MissingConstant : 特徴量の欠損値を指定した値で補完する
+	MissingConstant
+)
+
+// defaultMissingTokens : デフォルトで欠損値とみなす文字列
+var defaultMissingTokens = []string{"", "NA", "N/A", "NaN", "nan", "null", "?"}
+
+// CSVDataset : ヘッダー付きのCSV/TSVファイルから、特徴量の列と正解ラベルの列を選んで作成したデータセット
+// 分類の場合、正解ラベルはMnistData.GetLabelVectorと同様にクラス番号をone-hot形式に変換する
+type CSVDataset struct {
+	*dataset.MatrixDataset
+	featureNames []string
+	labelName    string
+	classNames   []string
+	fillValues   map[string]float64
+}
+
+// csvConfig : CSV/TSVファイルの読み込み設定
+type csvConfig struct {
+	delimiter      rune
+	featureColumns []string
+	strategy       MissingStrategy
+	constant       float64
+	missingTokens  []string
+	fillValues     map[string]float64
+	classNames     []string
+	regression     bool
+}
+
+// CSVOption : CSV/TSVファイルの読み込みのオプション
+type CSVOption func(*csvConfig)
+
+// WithCSVDelimiter : 区切り文字指定のオプションを取得（デフォルトはカンマ、ReadCSVで拡張子が.tsvの場合はタブ）
+func WithCSVDelimiter(delimiter rune) CSVOption {
+	return func(c *csvConfig) {
+		c.delimiter = delimiter
+	}
+}
+
+// WithCSVFeatureColumns : 特徴量として利用する列名指定のオプションを取得（デフォルトは正解ラベル以外の全ての列）
+func WithCSVFeatureColumns(columns ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.featureColumns = columns
+	}
+}
+
+// WithCSVMissingStrategy : 欠損値の扱い指定のオプションを取得（デフォルトはMissingError）
+// 正解ラベルの欠損値は補完できないため、MissingDrop以外ではエラーとなる
+func WithCSVMissingStrategy(strategy MissingStrategy) CSVOption {
+	return func(c *csvConfig) {
+		c.strategy = strategy
+	}
+}
+
+// WithCSVMissingConstant : 特徴量の欠損値を指定した値で補完するオプションを取得
+func WithCSVMissingConstant(value float64) CSVOption {
+	return func(c *csvConfig) {
+		c.strategy = MissingConstant
+		c.constant = value
+	}
+}
+
+// WithCSVMissingTokens : 欠損値とみなす文字列指定のオプションを取得（前後の空白は無視する）
+func WithCSVMissingTokens(tokens ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.missingTokens = tokens
+	}
+}
+
+// WithCSVFillValues : 列毎の補完値指定のオプションを取得（指定した列は欠損値の扱いに関わらずこの値で補完する）
+// 学習用データのGetFillValuesを指定すると、検証用データを同じ値で補完できる
+func WithCSVFillValues(fillValues map[string]float64) CSVOption {
+	return func(c *csvConfig) {
+		c.fillValues = fillValues
+	}
+}
+
+// WithCSVClassNames : クラス番号の順に並べたクラス名指定のオプションを取得（デフォルトはラベルの値の昇順）
+// 学習用データと検証用データでクラス番号を揃える場合に利用する
+func WithCSVClassNames(classNames ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.classNames = classNames
+	}
+}
+
+// WithCSVRegression : 正解ラベルをone-hot形式に変換せず、数値としてそのまま利用するオプションを取得
+func WithCSVRegression() CSVOption {
+	return func(c *csvConfig) {
+		c.regression = true
+	}
+}
+
+// ReadCSV : ヘッダー付きのCSV/TSVファイルからデータセットを取得
+// labelColumn : 正解ラベルの列名
+func ReadCSV(filePath string, labelColumn string, options ...CSVOption) (*CSVDataset, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(filePath), ".tsv") {
+		options = append([]CSVOption{WithCSVDelimiter('\t')}, options...)
+	}
+	ds, err := ReadCSVFrom(f, labelColumn, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %v", filePath, err)
+	}
+	return ds, nil
+}
+
+// ReadCSVFrom : ヘッダー付きのCSV/TSV形式のデータからデータセットを取得
+// labelColumn : 正解ラベルの列名
+func ReadCSVFrom(r io.Reader, labelColumn string, options ...CSVOption) (*CSVDataset, error) {
+	c := csvConfig{delimiter: ',', missingTokens: defaultMissingTokens}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&c)
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = c.delimiter
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("ヘッダーとデータの行がありません")
+	}
+
+	header := records[0]
+	columnIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		columnIndex[strings.TrimSpace(name)] = i
+	}
+	labelIndex, ok := columnIndex[labelColumn]
+	if !ok {
+		return nil, fmt.Errorf("正解ラベルの列%sがありません", labelColumn)
+	}
+	featureNames := c.featureColumns
+	if featureNames == nil {
+		featureNames = make([]string, 0, len(header)-1)
+		for i, name := range header {
+			if i != labelIndex {
+				featureNames = append(featureNames, strings.TrimSpace(name))
+			}
+		}
+	}
+	featureIndexes := make([]int, len(featureNames))
+	for i, name := range featureNames {
+		index, ok := columnIndex[name]
+		if !ok {
+			return nil, fmt.Errorf("特徴量の列%sがありません", name)
+		}
+		if index == labelIndex {
+			return nil, fmt.Errorf("正解ラベルの列%sは特徴量に指定できません", name)
+		}
+		featureIndexes[i] = index
+	}
+
+	// 各行の特徴量を数値に変換する（欠損値はNaNとし、後で補完する）
+	features := make([][]float64, 0, len(records)-1)
+	labels := make([]string, 0, len(records)-1)
+	for line, record := range records[1:] {
+		label := strings.TrimSpace(record[labelIndex])
+		row := make([]float64, len(featureIndexes))
+		missing := c.isMissing(label)
+		for i, index := range featureIndexes {
+			value := strings.TrimSpace(record[index])
+			if c.isMissing(value) {
+				row[i] = math.NaN()
+				missing = true
+				continue
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%d行目の列%sの値%sが数値ではありません", line+2, featureNames[i], value)
+			}
+			row[i] = v
+		}
+
+		if missing && c.strategy == MissingDrop {
+			continue
+		}
+		if c.isMissing(label) {
+			return nil, fmt.Errorf("%d行目の正解ラベルが欠損しています", line+2)
+		}
+		features = append(features, row)
+		labels = append(labels, label)
+	}
+	if len(features) == 0 {
+		return nil, fmt.Errorf("欠損値を含まない行がありません")
+	}
+
+	fillValues, err := c.fill(features, featureNames)
+	if err != nil {
+		return nil, err
+	}
+	x := mat.NewDense(len(features), len(featureNames), nil)
+	for i, row := range features {
+		x.SetRow(i, row)
+	}
+
+	ds := CSVDataset{featureNames: featureNames, labelName: labelColumn, fillValues: fillValues}
+	t, err := ds.encodeLabels(labels, &c)
+	if err != nil {
+		return nil, err
+	}
+	ds.MatrixDataset = dataset.NewMatrixDataset(x, t)
+	return &ds, nil
+}
+
+// isMissing : 欠損値とみなす文字列かどうか
+func (c *csvConfig) isMissing(value string) bool {
+	for _, token := range c.missingTokens {
+		if value == token {
+			return true
+		}
+	}
+	return false
+}
+
+// fill : 特徴量の欠損値を補完し、列毎の補完値を返す
+func (c *csvConfig) fill(features [][]float64, featureNames []string) (map[string]float64, error) {
+	fillValues := make(map[string]float64)
+	for col, name := range featureNames {
+		values := make([]float64, 0, len(features))
+		missingRow := -1
+		for i, row := range features {
+			if math.IsNaN(row[col]) {
+				missingRow = i
+				continue
+			}
+			values = append(values, row[col])
+		}
+
+		fillValue, ok := c.fillValues[name]
+		if !ok {
+			switch c.strategy {
+			case MissingMean, MissingMedian:
+				if len(values) == 0 {
+					return nil, fmt.Errorf("列%sが全て欠損しているため補完できません", name)
+				}
+				fillValue, _ = util.MeanStd(values)
+				if c.strategy == MissingMedian {
+					fillValue = median(values)
+				}
+			case MissingConstant:
+				fillValue = c.constant
+			default:
+				if missingRow >= 0 {
+					return nil, fmt.Errorf("%d件目のデータの列%sが欠損しています", missingRow+1, name)
+				}
+				continue
+			}
+		}
+		fillValues[name] = fillValue
+		for _, row := range features {
+			if math.IsNaN(row[col]) {
+				row[col] = fillValue
+			}
+		}
+	}
+	return fillValues, nil
+}
+
+// encodeLabels : 正解ラベルを、分類の場合はone-hot形式、回帰の場合は数値の行列に変換する
+func (ds *CSVDataset) encodeLabels(labels []string, c *csvConfig) (*mat.Dense, error) {
+	if c.regression {
+		t := mat.NewDense(len(labels), 1, nil)
+		for i, label := range labels {
+			v, err := strconv.ParseFloat(label, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%d件目のデータの正解ラベル%sが数値ではありません", i+1, label)
+			}
+			t.Set(i, 0, v)
+		}
+		return t, nil
+	}
+
+	ds.classNames = c.classNames
+	if ds.classNames == nil {
+		ds.classNames = uniqueSorted(labels)
+	}
+	classIndex := make(map[string]int, len(ds.classNames))
+	for i, name := range ds.classNames {
+		classIndex[name] = i
+	}
+	t := mat.NewDense(len(labels), len(ds.classNames), nil)
+	for i, label := range labels {
+		index, ok := classIndex[label]
+		if !ok {
+			return nil, fmt.Errorf("%d件目のデータの正解ラベル%sが指定したクラス名にありません", i+1, label)
+		}
+		t.Set(i, index, 1)
+	}
+	return t, nil
+}
+
+// uniqueSorted : 重複を除いて昇順に並べた値を取得（全て数値の場合は数値の昇順とする）
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool)
+	unique := make([]string, 0)
+	numeric := true
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		unique = append(unique, v)
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			numeric = false
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		if numeric {
+			a, _ := strconv.ParseFloat(unique[i], 64)
+			b, _ := strconv.ParseFloat(unique[j], 64)
+			return a < b
+		}
+		return unique[i] < unique[j]
+	})
+	return unique
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// GetFeatureNames : 特徴量の列名を取得
+func (ds *CSVDataset) GetFeatureNames() []string {
+	return append([]string{}, ds.featureNames...)
+}
+
+// GetLabelName : 正解ラベルの列名を取得
+func (ds *CSVDataset) GetLabelName() string {
+	return ds.labelName
+}
+
+// GetClassNames : クラス番号の順に並べたクラス名を取得（WithCSVRegressionを指定した場合はnil）
+func (ds *CSVDataset) GetClassNames() []string {
+	return ds.classNames
+}
+
+// GetFillValues : 列毎の欠損値の補完値を取得（WithCSVFillValuesで検証用データの読み込みに利用できる）
+func (ds *CSVDataset) GetFillValues() map[string]float64 {
+	fillValues := make(map[string]float64, len(ds.fillValues))
+	for k, v := range ds.fillValues {
+		fillValues[k] = v
+	}
+	return fillValues
+}
+
+// ConvertMatrix : 全データを、(データ数, 特徴量の数)の入力と正解データの行列で取得
+func (ds *CSVDataset) ConvertMatrix() (x mat.Matrix, t mat.Matrix) {
+	indexes := make([]int, ds.Count())
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return ds.GetBatch(indexes)
+}
@@ -0,0 +1,161 @@
+package tabular
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+const irisCSV = `sepal_length,sepal_width,petal_length,species
+5.1,3.5,1.4,setosa
+7.0,3.2,4.7,versicolor
+6.3,3.3,6.0,virginica
+4.9,3.0,1.4,setosa
+`
+
+func TestReadCSV(t *testing.T) {
+	Convey("Given : ヘッダー付きのCSVが与えられた時", t, func() {
+		Convey("When : 正解ラベルの列を指定して読み込む", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(irisCSV), "species")
+			Convey("Then : 正解ラベル以外の列が特徴量となり、ラベルがone-hot形式に変換されること", func() {
+				So(err, ShouldBeNil)
+				So(ds.Count(), ShouldEqual, 4)
+				So(ds.GetFeatureNames(), ShouldResemble, []string{"sepal_length", "sepal_width", "petal_length"})
+				So(ds.GetLabelName(), ShouldEqual, "species")
+				So(ds.GetClassNames(), ShouldResemble, []string{"setosa", "versicolor", "virginica"})
+				x, label := ds.GetSample(2)
+				So(x, ShouldResemble, []float64{6.3, 3.3, 6.0})
+				So(label, ShouldResemble, []float64{0, 0, 1})
+			})
+			Convey("Then : バッチを行列で取得できること", func() {
+				x, label := ds.GetBatch([]int{3, 1})
+				So(mat.Equal(x, mat.NewDense(2, 3, []float64{4.9, 3.0, 1.4, 7.0, 3.2, 4.7})), ShouldBeTrue)
+				So(mat.Equal(label, mat.NewDense(2, 3, []float64{1, 0, 0, 0, 1, 0})), ShouldBeTrue)
+				all, _ := ds.ConvertMatrix()
+				r, c := all.Dims()
+				So(r, ShouldEqual, 4)
+				So(c, ShouldEqual, 3)
+			})
+		})
+		Convey("When : 特徴量の列とクラス名を指定して読み込む", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(irisCSV), "species",
+				WithCSVFeatureColumns("petal_length", "sepal_length"),
+				WithCSVClassNames("virginica", "setosa", "versicolor", "unknown"))
+			Convey("Then : 指定した順に特徴量とクラス番号が並ぶこと", func() {
+				So(err, ShouldBeNil)
+				x, label := ds.GetSample(0)
+				So(x, ShouldResemble, []float64{1.4, 5.1})
+				So(label, ShouldResemble, []float64{0, 1, 0, 0})
+			})
+		})
+		Convey("When : 数値のラベルを回帰の正解データとして読み込む", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(irisCSV), "petal_length", WithCSVFeatureColumns("sepal_length"), WithCSVRegression())
+			Convey("Then : ラベルがそのまま1列の正解データとなること", func() {
+				So(err, ShouldBeNil)
+				So(ds.GetClassNames(), ShouldBeNil)
+				_, label := ds.GetSample(1)
+				So(label, ShouldResemble, []float64{4.7})
+			})
+		})
+		Convey("Then : 不正な指定の場合はエラーとなること", func() {
+			_, err := ReadCSVFrom(strings.NewReader(irisCSV), "none")
+			So(err, ShouldNotBeNil)
+			_, err = ReadCSVFrom(strings.NewReader(irisCSV), "species", WithCSVFeatureColumns("none"))
+			So(err, ShouldNotBeNil)
+			_, err = ReadCSVFrom(strings.NewReader(irisCSV), "species", WithCSVFeatureColumns("species"))
+			So(err, ShouldNotBeNil)
+			_, err = ReadCSVFrom(strings.NewReader(irisCSV), "sepal_width", WithCSVClassNames("3.5"))
+			So(err, ShouldNotBeNil)
+			_, err = ReadCSVFrom(strings.NewReader(irisCSV), "petal_length", WithCSVRegression())
+			So(err, ShouldNotBeNil)
+			_, err = ReadCSVFrom(strings.NewReader("a,b\n"), "b")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given : 数値のラベルを持つCSVが与えられた時", t, func() {
+		data := "x,label\n1,10\n2,9\n3,10\n4,2\n"
+		Convey("When : 分類の正解ラベルとして読み込む", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(data), "label")
+			Convey("Then : クラス名が数値の昇順になること", func() {
+				So(err, ShouldBeNil)
+				So(ds.GetClassNames(), ShouldResemble, []string{"2", "9", "10"})
+			})
+		})
+	})
+
+	Convey("Given : 欠損値を含むCSVが与えられた時", t, func() {
+		data := "a,b,label\n1,NA,x\n3,4,y\n,8,x\n5,6,\n7,2,y\n"
+		Convey("Then : デフォルトではエラーとなること", func() {
+			_, err := ReadCSVFrom(strings.NewReader(data), "label")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("When : 欠損値を含む行を除外する", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(data), "label", WithCSVMissingStrategy(MissingDrop))
+			Convey("Then : 欠損値を含まない行のみとなること", func() {
+				So(err, ShouldBeNil)
+				So(ds.Count(), ShouldEqual, 2)
+				x, _ := ds.GetSample(1)
+				So(x, ShouldResemble, []float64{7, 2})
+			})
+		})
+		data = "a,b,label\n1,NA,x\n3,4,y\n,8,x\n7,2,y\n6,?,x\n"
+		Convey("When : 平均値で補完する", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(data), "label", WithCSVMissingStrategy(MissingMean))
+			Convey("Then : 列毎の平均値で補完されること", func() {
+				So(err, ShouldBeNil)
+				x, _ := ds.GetSample(0)
+				So(x, ShouldResemble, []float64{1, 14.0 / 3})
+				x, _ = ds.GetSample(2)
+				So(x, ShouldResemble, []float64{4.25, 8})
+				So(ds.GetFillValues(), ShouldResemble, map[string]float64{"a": 4.25, "b": 14.0 / 3})
+			})
+		})
+		Convey("When : 中央値で補完する", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(data), "label", WithCSVMissingStrategy(MissingMedian))
+			Convey("Then : 列毎の中央値で補完されること", func() {
+				So(err, ShouldBeNil)
+				So(ds.GetFillValues(), ShouldResemble, map[string]float64{"a": 4.5, "b": 4})
+			})
+		})
+		Convey("When : 定数で補完し、一部の列は補完値を指定する", func() {
+			ds, err := ReadCSVFrom(strings.NewReader(data), "label", WithCSVMissingConstant(-1), WithCSVFillValues(map[string]float64{"b": 100}))
+			Convey("Then : 指定した値で補完されること", func() {
+				So(err, ShouldBeNil)
+				x, _ := ds.GetSample(0)
+				So(x, ShouldResemble, []float64{1, 100})
+				x, _ = ds.GetSample(2)
+				So(x, ShouldResemble, []float64{-1, 8})
+			})
+		})
+		Convey("Then : 正解ラベルが欠損している場合は補完する設定でもエラーとなること", func() {
+			_, err := ReadCSVFrom(strings.NewReader("a,label\n1,x\n2,\n"), "label", WithCSVMissingStrategy(MissingMean))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given : TSVファイルが与えられた時", t, func() {
+		dir, _ := ioutil.TempDir("", "tabular")
+		defer os.RemoveAll(dir)
+		filePath := filepath.Join(dir, "data.tsv")
+		ioutil.WriteFile(filePath, []byte("f1\tf2\ty\n1\t2\ta\n3\t4\tb\n"), 0644)
+		Convey("When : ファイルを読み込む", func() {
+			ds, err := ReadCSV(filePath, "y")
+			Convey("Then : タブ区切りとして読み込めること", func() {
+				So(err, ShouldBeNil)
+				x, label := ds.GetSample(1)
+				So(x, ShouldResemble, []float64{3, 4})
+				So(label, ShouldResemble, []float64{0, 1})
+			})
+		})
+		Convey("Then : 存在しないファイルの場合はエラーとなること", func() {
+			_, err := ReadCSV(filepath.Join(dir, "none.csv"), "y")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
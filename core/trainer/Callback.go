@@ -0,0 +1,213 @@
+package trainer
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/goMLLibrary/core/graph"
+	"github.com/goMLLibrary/core/neuralNetwork"
+)
+
+// Callback : Trainerの学習中の各タイミングで呼び出される処理のIF
+type Callback interface {
+	// OnTrainBegin : 学習開始時に呼ばれる
+	OnTrainBegin(t *Trainer)
+	// OnBatchEnd : 1バッチの学習（パラメーターの更新）終了時に呼ばれる
+	OnBatchEnd(t *Trainer, log BatchLog)
+	// OnEpochEnd : 1epochの学習・検証データでの評価終了時に呼ばれる
+	OnEpochEnd(t *Trainer, log EpochLog)
+	// OnTrainEnd : 学習終了時に呼ばれる
+	OnTrainEnd(t *Trainer)
+}
+
+// StatefulCallback : 学習を再開する際に引き継ぐ状態を持つCallbackのIF
+// Trainer.GetStateで取得する進捗状況に含まれ、WithTrainerResumeで再開した時の学習開始時（OnTrainBeginの後）に設定される
+type StatefulCallback interface {
+	Callback
+	// GetStates : 学習を再開するための状態を取得
+	GetStates() map[string]float64
+	// SetStates : GetStatesで取得した状態を設定
+	SetStates(states map[string]float64)
+}
+
+// PostEpochCallback : 全てのCallbackのOnEpochEndの後に呼ばれる処理を持つCallbackのIF
+// 他のCallbackのそのepochの処理後の状態を利用する処理（進捗状況の保存など）を行う
+type PostEpochCallback interface {
+	Callback
+	// OnPostEpochEnd : 全てのCallbackのOnEpochEnd終了後に呼ばれる
+	OnPostEpochEnd(t *Trainer, log EpochLog)
+}
+
+// BaseCallback : 何もしないCallback
+// 埋め込むことで、必要なタイミングの処理だけを実装したCallbackを作成できる
+type BaseCallback struct{}
+
+func (cb *BaseCallback) OnTrainBegin(t *Trainer) {}
+
+func (cb *BaseCallback) OnBatchEnd(t *Trainer, log BatchLog) {}
+
+func (cb *BaseCallback) OnEpochEnd(t *Trainer, log EpochLog) {}
+
+func (cb *BaseCallback) OnTrainEnd(t *Trainer) {}
+
+// LoggingCallback : 学習の経過を出力するCallback
+type LoggingCallback struct {
+	BaseCallback
+	writer        io.Writer
+	batchInterval int
+}
+
+// LoggingCallbackOption : LoggingCallbackのオプション
+type LoggingCallbackOption func(*LoggingCallback)
+
+// NewLoggingCallback : LoggingCallbackを取得
+// デフォルトでは標準出力に、epoch毎の結果のみを出力する
+// 初期化時にオプション指定が可能
+func NewLoggingCallback(options ...LoggingCallbackOption) *LoggingCallback {
+	cb := LoggingCallback{writer: os.Stdout}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&cb)
+	}
+	return &cb
+}
+
+// WithLoggingCallbackWriter : 出力先指定のオプションを取得
+func WithLoggingCallbackWriter(writer io.Writer) LoggingCallbackOption {
+	return func(cb *LoggingCallback) {
+		cb.writer = writer
+	}
+}
+
+// WithLoggingCallbackBatchInterval : 指定したバッチ数毎にバッチの結果も出力するオプションを取得
+func WithLoggingCallbackBatchInterval(interval int) LoggingCallbackOption {
+	return func(cb *LoggingCallback) {
+		cb.batchInterval = interval
+	}
+}
+
+func (cb *LoggingCallback) OnBatchEnd(t *Trainer, log BatchLog) {
+	if cb.batchInterval > 0 && log.Iteration%cb.batchInterval == 0 {
+		fmt.Fprintf(cb.writer, "train %d iteration : loss is %f, accuracy is %f\n", log.Iteration, log.Loss, log.Accuracy)
+	}
+}
+
+func (cb *LoggingCallback) OnEpochEnd(t *Trainer, log EpochLog) {
+	fmt.Fprintf(cb.writer, "epoch %d : loss is %f, accuracy is %f", log.Epoch+1, log.Loss, log.Accuracy)
+	if log.HasValidation {
+		fmt.Fprintf(cb.writer, ", val_loss is %f, val_accuracy is %f", log.ValLoss, log.ValAccuracy)
+	}
+	fmt.Fprintln(cb.writer)
+}
+
+// GraphPointsCallback : 学習の経過をグラフの座標として記録するCallback
+type GraphPointsCallback struct {
+	BaseCallback
+	points   *graph.GraphPoints
+	monitor  string
+	perBatch bool
+}
+
+// GraphPointsCallbackOption : GraphPointsCallbackのオプション
+type GraphPointsCallbackOption func(*GraphPointsCallback)
+
+// NewGraphPointsCallback : GraphPointsCallbackを取得
+// points : 座標の追加先, monitor : 記録する値のキー（MonitorLossなど）
+// デフォルトではepoch毎に、x座標をepoch数として記録する
+// 初期化時にオプション指定が可能
+func NewGraphPointsCallback(points *graph.GraphPoints, monitor string, options ...GraphPointsCallbackOption) *GraphPointsCallback {
+	cb := GraphPointsCallback{points: points, monitor: monitor}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&cb)
+	}
+	if cb.perBatch && cb.monitor != MonitorLoss && cb.monitor != MonitorAccuracy {
+		panic(fmt.Sprintf("バッチ毎に記録できる値はloss, accuracyのみです : %s", cb.monitor))
+	}
+	return &cb
+}
+
+// WithGraphPointsCallbackPerBatch : バッチ毎に、x座標を通算のバッチ番号として記録するオプションを取得
+func WithGraphPointsCallbackPerBatch() GraphPointsCallbackOption {
+	return func(cb *GraphPointsCallback) {
+		cb.perBatch = true
+	}
+}
+
+func (cb *GraphPointsCallback) OnBatchEnd(t *Trainer, log BatchLog) {
+	if !cb.perBatch {
+		return
+	}
+	value := log.Accuracy
+	if cb.monitor == MonitorLoss {
+		value = log.Loss
+	}
+	cb.points.AddPoint(graph.NewPoint(float64(log.Iteration), value))
+}
+
+func (cb *GraphPointsCallback) OnEpochEnd(t *Trainer, log EpochLog) {
+	if cb.perBatch {
+		return
+	}
+	if value, ok := log.Get(cb.monitor); ok {
+		cb.points.AddPoint(graph.NewPoint(float64(log.Epoch+1), value))
+	}
+}
+
+// SchedulerCallback : epoch毎に学習率のスケジューラーを1ステップ進めるCallback
+type SchedulerCallback struct {
+	BaseCallback
+	scheduler neuralNetwork.LearningRateScheduler
+}
+
+// NewSchedulerCallback : SchedulerCallbackを取得
+// 1iteration毎に進める場合はCallbackを利用せず、NeuralNetworkLayers.SetSchedulerで設定すること
+func NewSchedulerCallback(scheduler neuralNetwork.LearningRateScheduler) *SchedulerCallback {
+	return &SchedulerCallback{scheduler: scheduler}
+}
+
+func (cb *SchedulerCallback) OnEpochEnd(t *Trainer, log EpochLog) {
+	cb.scheduler.Step()
+}
+
+// GetStates : スケジューラーの状態を取得
+func (cb *SchedulerCallback) GetStates() map[string]float64 {
+	return cb.scheduler.GetStates()
+}
+
+// SetStates : スケジューラーの状態を設定
+func (cb *SchedulerCallback) SetStates(states map[string]float64) {
+	cb.scheduler.SetStates(states)
+}
+
+// MetricSchedulerCallback : epoch毎に評価値を与えて学習率のスケジューラー（ReduceLROnPlateauなど）を進めるCallback
+type MetricSchedulerCallback struct {
+	BaseCallback
+	scheduler neuralNetwork.MetricScheduler
+	monitor   string
+}
+
+// NewMetricSchedulerCallback : MetricSchedulerCallbackを取得
+// monitor : スケジューラーに与える値のキー（MonitorValLossなど）
+func NewMetricSchedulerCallback(scheduler neuralNetwork.MetricScheduler, monitor string) *MetricSchedulerCallback {
+	return &MetricSchedulerCallback{scheduler: scheduler, monitor: monitor}
+}
+
+func (cb *MetricSchedulerCallback) OnEpochEnd(t *Trainer, log EpochLog) {
+	if value, ok := log.Get(cb.monitor); ok {
+		cb.scheduler.StepWithMetric(value)
+	}
+}
+
+// GetStates : スケジューラーの状態を取得
+func (cb *MetricSchedulerCallback) GetStates() map[string]float64 {
+	return cb.scheduler.GetStates()
+}
+
+// SetStates : スケジューラーの状態を設定
+func (cb *MetricSchedulerCallback) SetStates(states map[string]float64) {
+	cb.scheduler.SetStates(states)
+}
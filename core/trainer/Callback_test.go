@@ -0,0 +1,73 @@
+package trainer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/goMLLibrary/core/graph"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCallbacks(t *testing.T) {
+	Convey("Given : 90件の学習データと30件の検証データでバッチサイズ30, 3epochの学習を行う時", t, func() {
+		util.SetSeed(2)
+		train := newClusterDataSet(90)
+		validation := newClusterDataSet(30)
+		nnLayers := newClassifier()
+		fit := func(callbacks ...Callback) *History {
+			return NewTrainer(nnLayers, train, 30, 3,
+				WithTrainerValidationData(validation), WithTrainerCallbacks(callbacks...)).Fit()
+		}
+
+		Convey("When : 2バッチ毎に出力するLoggingCallbackを利用する", func() {
+			buf := new(bytes.Buffer)
+			fit(NewLoggingCallback(WithLoggingCallbackWriter(buf), WithLoggingCallbackBatchInterval(2)))
+			Convey("Then : バッチ毎・epoch毎の結果が出力されること", func() {
+				lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+				// バッチ : 0, 2, 4, 6, 8 iteration, epoch : 3回
+				So(len(lines), ShouldEqual, 8)
+				So(lines[0], ShouldStartWith, "train 0 iteration")
+				So(lines[2], ShouldStartWith, "epoch 1 : loss is")
+				So(lines[2], ShouldContainSubstring, "val_accuracy is")
+			})
+		})
+
+		Convey("When : GraphPointsCallbackを利用する", func() {
+			epochPoints := graph.NewGraphPoints("val_loss")
+			batchPoints := graph.NewGraphPoints("accuracy")
+			history := fit(NewGraphPointsCallback(&epochPoints, MonitorValLoss),
+				NewGraphPointsCallback(&batchPoints, MonitorAccuracy, WithGraphPointsCallbackPerBatch()))
+			Convey("Then : epoch毎・バッチ毎に座標が記録されること", func() {
+				So(len(epochPoints.GetPoints()), ShouldEqual, 3)
+				So(epochPoints.GetPoints()[2], ShouldResemble, graph.NewPoint(3, history.Epochs[2].ValLoss))
+				So(len(batchPoints.GetPoints()), ShouldEqual, 9)
+			})
+			Convey("Then : バッチ毎に検証データの値を記録しようとするとpanicが発生すること", func() {
+				So(func() { NewGraphPointsCallback(&batchPoints, MonitorValLoss, WithGraphPointsCallbackPerBatch()) }, ShouldPanic)
+			})
+		})
+
+		Convey("When : 1epoch毎に学習率を0.5倍するSchedulerCallbackを利用する", func() {
+			optimizer := neuralNetwork.NewSGD()
+			nnLayers.SetOptimizer(optimizer)
+			fit(NewSchedulerCallback(neuralNetwork.NewStepDecay(optimizer, 1, neuralNetwork.WithStepDecayGamma(0.5))))
+			Convey("Then : 学習率が3回減衰していること", func() {
+				So(optimizer.GetLearningRate(), ShouldAlmostEqual, neuralNetwork.DefaultLearningRate/8, 1e-12)
+			})
+		})
+
+		Convey("When : 改善しない評価値を与えるMetricSchedulerCallbackを利用する", func() {
+			optimizer := neuralNetwork.NewSGD()
+			nnLayers.SetOptimizer(optimizer)
+			scheduler := neuralNetwork.NewReduceLROnPlateau(optimizer,
+				neuralNetwork.WithReduceLROnPlateauPatience(0), neuralNetwork.WithReduceLROnPlateauMinDelta(100))
+			fit(NewMetricSchedulerCallback(scheduler, MonitorValLoss))
+			Convey("Then : 2epoch目以降で学習率が減衰していること", func() {
+				So(optimizer.GetLearningRate(), ShouldAlmostEqual, neuralNetwork.DefaultLearningRate/100, 1e-12)
+			})
+		})
+	})
+}
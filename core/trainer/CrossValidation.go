@@ -0,0 +1,119 @@
+package trainer
+
+import (
+	"github.com/goMLLibrary/core/dataset"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+)
+
+// ModelFactory : 交差検証の各foldで学習する、初期化済みのNNを作成する関数
+// fold : 0から始まるfoldの番号
+type ModelFactory func(fold int) *neuralNetwork.NeuralNetworkLayers
+
+// FoldResult : 交差検証の1つのfoldの結果
+type FoldResult struct {
+	Fold            int // 0から始まるfoldの番号
+	History         *History
+	Loss            float64 // 学習後のNNでの検証データの損失
+	Accuracy        float64 // 学習後のNNでの検証データの正解率
+	TrainCount      int
+	ValidationCount int
+}
+
+// CrossValidationResult : 交差検証全体の結果
+// 平均と標準偏差は各foldの検証データの評価値から算出する（標準偏差はfold数で割る母標準偏差）
+type CrossValidationResult struct {
+	Folds        []FoldResult
+	MeanLoss     float64
+	StdLoss      float64
+	MeanAccuracy float64
+	StdAccuracy  float64
+}
+
+// CrossValidator : 層化k分割交差検証を行う
+type CrossValidator struct {
+	factory        ModelFactory
+	k              int
+	batchSize      int
+	epochs         int
+	splitOptions   []dataset.SplitOption
+	trainerOptions func(fold int) []TrainerOption
+}
+
+// CrossValidatorOption : CrossValidatorのオプション
+type CrossValidatorOption func(*CrossValidator)
+
+// NewCrossValidator : CrossValidatorを取得
+// factory : 各foldで学習するNNを作成する関数, k : 分割数
+// batchSize, epochs : 各foldの学習のバッチサイズとepoch数
+// 初期化時にオプション指定が可能
+func NewCrossValidator(factory ModelFactory, k int, batchSize int, epochs int, options ...CrossValidatorOption) *CrossValidator {
+	if k < 2 {
+		panic("kは2以上を指定してください")
+	}
+	if batchSize <= 0 || epochs <= 0 {
+		panic("batchSize, epochsは1以上を指定してください")
+	}
+	cv := CrossValidator{factory: factory, k: k, batchSize: batchSize, epochs: epochs}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&cv)
+	}
+	return &cv
+}
+
+// WithCrossValidatorSplitOptions : データセットの分割のオプション（dataset.WithSplitRandなど）指定のオプションを取得
+func WithCrossValidatorSplitOptions(options ...dataset.SplitOption) CrossValidatorOption {
+	return func(cv *CrossValidator) {
+		cv.splitOptions = options
+	}
+}
+
+// WithCrossValidatorTrainerOptions : 各foldのTrainerのオプション指定のオプションを取得
+// EarlyStoppingなどの状態を持つコールバックはfold毎に作成する必要があるため、foldの番号からオプションを作成する関数を指定する
+// 検証データには各foldの検証データが設定されるため、WithTrainerValidationDataは指定しないこと
+func WithCrossValidatorTrainerOptions(trainerOptions func(fold int) []TrainerOption) CrossValidatorOption {
+	return func(cv *CrossValidator) {
+		cv.trainerOptions = trainerOptions
+	}
+}
+
+// Run : データセットをdataset.StratifiedKFoldで分割し、各foldで学習・評価した結果を返す
+func (cv *CrossValidator) Run(ds dataset.Dataset) *CrossValidationResult {
+	folds := dataset.StratifiedKFold(ds, cv.k, cv.splitOptions...)
+	result := CrossValidationResult{Folds: make([]FoldResult, 0, len(folds))}
+	for i, fold := range folds {
+		options := []TrainerOption{WithTrainerValidationData(fold.Validation)}
+		if cv.trainerOptions != nil {
+			options = append(options, cv.trainerOptions(i)...)
+		}
+		t := NewTrainer(cv.factory(i), fold.Train, cv.batchSize, cv.epochs, options...)
+		history := t.Fit()
+
+		// EarlyStoppingなどで最良のパラメーターに戻した場合も考慮し、学習後のNNで改めて評価する
+		loss, accuracy := t.Evaluate(fold.Validation)
+		result.Folds = append(result.Folds, FoldResult{
+			Fold:            i,
+			History:         history,
+			Loss:            loss,
+			Accuracy:        accuracy,
+			TrainCount:      fold.Train.Count(),
+			ValidationCount: fold.Validation.Count(),
+		})
+	}
+
+	losses := make([]float64, len(result.Folds))
+	accuracies := make([]float64, len(result.Folds))
+	for i, fr := range result.Folds {
+		losses[i], accuracies[i] = fr.Loss, fr.Accuracy
+	}
+	result.MeanLoss, result.StdLoss = util.MeanStd(losses)
+	result.MeanAccuracy, result.StdAccuracy = util.MeanStd(accuracies)
+	return &result
+}
+
+// CrossValidate : NewCrossValidatorで作成したCrossValidatorで交差検証を行う
+func CrossValidate(ds dataset.Dataset, factory ModelFactory, k int, batchSize int, epochs int, options ...CrossValidatorOption) *CrossValidationResult {
+	return NewCrossValidator(factory, k, batchSize, epochs, options...).Run(ds)
+}
@@ -0,0 +1,58 @@
+package trainer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/goMLLibrary/core/dataset"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCrossValidation(t *testing.T) {
+	Convey("Given : 3クラスに分かれる2次元のデータセットとNNの作成関数が与えられた時", t, func() {
+		util.SetSeed(1)
+		ds := newClusterDataSet(90)
+		created := make([]int, 0)
+		factory := func(fold int) *neuralNetwork.NeuralNetworkLayers {
+			created = append(created, fold)
+			return newClassifier()
+		}
+
+		Convey("When : 3分割交差検証を行う", func() {
+			stoppings := make([]*EarlyStopping, 0)
+			result := CrossValidate(ds, factory, 3, 16, 5,
+				WithCrossValidatorSplitOptions(dataset.WithSplitRand(rand.New(util.NewSource(1)))),
+				WithCrossValidatorTrainerOptions(func(fold int) []TrainerOption {
+					es := NewEarlyStopping(MonitorValLoss, 2)
+					stoppings = append(stoppings, es)
+					return []TrainerOption{WithTrainerCallbacks(es)}
+				}))
+			Convey("Then : foldの数だけNNを作成して学習し、各foldの検証データで評価されること", func() {
+				So(created, ShouldResemble, []int{0, 1, 2})
+				So(len(stoppings), ShouldEqual, 3)
+				So(len(result.Folds), ShouldEqual, 3)
+				for i, fr := range result.Folds {
+					So(fr.Fold, ShouldEqual, i)
+					So(fr.TrainCount, ShouldEqual, 60)
+					So(fr.ValidationCount, ShouldEqual, 30)
+					So(fr.History.Epochs[0].HasValidation, ShouldBeTrue)
+					So(fr.Accuracy, ShouldBeGreaterThan, 0.9)
+				}
+			})
+			Convey("Then : 各foldの評価値の平均と標準偏差が算出されること", func() {
+				accuracies := []float64{result.Folds[0].Accuracy, result.Folds[1].Accuracy, result.Folds[2].Accuracy}
+				_, std := util.MeanStd(accuracies)
+				So(result.MeanAccuracy, ShouldAlmostEqual, (accuracies[0]+accuracies[1]+accuracies[2])/3, 1e-12)
+				So(result.StdAccuracy, ShouldEqual, std)
+				So(result.MeanLoss, ShouldBeGreaterThan, 0)
+			})
+		})
+
+		Convey("Then : 不正な引数を指定した場合はpanicが発生すること", func() {
+			So(func() { NewCrossValidator(factory, 1, 16, 5) }, ShouldPanic)
+			So(func() { NewCrossValidator(factory, 3, 0, 5) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,329 @@
+package trainer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goMLLibrary/core/model"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/transform"
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// monitorTracker : 評価値の最良値を記録し、改善したかどうかを判定する
+// キーにaccuracyを含む場合は大きいほど良い値、それ以外（lossなど）は小さいほど良い値として扱う
+type monitorTracker struct {
+	monitor  string
+	minDelta float64
+	best     float64
+	hasBest  bool
+}
+
+// update : 評価値が改善していれば最良値を更新してtrueを返す
+func (mt *monitorTracker) update(log EpochLog) bool {
+	value, ok := log.Get(mt.monitor)
+	if !ok {
+		panic(fmt.Sprintf("評価値%sが取得できません（検証データが設定されているか確認してください）", mt.monitor))
+	}
+	improved := !mt.hasBest
+	if mt.hasBest {
+		if strings.Contains(mt.monitor, MonitorAccuracy) {
+			improved = value > mt.best+mt.minDelta
+		} else {
+			improved = value < mt.best-mt.minDelta
+		}
+	}
+	if improved {
+		mt.best = value
+		mt.hasBest = true
+	}
+	return improved
+}
+
+// getStates : 最良値の状態を取得
+func (mt *monitorTracker) getStates() map[string]float64 {
+	states := map[string]float64{"best": mt.best, "hasBest": 0}
+	if mt.hasBest {
+		states["hasBest"] = 1
+	}
+	return states
+}
+
+// setStates : getStatesで取得した最良値の状態を設定
+func (mt *monitorTracker) setStates(states map[string]float64) {
+	mt.best = states["best"]
+	mt.hasBest = states["hasBest"] != 0
+}
+
+// EarlyStopping : 評価値が一定epoch改善しない場合に学習を終了するCallback
+type EarlyStopping struct {
+	BaseCallback
+	tracker            monitorTracker
+	patience           int
+	restoreBestWeights bool
+
+	wait        int
+	bestEpoch   int
+	stoppedAt   int // 学習を終了したepoch（終了していない場合は-1）
+	bestWeights []layerWeights
+}
+
+// EarlyStoppingOption : EarlyStoppingのオプション
+type EarlyStoppingOption func(*EarlyStopping)
+
+// NewEarlyStopping : EarlyStoppingを取得
+// monitor : 監視する評価値のキー（MonitorValLossなど）
+// patience : 改善しなくても学習を続けるepoch数
+// 初期化時にオプション指定が可能
+func NewEarlyStopping(monitor string, patience int, options ...EarlyStoppingOption) *EarlyStopping {
+	es := EarlyStopping{tracker: monitorTracker{monitor: monitor}, patience: patience, stoppedAt: -1}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&es)
+	}
+	return &es
+}
+
+// WithEarlyStoppingMinDelta : 改善とみなす評価値の最小変化量指定のオプションを取得
+func WithEarlyStoppingMinDelta(minDelta float64) EarlyStoppingOption {
+	return func(es *EarlyStopping) {
+		es.tracker.minDelta = minDelta
+	}
+}
+
+// WithEarlyStoppingRestoreBestWeights : 学習終了時に、評価値が最良だったepochのパラメーターに戻すオプションを取得
+// 最良のパラメーターはメモリ上に保持するため、学習を再開した後に評価値が改善しなかった場合は戻さない
+func WithEarlyStoppingRestoreBestWeights() EarlyStoppingOption {
+	return func(es *EarlyStopping) {
+		es.restoreBestWeights = true
+	}
+}
+
+func (es *EarlyStopping) OnTrainBegin(t *Trainer) {
+	es.tracker.hasBest = false
+	es.wait = 0
+	es.bestEpoch = -1
+	es.stoppedAt = -1
+	es.bestWeights = nil
+}
+
+func (es *EarlyStopping) OnEpochEnd(t *Trainer, log EpochLog) {
+	if es.tracker.update(log) {
+		es.wait = 0
+		es.bestEpoch = log.Epoch
+		if es.restoreBestWeights {
+			es.bestWeights = copyLayerWeights(t.GetLayers())
+		}
+		return
+	}
+
+	es.wait++
+	if es.wait > es.patience {
+		es.stoppedAt = log.Epoch
+		t.StopTraining()
+	}
+}
+
+func (es *EarlyStopping) OnTrainEnd(t *Trainer) {
+	if es.restoreBestWeights && es.bestWeights != nil {
+		restoreLayerWeights(t.GetLayers(), es.bestWeights)
+	}
+}
+
+// GetStates : 学習を再開するための状態（最良値・改善しなかったepoch数など）を取得
+func (es *EarlyStopping) GetStates() map[string]float64 {
+	states := es.tracker.getStates()
+	states["wait"] = float64(es.wait)
+	states["bestEpoch"] = float64(es.bestEpoch)
+	return states
+}
+
+// SetStates : GetStatesで取得した状態を設定
+func (es *EarlyStopping) SetStates(states map[string]float64) {
+	es.tracker.setStates(states)
+	es.wait = int(states["wait"])
+	es.bestEpoch = int(states["bestEpoch"])
+}
+
+// GetBestEpoch : 評価値が最良だった0から始まるepoch数を取得
+func (es *EarlyStopping) GetBestEpoch() int {
+	return es.bestEpoch
+}
+
+// GetBest : 評価値の最良値を取得
+func (es *EarlyStopping) GetBest() float64 {
+	return es.tracker.best
+}
+
+// GetStoppedEpoch : 学習を終了した0から始まるepoch数を取得（途中で終了していない場合は-1）
+func (es *EarlyStopping) GetStoppedEpoch() int {
+	return es.stoppedAt
+}
+
+// ModelCheckpoint : 評価値が改善した時点のNNをmodel.WriteCheckpointWithTransformでファイルに保存するCallback
+// 学習の進捗状況もあわせて保存するため、保存したファイルから学習を再開できる
+// 保存はPostEpochCallbackとして全てのコールバックのepoch終了時の処理の後に行うため、他のコールバック（スケジューラーなど）のそのepochの処理後の状態が保存される
+type ModelCheckpoint struct {
+	BaseCallback
+	path             string
+	tracker          monitorTracker
+	saveAll          bool
+	restoreBestOnEnd bool
+	pipeline         *transform.Pipeline
+
+	best []layerWeights // 評価値が最良だった時点のパラメーター（restoreBestOnEndの場合のみ保持）
+	save bool           // そのepochの終了時に保存するかどうか
+	err  error
+}
+
+// ModelCheckpointOption : ModelCheckpointのオプション
+type ModelCheckpointOption func(*ModelCheckpoint)
+
+// NewModelCheckpoint : ModelCheckpointを取得
+// path : 保存先のファイルパス, monitor : 監視する評価値のキー（MonitorValLossなど）
+// デフォルトでは評価値が改善した場合のみ保存する
+// 初期化時にオプション指定が可能
+func NewModelCheckpoint(path string, monitor string, options ...ModelCheckpointOption) *ModelCheckpoint {
+	mc := ModelCheckpoint{path: path, tracker: monitorTracker{monitor: monitor}}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&mc)
+	}
+	return &mc
+}
+
+// WithModelCheckpointMinDelta : 改善とみなす評価値の最小変化量指定のオプションを取得
+func WithModelCheckpointMinDelta(minDelta float64) ModelCheckpointOption {
+	return func(mc *ModelCheckpoint) {
+		mc.tracker.minDelta = minDelta
+	}
+}
+
+// WithModelCheckpointSaveAll : 評価値に関わらずepoch毎に保存するオプションを取得
+func WithModelCheckpointSaveAll() ModelCheckpointOption {
+	return func(mc *ModelCheckpoint) {
+		mc.saveAll = true
+	}
+}
+
+// WithModelCheckpointRestoreBest : 学習終了時に、評価値が最良だった時点のパラメーターに戻すオプションを取得
+// 最良のパラメーターはメモリ上に保持して戻すため、WithModelCheckpointSaveAllでファイルが上書きされていても最良の時点に戻る
+// 再開前のepochが最良で再開後に改善しなかった場合は、保存したファイルから読み込む（WithModelCheckpointSaveAllの場合は戻さない）
+func WithModelCheckpointRestoreBest() ModelCheckpointOption {
+	return func(mc *ModelCheckpoint) {
+		mc.restoreBestOnEnd = true
+	}
+}
+
+// WithModelCheckpointTransform : 学習データでFitした入力データの前処理をあわせて保存するオプションを取得
+// 保存したファイルをmodel.ReadNNLayersWithTransformで読み込むことで、推論時に同じ前処理を適用できる
+func WithModelCheckpointTransform(pipeline *transform.Pipeline) ModelCheckpointOption {
+	return func(mc *ModelCheckpoint) {
+		mc.pipeline = pipeline
+	}
+}
+
+func (mc *ModelCheckpoint) OnTrainBegin(t *Trainer) {
+	mc.tracker.hasBest = false
+	mc.best = nil
+	mc.save = false
+	mc.err = nil
+}
+
+func (mc *ModelCheckpoint) OnEpochEnd(t *Trainer, log EpochLog) {
+	improved := mc.tracker.update(log)
+	if improved && mc.restoreBestOnEnd {
+		mc.best = copyLayerWeights(t.GetLayers())
+	}
+	mc.save = improved || mc.saveAll
+}
+
+// OnPostEpochEnd : OnEpochEndで保存すると判定した場合に、全てのコールバックの処理後の進捗状況とあわせて保存する
+func (mc *ModelCheckpoint) OnPostEpochEnd(t *Trainer, log EpochLog) {
+	if !mc.save {
+		return
+	}
+	mc.save = false
+	if err := model.WriteCheckpointWithTransform(mc.path, t.GetLayers(), t.GetState(), mc.pipeline); err != nil {
+		mc.err = err
+	}
+}
+
+func (mc *ModelCheckpoint) OnTrainEnd(t *Trainer) {
+	if !mc.restoreBestOnEnd {
+		return
+	}
+	if mc.best != nil {
+		restoreLayerWeights(t.GetLayers(), mc.best)
+		return
+	}
+
+	// 再開前のepochが最良で、再開後に改善しなかった場合は、改善時のみ保存しているファイルから読み込む
+	if !mc.tracker.hasBest || mc.saveAll || mc.err != nil {
+		return
+	}
+	saved, err := model.ReadNNLayers(mc.path)
+	if err != nil {
+		mc.err = err
+		return
+	}
+	restoreLayerWeights(t.GetLayers(), copyLayerWeights(saved))
+}
+
+// GetStates : 学習を再開するための状態（最良値）を取得
+func (mc *ModelCheckpoint) GetStates() map[string]float64 {
+	return mc.tracker.getStates()
+}
+
+// SetStates : GetStatesで取得した状態を設定
+func (mc *ModelCheckpoint) SetStates(states map[string]float64) {
+	mc.tracker.setStates(states)
+}
+
+// GetBest : 評価値の最良値を取得
+func (mc *ModelCheckpoint) GetBest() float64 {
+	return mc.tracker.best
+}
+
+// GetError : 保存・読み込み時に発生したエラーを取得（エラーが無い場合はnil）
+func (mc *ModelCheckpoint) GetError() error {
+	return mc.err
+}
+
+// layerWeights : 1つの素子のパラメーターと状態（BatchNormalizationの推論用の平均・分散など）
+type layerWeights struct {
+	params map[string]mat.Matrix
+	states map[string]mat.Matrix
+}
+
+// copyLayerWeights : 各素子のパラメーターと状態のコピーを取得
+func copyLayerWeights(nnLayers *neuralNetwork.NeuralNetworkLayers) []layerWeights {
+	weights := make([]layerWeights, len(nnLayers.GetLayers()))
+	for i, layer := range nnLayers.GetLayers() {
+		if paramLayer, ok := layer.(neuralNetwork.NeuralNetworkLayer); ok {
+			weights[i].params = util.CopyMatrixMap(paramLayer.GetParams())
+		}
+		if stateLayer, ok := layer.(neuralNetwork.StatefulLayer); ok {
+			weights[i].states = util.CopyMatrixMap(stateLayer.GetStates())
+		}
+	}
+	return weights
+}
+
+// restoreLayerWeights : 各素子にパラメーターと状態を設定する
+func restoreLayerWeights(nnLayers *neuralNetwork.NeuralNetworkLayers, weights []layerWeights) {
+	if len(weights) != len(nnLayers.GetLayers()) {
+		panic(fmt.Sprintf("素子数%dと復元する素子数%dがマッチしてません", len(nnLayers.GetLayers()), len(weights)))
+	}
+	for i, layer := range nnLayers.GetLayers() {
+		if paramLayer, ok := layer.(neuralNetwork.NeuralNetworkLayer); ok && weights[i].params != nil {
+			paramLayer.UpdateParams(util.CopyMatrixMap(weights[i].params))
+		}
+		if stateLayer, ok := layer.(neuralNetwork.StatefulLayer); ok && weights[i].states != nil {
+			stateLayer.SetStates(util.CopyMatrixMap(weights[i].states))
+		}
+	}
+}
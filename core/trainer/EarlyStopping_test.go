@@ -0,0 +1,200 @@
+package trainer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/goMLLibrary/core/model"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/transform"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestEarlyStopping(t *testing.T) {
+	Convey("Given : 3クラスに分かれる2次元のデータセットとNNが与えられた時", t, func() {
+		util.SetSeed(3)
+		train := newClusterDataSet(90)
+		validation := newClusterDataSet(30)
+		nnLayers := newClassifier()
+		fit := func(epochs int, callbacks ...Callback) *History {
+			return NewTrainer(nnLayers, train, 30, epochs,
+				WithTrainerValidationData(validation), WithTrainerCallbacks(callbacks...)).Fit()
+		}
+
+		Convey("When : 改善とみなす変化量を大きくし、patienceを2としたEarlyStoppingを利用する", func() {
+			es := NewEarlyStopping(MonitorValLoss, 2, WithEarlyStoppingMinDelta(100))
+			history := fit(10, es)
+			Convey("Then : 1epoch目以降改善しないため、4epochで学習が終了すること", func() {
+				So(len(history.Epochs), ShouldEqual, 4)
+				So(es.GetBestEpoch(), ShouldEqual, 0)
+				So(es.GetBest(), ShouldEqual, history.Epochs[0].ValLoss)
+				So(es.GetStoppedEpoch(), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When : 学習率を0にして評価値が改善しない状態でEarlyStoppingを利用する", func() {
+			nnLayers.SetOptimizer(neuralNetwork.NewSGD(neuralNetwork.WithSGDLearningRate(0)))
+			es := NewEarlyStopping(MonitorValAccuracy, 0)
+			history := fit(10, es)
+			Convey("Then : accuracyは大きいほど良い値として扱われ、2epochで学習が終了すること", func() {
+				So(len(history.Epochs), ShouldEqual, 2)
+				So(es.GetBest(), ShouldEqual, history.Epochs[0].ValAccuracy)
+			})
+		})
+
+		Convey("When : 最良のパラメーターに戻すEarlyStoppingを利用する", func() {
+			es := NewEarlyStopping(MonitorValLoss, 1, WithEarlyStoppingMinDelta(100), WithEarlyStoppingRestoreBestWeights())
+			recorder := &weightsRecorder{}
+			fit(10, recorder, es)
+			Convey("Then : 学習終了時に1epoch目終了時点のパラメーターに戻っていること", func() {
+				w := nnLayers.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+				So(mat.Equal(w, recorder.weights[0]), ShouldBeTrue)
+				So(mat.Equal(w, recorder.weights[len(recorder.weights)-1]), ShouldBeFalse)
+			})
+		})
+
+		Convey("When : 検証データを与えずにval_lossを監視する", func() {
+			es := NewEarlyStopping(MonitorValLoss, 1)
+			Convey("Then : panicが発生すること", func() {
+				So(func() { NewTrainer(nnLayers, train, 30, 2, WithTrainerCallbacks(es)).Fit() }, ShouldPanic)
+			})
+		})
+	})
+}
+
+func TestModelCheckpoint(t *testing.T) {
+	Convey("Given : 3クラスに分かれる2次元のデータセットとNNが与えられた時", t, func() {
+		util.SetSeed(4)
+		train := newClusterDataSet(90)
+		validation := newClusterDataSet(30)
+		nnLayers := newClassifier()
+		modelPath := "checkpoint.db"
+		defer os.Remove(modelPath)
+		fit := func(callbacks ...Callback) *History {
+			return NewTrainer(nnLayers, train, 30, 5,
+				WithTrainerValidationData(validation), WithTrainerCallbacks(callbacks...)).Fit()
+		}
+
+		Convey("When : 改善とみなす変化量を大きくしたModelCheckpointを利用する", func() {
+			mc := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointMinDelta(100))
+			recorder := &weightsRecorder{}
+			history := fit(recorder, mc)
+			Convey("Then : 1epoch目終了時点のNNが保存されていること", func() {
+				So(mc.GetError(), ShouldBeNil)
+				So(mc.GetBest(), ShouldEqual, history.Epochs[0].ValLoss)
+				saved, err := model.ReadNNLayers(modelPath)
+				So(err, ShouldBeNil)
+				w := saved.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+				So(mat.Equal(w, recorder.weights[0]), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 学習終了時に評価値が最良だったNNに戻すModelCheckpointを利用する", func() {
+			mc := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointMinDelta(100), WithModelCheckpointRestoreBest())
+			recorder := &weightsRecorder{}
+			fit(recorder, mc)
+			Convey("Then : 1epoch目終了時点のパラメーターに戻っていること", func() {
+				So(mc.GetError(), ShouldBeNil)
+				w := nnLayers.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+				So(mat.Equal(w, recorder.weights[0]), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : epoch毎に保存するModelCheckpointを利用する", func() {
+			mc := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointMinDelta(100), WithModelCheckpointSaveAll())
+			recorder := &weightsRecorder{}
+			fit(recorder, mc)
+			Convey("Then : 最後のepoch終了時点のNNが保存されていること", func() {
+				saved, err := model.ReadNNLayers(modelPath)
+				So(err, ShouldBeNil)
+				w := saved.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+				So(mat.Equal(w, recorder.weights[len(recorder.weights)-1]), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : epoch毎に保存し、学習終了時に評価値が最良だったNNに戻すModelCheckpointを利用する", func() {
+			mc := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointMinDelta(100),
+				WithModelCheckpointSaveAll(), WithModelCheckpointRestoreBest())
+			recorder := &weightsRecorder{}
+			fit(recorder, mc)
+			Convey("Then : ファイルは最後のepochで上書きされても、1epoch目終了時点のパラメーターに戻っていること", func() {
+				So(mc.GetError(), ShouldBeNil)
+				saved, err := model.ReadNNLayers(modelPath)
+				So(err, ShouldBeNil)
+				savedW := saved.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+				So(mat.Equal(savedW, recorder.weights[len(recorder.weights)-1]), ShouldBeTrue)
+				w := nnLayers.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+				So(mat.Equal(w, recorder.weights[0]), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 学習データでFitした前処理をあわせて保存するModelCheckpointを利用する", func() {
+			pipeline := transform.NewPipeline(transform.NewStandardScaler())
+			transform.FitDataset(pipeline, train)
+			mc := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointTransform(pipeline))
+			fit(mc)
+			Convey("Then : 保存したファイルから推論時に同じ前処理が復元できること", func() {
+				So(mc.GetError(), ShouldBeNil)
+				_, rePipeline, err := model.ReadNNLayersWithTransform(modelPath)
+				So(err, ShouldBeNil)
+				x := mat.NewDense(2, 2, []float64{1, -2, 0.5, 3})
+				So(len(rePipeline.GetTransformers()), ShouldEqual, 1)
+				So(mat.EqualApprox(rePipeline.Transform(x), pipeline.Transform(x), 1e-12), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : スケジューラーとEarlyStoppingより前にepoch毎に保存するModelCheckpointを指定する", func() {
+			mc := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointSaveAll())
+			scheduler := neuralNetwork.NewStepDecay(nnLayers.GetOptimizer(), 1, neuralNetwork.WithStepDecayGamma(0.5))
+			es := NewEarlyStopping(MonitorValLoss, 10)
+			tr := NewTrainer(nnLayers, train, 30, 3,
+				WithTrainerValidationData(validation), WithTrainerCallbacks(mc, NewSchedulerCallback(scheduler), es))
+			tr.Fit()
+			Convey("Then : 後に指定したコールバックの最後のepochの処理後の状態が保存されていること", func() {
+				So(mc.GetError(), ShouldBeNil)
+				_, state, err := model.ReadCheckpoint(modelPath)
+				So(err, ShouldBeNil)
+				So(state.CallbackStates, ShouldResemble, tr.GetState().CallbackStates)
+				So(state.CallbackStates["1.step"], ShouldEqual, 3)
+			})
+		})
+
+		Convey("When : 再開前の最良値を設定したModelCheckpointで、評価値が改善しないまま学習を終える", func() {
+			best := newClassifier()
+			So(model.WriteCheckpoint(modelPath, best, model.NNTrainingState{}), ShouldBeNil)
+			mc := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointRestoreBest())
+			tr := NewTrainer(nnLayers, train, 30, 1, WithTrainerValidationData(validation))
+			mc.OnTrainBegin(tr)
+			mc.SetStates(map[string]float64{"best": -1, "hasBest": 1})
+			mc.OnEpochEnd(tr, EpochLog{ValLoss: 1, HasValidation: true})
+			mc.OnTrainEnd(tr)
+			Convey("Then : 最良値は引き継がれ、保存したファイルのパラメーターに戻っていること", func() {
+				So(mc.GetError(), ShouldBeNil)
+				So(mc.GetBest(), ShouldEqual, -1)
+				w := nnLayers.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+				So(mat.Equal(w, best.GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 存在しないフォルダを保存先に指定する", func() {
+			mc := NewModelCheckpoint("not_exist/checkpoint.db", MonitorValLoss)
+			fit(mc)
+			Convey("Then : エラーが記録されること", func() {
+				So(mc.GetError(), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// weightsRecorder : epoch毎に1層目の重みのコピーを記録するCallback
+type weightsRecorder struct {
+	BaseCallback
+	weights []mat.Matrix
+}
+
+func (cb *weightsRecorder) OnEpochEnd(t *Trainer, log EpochLog) {
+	w := t.GetLayers().GetLayers()[0].(neuralNetwork.NeuralNetworkLayer).GetParams()["w"]
+	cb.weights = append(cb.weights, mat.DenseCopyOf(w))
+}
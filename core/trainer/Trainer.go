@@ -0,0 +1,329 @@
+package trainer
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/goMLLibrary/core/dataset"
+	"github.com/goMLLibrary/core/model"
+	"github.com/goMLLibrary/core/neuralNetwork"
+)
+
+// BatchLog : 1バッチ分の学習結果
+type BatchLog struct {
+	Epoch     int // 0から始まるepoch数
+	Batch     int // epoch内での0から始まるバッチ番号
+	Iteration int // 学習開始からの0から始まる通算のバッチ番号
+	Loss      float64
+	Accuracy  float64
+}
+
+// EpochLog : 1epoch分の学習・評価結果
+type EpochLog struct {
+	Epoch         int // 0から始まるepoch数
+	Loss          float64
+	Accuracy      float64
+	ValLoss       float64
+	ValAccuracy   float64
+	HasValidation bool // 検証データで評価したかどうか
+}
+
+const (
+	// MonitorLoss : 学習データの損失を表すキー
+	MonitorLoss = "loss"
+	// MonitorAccuracy : 学習データの正解率を表すキー
+	MonitorAccuracy = "accuracy"
+	// MonitorValLoss : 検証データの損失を表すキー
+	MonitorValLoss = "val_loss"
+	// MonitorValAccuracy : 検証データの正解率を表すキー
+	MonitorValAccuracy = "val_accuracy"
+)
+
+// Get : キー（MonitorLossなど）に対応する値を取得
+// 存在しないキー、または検証データが無い場合の検証データのキーではfalseを返す
+func (log EpochLog) Get(monitor string) (float64, bool) {
+	switch monitor {
+	case MonitorLoss:
+		return log.Loss, true
+	case MonitorAccuracy:
+		return log.Accuracy, true
+	case MonitorValLoss:
+		return log.ValLoss, log.HasValidation
+	case MonitorValAccuracy:
+		return log.ValAccuracy, log.HasValidation
+	}
+	return 0, false
+}
+
+// History : 学習全体の結果
+type History struct {
+	Epochs []EpochLog
+}
+
+// Trainer : NeuralNetworkLayersの学習ループ（ミニバッチ学習・検証データでの評価・コールバック呼び出し）を行う
+type Trainer struct {
+	layers        *neuralNetwork.NeuralNetworkLayers
+	loader        *dataset.DataLoader
+	validation    dataset.Dataset
+	batchSize     int
+	epochs        int
+	callbacks     []Callback
+	loaderOptions []dataset.DataLoaderOption
+	resumeState   *model.NNTrainingState
+
+	epoch          int // 次に学習する0から始まるepoch数
+	iteration      int // 次に学習する0から始まる通算のバッチ番号
+	stopTraining   bool
+	callbackStates map[string]float64 // 再開時に、学習開始時にコールバックへ設定する状態
+}
+
+// TrainerOption : Trainerのオプション
+type TrainerOption func(*Trainer)
+
+// NewTrainer : Trainerを取得
+// layers : 学習対象のNN, train : 学習データ, batchSize : バッチサイズ, epochs : 学習するepoch数
+// 学習データはdataset.DataLoaderでepoch毎に重複なくシャッフルしてミニバッチに分割する
+// 学習を再開する場合、epochsは中断前の分も含めたepoch数を指定する
+// 初期化時にオプション指定が可能
+func NewTrainer(layers *neuralNetwork.NeuralNetworkLayers, train dataset.Dataset, batchSize int, epochs int, options ...TrainerOption) *Trainer {
+	if batchSize <= 0 || epochs <= 0 {
+		panic("batchSize, epochsは1以上を指定してください")
+	}
+	t := Trainer{
+		layers:    layers,
+		batchSize: batchSize,
+		epochs:    epochs,
+		callbacks: make([]Callback, 0),
+	}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&t)
+	}
+	// 学習データ・検証データが空の場合は学習の途中ではなく、作成時にpanicとする
+	if train.Count() == 0 {
+		panic("学習データにデータがありません")
+	}
+	if t.validation != nil && t.validation.Count() == 0 {
+		panic("検証データにデータがありません")
+	}
+	t.loader = dataset.NewDataLoader(train, batchSize, t.loaderOptions...)
+	if t.resumeState != nil {
+		t.epoch = t.resumeState.Epoch
+		t.iteration = t.resumeState.Iteration
+		if t.resumeState.HasRandomState {
+			t.loader.SetRandomState(t.resumeState.RandomState)
+		}
+		if randomDataset, ok := dataset.FindRandomStateDataset(train); ok && t.resumeState.HasDatasetRandomState {
+			randomDataset.SetRandomState(t.resumeState.DatasetRandomState)
+		}
+		if scheduler := layers.GetScheduler(); scheduler != nil && t.resumeState.SchedulerStates != nil {
+			scheduler.SetStates(t.resumeState.SchedulerStates)
+		}
+		t.callbackStates = t.resumeState.CallbackStates
+	}
+	return &t
+}
+
+// WithTrainerValidationData : epoch毎に評価する検証データ指定のオプションを取得
+func WithTrainerValidationData(validation dataset.Dataset) TrainerOption {
+	return func(t *Trainer) {
+		t.validation = validation
+	}
+}
+
+// WithTrainerCallbacks : コールバック指定のオプションを取得（指定した順に呼び出す）
+func WithTrainerCallbacks(callbacks ...Callback) TrainerOption {
+	return func(t *Trainer) {
+		t.callbacks = append(t.callbacks, callbacks...)
+	}
+}
+
+// WithTrainerNoShuffle : epoch毎に学習データをシャッフルしないオプションを取得
+func WithTrainerNoShuffle() TrainerOption {
+	return func(t *Trainer) {
+		t.loaderOptions = append(t.loaderOptions, dataset.WithDataLoaderNoShuffle())
+	}
+}
+
+// WithTrainerDropLast : バッチサイズに満たない最後のバッチを学習に利用しないオプションを取得
+func WithTrainerDropLast() TrainerOption {
+	return func(t *Trainer) {
+		t.loaderOptions = append(t.loaderOptions, dataset.WithDataLoaderDropLast())
+	}
+}
+
+// WithTrainerPrefetch : 学習中に次のミニバッチを別のgoroutineで事前に作成するオプションを取得
+func WithTrainerPrefetch() TrainerOption {
+	return func(t *Trainer) {
+		t.loaderOptions = append(t.loaderOptions, dataset.WithDataLoaderPrefetch())
+	}
+}
+
+// WithTrainerRand : 学習データのシャッフルに利用する乱数生成器指定のオプションを取得
+func WithTrainerRand(rnd *rand.Rand) TrainerOption {
+	return func(t *Trainer) {
+		t.loaderOptions = append(t.loaderOptions, dataset.WithDataLoaderRand(rnd))
+	}
+}
+
+// WithTrainerResume : model.ReadCheckpointで取得した進捗状況から学習を再開するオプションを取得
+// 学習データのシャッフル・データ拡張に利用する乱数、NNに設定したスケジューラー、StatefulCallbackの状態も中断した時点の続きから再開する
+// スケジューラーとコールバックは中断前と同じ構成で作成し、コールバックは同じ順に指定すること
+func WithTrainerResume(state model.NNTrainingState) TrainerOption {
+	return func(t *Trainer) {
+		t.resumeState = &state
+	}
+}
+
+// Fit : 学習を行い、epoch毎の結果を返す
+// コールバックからStopTrainingが呼ばれた場合は、そのepochの終了時点で学習を終える
+// 再度呼び出した場合や、WithTrainerResumeを指定した場合は、前回終了したepochの続きから学習する
+func (t *Trainer) Fit() *History {
+	history := History{Epochs: make([]EpochLog, 0, t.epochs)}
+	t.stopTraining = false
+	t.layers.SetTrainingMode()
+	for _, cb := range t.callbacks {
+		cb.OnTrainBegin(t)
+	}
+	// 再開時のコールバックの状態は、OnTrainBeginで初期化された後に設定する
+	if t.callbackStates != nil {
+		t.setCallbackStates(t.callbackStates)
+		t.callbackStates = nil
+	}
+
+	for t.epoch < t.epochs && !t.stopTraining {
+		epoch := t.epoch
+		log := EpochLog{Epoch: epoch}
+		it := t.loader.Iterator()
+		for batch := 0; it.Next(); batch++ {
+			b := it.Batch()
+			loss, acc := t.layers.Forward(b.X, b.T)
+			t.layers.Backward()
+			t.layers.Update()
+
+			// epoch全体の結果はデータ数で重み付けした平均とする
+			log.Loss += loss * float64(len(b.Indexes))
+			log.Accuracy += acc * float64(len(b.Indexes))
+			batchLog := BatchLog{Epoch: epoch, Batch: batch, Iteration: t.iteration, Loss: loss, Accuracy: acc}
+			t.iteration++
+			for _, cb := range t.callbacks {
+				cb.OnBatchEnd(t, batchLog)
+			}
+		}
+		log.Loss /= float64(t.loader.SampleCount())
+		log.Accuracy /= float64(t.loader.SampleCount())
+
+		if t.validation != nil {
+			log.ValLoss, log.ValAccuracy = t.Evaluate(t.validation)
+			log.HasValidation = true
+		}
+		history.Epochs = append(history.Epochs, log)
+
+		// コールバックからGetStateで取得する進捗状況は、次のepochから再開する状態とする
+		t.epoch++
+		for _, cb := range t.callbacks {
+			cb.OnEpochEnd(t, log)
+		}
+		// 進捗状況の保存などは、コールバックの指定順に関わらず全てのコールバックのepoch終了時の処理の後に行う
+		for _, cb := range t.callbacks {
+			if postEpoch, ok := cb.(PostEpochCallback); ok {
+				postEpoch.OnPostEpochEnd(t, log)
+			}
+		}
+	}
+
+	for _, cb := range t.callbacks {
+		cb.OnTrainEnd(t)
+	}
+	return &history
+}
+
+// Evaluate : 推論時の挙動でデータセット全体の損失と正解率を算出する
+// メモリ使用量を抑えるため、バッチサイズ毎に分割して処理する
+// データが1件も無い場合は評価値が算出できないため、panicとする
+func (t *Trainer) Evaluate(ds dataset.Dataset) (loss float64, accuracy float64) {
+	if ds.Count() == 0 {
+		panic("評価するデータセットにデータがありません")
+	}
+	training := t.layers.IsTraining()
+	t.layers.SetEvaluationMode()
+	if training {
+		defer t.layers.SetTrainingMode()
+	}
+
+	it := dataset.NewDataLoader(ds, t.batchSize, dataset.WithDataLoaderNoShuffle()).Iterator()
+	for it.Next() {
+		b := it.Batch()
+		l, acc := t.layers.Forward(b.X, b.T)
+		loss += l * float64(len(b.Indexes))
+		accuracy += acc * float64(len(b.Indexes))
+	}
+	return loss / float64(ds.Count()), accuracy / float64(ds.Count())
+}
+
+// StopTraining : 現在のepochの終了時点で学習を終えるように指示する（コールバックから呼ぶ）
+func (t *Trainer) StopTraining() {
+	t.stopTraining = true
+}
+
+// GetState : 学習を再開するための進捗状況を取得（model.WriteCheckpointで保存する）
+// 学習データのシャッフル・データ拡張に利用する乱数、NNに設定したスケジューラー、StatefulCallbackの状態を含む
+// 全てのコールバックのepoch終了時の処理の後（PostEpochCallback.OnPostEpochEnd）に取得した場合に、中断した時点から同じ結果となるように再開できる
+func (t *Trainer) GetState() model.NNTrainingState {
+	state := model.NNTrainingState{Epoch: t.epoch, Iteration: t.iteration}
+	state.RandomState, state.HasRandomState = t.loader.GetRandomState()
+	if randomDataset, ok := dataset.FindRandomStateDataset(t.loader.GetDataset()); ok {
+		state.DatasetRandomState, state.HasDatasetRandomState = randomDataset.GetRandomState(), true
+	}
+	if scheduler := t.layers.GetScheduler(); scheduler != nil {
+		state.SchedulerStates = scheduler.GetStates()
+	}
+	state.CallbackStates = t.getCallbackStates()
+	return state
+}
+
+// getCallbackStates : StatefulCallbackの状態を"コールバックのインデックス.キー"をキーとして取得
+func (t *Trainer) getCallbackStates() map[string]float64 {
+	states := make(map[string]float64)
+	for i, cb := range t.callbacks {
+		if stateful, ok := cb.(StatefulCallback); ok {
+			for key, v := range stateful.GetStates() {
+				states[fmt.Sprintf("%d.%s", i, key)] = v
+			}
+		}
+	}
+	return states
+}
+
+// setCallbackStates : getCallbackStatesで取得した状態を、同じインデックスのStatefulCallbackに設定
+// 再開時にコールバックを省略した場合などで、対応するStatefulCallbackが無い状態は利用しない
+func (t *Trainer) setCallbackStates(states map[string]float64) {
+	grouped := make(map[int]map[string]float64)
+	for key, v := range states {
+		parts := strings.SplitN(key, ".", 2)
+		index, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 {
+			panic(fmt.Sprintf("コールバックの状態のキー%sが不正です", key))
+		}
+		if grouped[index] == nil {
+			grouped[index] = make(map[string]float64)
+		}
+		grouped[index][parts[1]] = v
+	}
+	for index, cbStates := range grouped {
+		if index >= len(t.callbacks) {
+			continue
+		}
+		if stateful, ok := t.callbacks[index].(StatefulCallback); ok {
+			stateful.SetStates(cbStates)
+		}
+	}
+}
+
+// GetLayers : 学習対象のNNを取得
+func (t *Trainer) GetLayers() *neuralNetwork.NeuralNetworkLayers {
+	return t.layers
+}
@@ -0,0 +1,245 @@
+package trainer
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/goMLLibrary/core/augment"
+	"github.com/goMLLibrary/core/dataset"
+	"github.com/goMLLibrary/core/model"
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTrainer(t *testing.T) {
+	Convey("Given : 3クラスに分かれる2次元のデータセットとNNが与えられた時", t, func() {
+		util.SetSeed(1)
+		train := newClusterDataSet(90)
+		validation := newClusterDataSet(30)
+		nnLayers := newClassifier()
+		recorder := &recordingCallback{}
+
+		Convey("When : バッチサイズ16, 5epochの学習を行う", func() {
+			tr := NewTrainer(nnLayers, train, 16, 5,
+				WithTrainerValidationData(validation), WithTrainerCallbacks(recorder))
+			history := tr.Fit()
+			Convey("Then : epoch毎の結果が記録され、損失が減少すること", func() {
+				So(len(history.Epochs), ShouldEqual, 5)
+				So(history.Epochs[4].Loss, ShouldBeLessThan, history.Epochs[0].Loss)
+				So(history.Epochs[4].HasValidation, ShouldBeTrue)
+				So(history.Epochs[4].ValAccuracy, ShouldBeGreaterThan, 0.9)
+			})
+			Convey("Then : コールバックが各タイミングで呼ばれること", func() {
+				// 90件を16件毎に分割するため、1epochあたり6バッチ
+				So(recorder.events[0], ShouldEqual, "begin")
+				So(recorder.batches, ShouldEqual, 5*6)
+				So(recorder.lastIteration, ShouldEqual, 5*6-1)
+				So(recorder.epochs, ShouldEqual, 5)
+				So(recorder.events[len(recorder.events)-1], ShouldEqual, "end")
+			})
+			Convey("Then : 学習後は学習時の挙動のままであること", func() {
+				So(nnLayers.IsTraining(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : バッチサイズに満たない最後のバッチを利用せず、事前作成を行って学習する", func() {
+			NewTrainer(nnLayers, train, 16, 2, WithTrainerDropLast(), WithTrainerPrefetch(), WithTrainerCallbacks(recorder)).Fit()
+			Convey("Then : 1epochあたり5バッチで学習すること", func() {
+				So(recorder.batches, ShouldEqual, 2*5)
+			})
+		})
+
+		Convey("When : 2epoch目の終了時に学習の停止を指示する", func() {
+			recorder.stopAtEpoch = 1
+			history := NewTrainer(nnLayers, train, 30, 10, WithTrainerCallbacks(recorder)).Fit()
+			Convey("Then : 2epochで学習が終了すること", func() {
+				So(len(history.Epochs), ShouldEqual, 2)
+				So(history.Epochs[1].HasValidation, ShouldBeFalse)
+				So(recorder.events[len(recorder.events)-1], ShouldEqual, "end")
+			})
+		})
+
+		Convey("When : 推論時の挙動に切り替えて評価を行う", func() {
+			nnLayers.SetEvaluationMode()
+			tr := NewTrainer(nnLayers, train, 7, 1)
+			loss, acc := tr.Evaluate(validation)
+			Convey("Then : 全データを一度に評価した結果と一致し、推論時の挙動のままであること", func() {
+				x, label := validation.GetBatch(sequence(30))
+				expectedLoss, expectedAcc := nnLayers.Forward(x, label)
+				So(loss, ShouldAlmostEqual, expectedLoss, 1e-10)
+				So(acc, ShouldAlmostEqual, expectedAcc, 1e-10)
+				So(nnLayers.IsTraining(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When : データが1件も無いデータセットを学習・評価・検証データとする", func() {
+			empty := dataset.NewSubset(validation, nil)
+			Convey("Then : NaNを返さずにpanicが発生すること", func() {
+				So(func() { NewTrainer(nnLayers, train, 7, 1).Evaluate(empty) }, ShouldPanic)
+				So(func() { NewTrainer(nnLayers, train, 7, 1, WithTrainerValidationData(empty)) }, ShouldPanic)
+				So(func() { NewTrainer(nnLayers, empty, 7, 1) }, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given : Dropoutを含むNNを4epoch学習する時", t, func() {
+		util.SetSeed(5)
+		train := newClusterDataSet(60)
+		validation := newClusterDataSet(30)
+		modelPath := "trainer_checkpoint.db"
+		defer os.Remove(modelPath)
+		newLayers := func() *neuralNetwork.NeuralNetworkLayers {
+			nnLayers := newClassifier()
+			nnLayers.Add(neuralNetwork.NewInvertedDropout(0.2))
+			return nnLayers
+		}
+
+		util.SetSeed(6)
+		expected := NewTrainer(newLayers(), train, 16, 4, WithTrainerValidationData(validation)).Fit()
+
+		Convey("When : 2epochで中断してファイルに保存し、ファイルから学習を再開する", func() {
+			util.SetSeed(6)
+			checkpoint := NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointSaveAll())
+			first := NewTrainer(newLayers(), train, 16, 2, WithTrainerValidationData(validation), WithTrainerCallbacks(checkpoint)).Fit()
+			So(checkpoint.GetError(), ShouldBeNil)
+
+			nnLayers, state, err := model.ReadCheckpoint(modelPath)
+			So(err, ShouldBeNil)
+			recorder := &recordingCallback{}
+			tr := NewTrainer(nnLayers, train, 16, 4, WithTrainerValidationData(validation),
+				WithTrainerCallbacks(recorder), WithTrainerResume(state))
+			second := tr.Fit()
+			Convey("Then : 3epoch目から学習が再開されること", func() {
+				So(state.Epoch, ShouldEqual, 2)
+				So(len(second.Epochs), ShouldEqual, 2)
+				So(second.Epochs[0].Epoch, ShouldEqual, 2)
+				// 60件を16件毎に分割するため、1epochあたり4バッチ
+				So(recorder.lastIteration, ShouldEqual, 4*4-1)
+				So(tr.GetState().Epoch, ShouldEqual, 4)
+			})
+			Convey("Then : 中断せずに学習した場合と同じ結果になること", func() {
+				So(append(first.Epochs, second.Epochs...), ShouldResemble, expected.Epochs)
+			})
+		})
+	})
+}
+
+func TestTrainerResumeStates(t *testing.T) {
+	Convey("Given : データ拡張を行う学習データ、スケジューラー、EarlyStoppingを利用して5epoch学習する時", t, func() {
+		util.SetSeed(7)
+		train := newClusterDataSet(60)
+		validation := newClusterDataSet(30)
+		modelPath := "trainer_resume_states.db"
+		defer os.Remove(modelPath)
+		// 2次元の入力を1チャネル・1*2の画像とみなして、ランダムに左右反転する
+		newTrainSet := func() dataset.Dataset {
+			augmented := augment.NewDataset(train, neuralNetwork.NewShape(1, 1, 2), augment.NewRandomHorizontalFlip(0.5), augment.WithDatasetSeed(3))
+			return dataset.NewSubset(augmented, sequence(60))
+		}
+
+		// epoch毎に進めるスケジューラーはコールバックで、iteration毎に進めるスケジューラーはNNに設定する
+		for _, perIteration := range []bool{false, true} {
+			setup := func(nnLayers *neuralNetwork.NeuralNetworkLayers, callbacks ...Callback) ([]Callback, *EarlyStopping) {
+				es := NewEarlyStopping(MonitorValLoss, 10)
+				if perIteration {
+					nnLayers.SetScheduler(neuralNetwork.NewStepDecay(nnLayers.GetOptimizer(), 5, neuralNetwork.WithStepDecayGamma(0.5)))
+					return append([]Callback{es}, callbacks...), es
+				}
+				scheduler := neuralNetwork.NewStepDecay(nnLayers.GetOptimizer(), 1, neuralNetwork.WithStepDecayGamma(0.5))
+				return append([]Callback{NewSchedulerCallback(scheduler), es}, callbacks...), es
+			}
+
+			util.SetSeed(8)
+			expectedLayers := newClassifier()
+			callbacks, expectedES := setup(expectedLayers)
+			expected := NewTrainer(expectedLayers, newTrainSet(), 16, 5,
+				WithTrainerValidationData(validation), WithTrainerCallbacks(callbacks...)).Fit()
+
+			Convey(fmt.Sprintf("When : 2epochで中断してファイルに保存し、スケジューラーを作り直して再開する（iteration毎 : %v）", perIteration), func() {
+				util.SetSeed(8)
+				firstLayers := newClassifier()
+				callbacks, _ := setup(firstLayers, NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointSaveAll()))
+				first := NewTrainer(firstLayers, newTrainSet(), 16, 2,
+					WithTrainerValidationData(validation), WithTrainerCallbacks(callbacks...)).Fit()
+
+				nnLayers, state, err := model.ReadCheckpoint(modelPath)
+				So(err, ShouldBeNil)
+				callbacks, es := setup(nnLayers, NewModelCheckpoint(modelPath, MonitorValLoss, WithModelCheckpointSaveAll()))
+				second := NewTrainer(nnLayers, newTrainSet(), 16, 5, WithTrainerValidationData(validation),
+					WithTrainerCallbacks(callbacks...), WithTrainerResume(state)).Fit()
+				Convey("Then : 学習率・データ拡張・EarlyStoppingの状態が引き継がれ、中断せずに学習した場合と同じ結果になること", func() {
+					So(state.HasDatasetRandomState, ShouldBeTrue)
+					So(append(first.Epochs, second.Epochs...), ShouldResemble, expected.Epochs)
+					So(nnLayers.GetOptimizer().GetLearningRate(), ShouldEqual, expectedLayers.GetOptimizer().GetLearningRate())
+					So(es.GetStates(), ShouldResemble, expectedES.GetStates())
+				})
+			})
+		}
+	})
+}
+
+// newClusterDataSet : (2, 0), (-2, 1), (0, -2)付近に分布する3クラスのデータセットを作成
+func newClusterDataSet(count int) *dataset.MatrixDataset {
+	centers := [][]float64{{2, 0}, {-2, 1}, {0, -2}}
+	noise := util.NormRandomArray(0.3, count*2)
+	x := mat.NewDense(count, 2, nil)
+	label := mat.NewDense(count, 3, nil)
+	for i := 0; i < count; i++ {
+		c := i % 3
+		x.Set(i, 0, centers[c][0]+noise[i*2])
+		x.Set(i, 1, centers[c][1]+noise[i*2+1])
+		label.Set(i, c, 1)
+	}
+	return dataset.NewMatrixDataset(x, label)
+}
+
+func newClassifier() *neuralNetwork.NeuralNetworkLayers {
+	nnLayers := neuralNetwork.NewDefaultNeuralNetworkLayers()
+	nnLayers.Add(neuralNetwork.NewAffine(2, 8, neuralNetwork.WithAffineWeightInitializer(neuralNetwork.NewHeNormalInitializer())))
+	nnLayers.Add(neuralNetwork.NewRelu())
+	nnLayers.Add(neuralNetwork.NewAffine(8, 3, neuralNetwork.WithAffineWeightInitializer(neuralNetwork.NewXavierNormalInitializer())))
+	nnLayers.SetOptimizer(neuralNetwork.NewAdam(neuralNetwork.WithAdamLearningRate(0.05)))
+	return nnLayers
+}
+
+func sequence(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+// recordingCallback : 呼び出されたタイミングを記録するCallback
+type recordingCallback struct {
+	BaseCallback
+	events        []string
+	batches       int
+	epochs        int
+	lastIteration int
+	stopAtEpoch   int
+}
+
+func (cb *recordingCallback) OnTrainBegin(t *Trainer) {
+	cb.events = append(cb.events, "begin")
+}
+
+func (cb *recordingCallback) OnBatchEnd(t *Trainer, log BatchLog) {
+	cb.batches++
+	cb.lastIteration = log.Iteration
+}
+
+func (cb *recordingCallback) OnEpochEnd(t *Trainer, log EpochLog) {
+	cb.epochs++
+	cb.events = append(cb.events, "epoch")
+	if cb.stopAtEpoch > 0 && log.Epoch == cb.stopAtEpoch {
+		t.StopTraining()
+	}
+}
+
+func (cb *recordingCallback) OnTrainEnd(t *Trainer) {
+	cb.events = append(cb.events, "end")
+}
@@ -0,0 +1,53 @@
+package transform
+
+import (
+	"github.com/goMLLibrary/core/dataset"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Dataset : 元のデータセットから取得した入力データに、Transformerで変換を行うデータセット
+// 正解データはそのまま利用する
+type Dataset struct {
+	ds          dataset.Dataset
+	transformer Transformer
+}
+
+// NewDataset : 入力データをtransformerで変換するデータセットを取得
+func NewDataset(ds dataset.Dataset, transformer Transformer) *Dataset {
+	return &Dataset{ds: ds, transformer: transformer}
+}
+
+// FitDataset : データセットの全ての入力データでtransformerをFitする
+func FitDataset(transformer Transformer, ds dataset.Dataset) {
+	indexes := make([]int, ds.Count())
+	for i := range indexes {
+		indexes[i] = i
+	}
+	x, _ := dataset.GetBatch(ds, indexes)
+	transformer.Fit(x)
+}
+
+func (d *Dataset) Count() int {
+	return d.ds.Count()
+}
+
+func (d *Dataset) GetSample(index int) (x []float64, t []float64) {
+	sx, st := d.ds.GetSample(index)
+	tx := d.transformer.Transform(mat.NewDense(1, len(sx), sx))
+	return mat.Row(nil, 0, tx), st
+}
+
+func (d *Dataset) GetBatch(indexes []int) (x mat.Matrix, t mat.Matrix) {
+	bx, bt := dataset.GetBatch(d.ds, indexes)
+	return d.transformer.Transform(bx), bt
+}
+
+// GetDataset : 変換前の元のデータセットを取得
+func (d *Dataset) GetDataset() dataset.Dataset {
+	return d.ds
+}
+
+// GetTransformer : 入力データの変換に利用するTransformerを取得
+func (d *Dataset) GetTransformer() Transformer {
+	return d.transformer
+}
@@ -0,0 +1,37 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/goMLLibrary/core/dataset"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDataset(t *testing.T) {
+	Convey("Given : 0〜255の値を持つデータセットが与えられた時", t, func() {
+		x := mat.NewDense(3, 2, []float64{0, 255, 51, 0, 255, 102})
+		label := mat.NewDense(3, 1, []float64{1, 2, 3})
+		ds := dataset.NewMatrixDataset(x, label)
+		Convey("When : データセット全体でMinMaxScalerをFitし、変換するデータセットを作成する", func() {
+			s := NewMinMaxScaler()
+			FitDataset(s, ds)
+			transformed := NewDataset(ds, s)
+			Convey("Then : 入力データのみが変換されること", func() {
+				So(transformed.Count(), ShouldEqual, 3)
+				So(transformed.GetTransformer(), ShouldEqual, s)
+				sx, st := transformed.GetSample(1)
+				So(sx, ShouldResemble, []float64{0.2, 0})
+				So(st, ShouldResemble, []float64{2})
+				bx, bt := transformed.GetBatch([]int{2, 0})
+				So(mat.EqualApprox(bx, mat.NewDense(2, 2, []float64{1, 0.4, 0, 1}), 1e-12), ShouldBeTrue)
+				So(mat.Equal(bt, mat.NewDense(2, 1, []float64{3, 1})), ShouldBeTrue)
+			})
+			Convey("Then : DataLoaderで変換したミニバッチが取得できること", func() {
+				it := dataset.NewDataLoader(transformed, 3, dataset.WithDataLoaderNoShuffle()).Iterator()
+				So(it.Next(), ShouldBeTrue)
+				So(mat.Max(it.Batch().X), ShouldEqual, 1)
+			})
+		})
+	})
+}
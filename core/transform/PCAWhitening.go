@@ -0,0 +1,157 @@
+package transform
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// DefaultPCAWhiteningEpsilon : PCA白色化で固有値に加える値のデフォルト値
+const DefaultPCAWhiteningEpsilon = 1e-5
+
+// PCAWhitening : 学習データの共分散行列の固有ベクトルへ射影し、各成分の分散が1となるように白色化する
+// ZCA白色化を指定した場合は、白色化した後に元の特徴量の空間へ戻す
+type PCAWhitening struct {
+	epsilon    float64
+	components int // 0の場合は全ての成分を利用する
+	zca        bool
+
+	mean *mat.Dense // (1, 特徴量の数)
+	w    *mat.Dense // (特徴量の数, 変換後の特徴量の数)
+}
+
+// PCAWhiteningOption : PCAWhiteningのオプション
+type PCAWhiteningOption func(*PCAWhitening)
+
+// NewPCAWhitening : PCAWhiteningを取得
+// 初期化時にオプション指定が可能
+func NewPCAWhitening(options ...PCAWhiteningOption) *PCAWhitening {
+	p := PCAWhitening{epsilon: DefaultPCAWhiteningEpsilon}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&p)
+	}
+	if p.zca && p.components > 0 {
+		panic("ZCA白色化では成分数を指定できません")
+	}
+	return &p
+}
+
+// WithPCAWhiteningEpsilon : 0除算を防ぐために固有値に加える値指定のオプションを取得
+func WithPCAWhiteningEpsilon(epsilon float64) PCAWhiteningOption {
+	return func(p *PCAWhitening) {
+		p.epsilon = epsilon
+	}
+}
+
+// WithPCAWhiteningComponents : 固有値の大きい順に利用する成分数指定のオプションを取得（次元削減）
+func WithPCAWhiteningComponents(components int) PCAWhiteningOption {
+	return func(p *PCAWhitening) {
+		if components <= 0 {
+			panic("成分数は1以上を指定してください")
+		}
+		p.components = components
+	}
+}
+
+// WithPCAWhiteningZCA : ZCA白色化を行うオプションを取得
+func WithPCAWhiteningZCA() PCAWhiteningOption {
+	return func(p *PCAWhitening) {
+		p.zca = true
+	}
+}
+
+func (p *PCAWhitening) Fit(x mat.Matrix) {
+	r, c := x.Dims()
+	if p.components > c {
+		panic("成分数は特徴量の数以下を指定してください")
+	}
+
+	// 平均を引いたデータから共分散行列を算出する
+	p.mean = mat.NewDense(1, c, nil)
+	for j := 0; j < c; j++ {
+		p.mean.Set(0, j, mat.Sum(mat.NewVecDense(r, mat.Col(nil, j, x)))/float64(r))
+	}
+	centered := mat.NewDense(r, c, nil)
+	centered.Apply(func(i, j int, v float64) float64 {
+		return v - p.mean.At(0, j)
+	}, x)
+	cov := mat.NewSymDense(c, nil)
+	cov.SymOuterK(1/float64(r), centered.T())
+
+	// 固有値は昇順に取得されるため、大きい順に並べ替えて射影行列を作成する
+	var eig mat.EigenSym
+	if !eig.Factorize(cov, true) {
+		panic("共分散行列の固有値分解に失敗しました")
+	}
+	values := eig.Values(nil)
+	var vectors mat.Dense
+	vectors.EigenvectorsSym(&eig)
+
+	components := p.components
+	if components == 0 {
+		components = c
+	}
+	w := mat.NewDense(c, components, nil)
+	for k := 0; k < components; k++ {
+		src := c - 1 - k
+		scale := 1 / math.Sqrt(math.Max(values[src], 0)+p.epsilon)
+		for j := 0; j < c; j++ {
+			w.Set(j, k, vectors.At(j, src)*scale)
+		}
+	}
+	if p.zca {
+		// W = U * diag(1/sqrt(λ+ε)) * U^T
+		u := mat.NewDense(c, components, nil)
+		for k := 0; k < components; k++ {
+			u.SetCol(k, mat.Col(nil, c-1-k, &vectors))
+		}
+		var zca mat.Dense
+		zca.Mul(w, u.T())
+		w = &zca
+	}
+	p.w = w
+}
+
+func (p *PCAWhitening) Transform(x mat.Matrix) mat.Matrix {
+	checkFitted(p)
+	r, c := x.Dims()
+	checkColumns(x, p.mean.RawMatrix().Cols)
+	centered := mat.NewDense(r, c, nil)
+	centered.Apply(func(i, j int, v float64) float64 {
+		return v - p.mean.At(0, j)
+	}, x)
+	var dst mat.Dense
+	dst.Mul(centered, p.w)
+	return &dst
+}
+
+func (p *PCAWhitening) IsFitted() bool {
+	return p.w != nil
+}
+
+func (p *PCAWhitening) GetStates() map[string]mat.Matrix {
+	checkFitted(p)
+	return map[string]mat.Matrix{"mean": p.mean, "w": p.w}
+}
+
+func (p *PCAWhitening) SetStates(states map[string]mat.Matrix) {
+	p.mean = getState(states, "mean")
+	p.w = getState(states, "w")
+}
+
+// GetEpsilon : 固有値に加える値を取得
+func (p *PCAWhitening) GetEpsilon() float64 {
+	return p.epsilon
+}
+
+// GetComponents : 利用する成分数を取得（全ての成分を利用する場合は0）
+func (p *PCAWhitening) GetComponents() int {
+	return p.components
+}
+
+// IsZCA : ZCA白色化を行うかどうか
+func (p *PCAWhitening) IsZCA() bool {
+	return p.zca
+}
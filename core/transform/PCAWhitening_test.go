@@ -0,0 +1,80 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPCAWhitening(t *testing.T) {
+	Convey("Given : 相関のある3次元の学習データが与えられた時", t, func() {
+		util.SetSeed(3)
+		rnd := util.NewRand()
+		n := 200
+		x := mat.NewDense(n, 3, nil)
+		for i := 0; i < n; i++ {
+			a, b, c := rnd.NormFloat64()*3, rnd.NormFloat64(), rnd.NormFloat64()*0.5
+			x.SetRow(i, []float64{a + 10, a + b, 0.5*b - a + c})
+		}
+		Convey("When : PCA白色化する", func() {
+			p := NewPCAWhitening(WithPCAWhiteningEpsilon(0))
+			p.Fit(x)
+			y := p.Transform(x)
+			Convey("Then : 変換後の共分散行列が単位行列となること", func() {
+				So(mat.EqualApprox(covariance(y), identity(3), 1e-6), ShouldBeTrue)
+			})
+		})
+		Convey("When : 成分数を2としてPCA白色化する", func() {
+			p := NewPCAWhitening(WithPCAWhiteningComponents(2))
+			p.Fit(x)
+			y := p.Transform(x)
+			Convey("Then : 2次元に削減され、共分散行列がほぼ単位行列となること", func() {
+				_, c := y.Dims()
+				So(c, ShouldEqual, 2)
+				So(mat.EqualApprox(covariance(y), identity(2), 1e-3), ShouldBeTrue)
+			})
+		})
+		Convey("When : ZCA白色化する", func() {
+			p := NewPCAWhitening(WithPCAWhiteningZCA(), WithPCAWhiteningEpsilon(0))
+			p.Fit(x)
+			y := p.Transform(x)
+			Convey("Then : 変換後の共分散行列が単位行列となり、変換行列が対称行列となること", func() {
+				So(mat.EqualApprox(covariance(y), identity(3), 1e-6), ShouldBeTrue)
+				w := p.GetStates()["w"]
+				So(mat.EqualApprox(w, w.T(), 1e-9), ShouldBeTrue)
+			})
+		})
+		Convey("Then : 不正な指定の場合はpanicが発生すること", func() {
+			So(func() { NewPCAWhitening(WithPCAWhiteningComponents(0)) }, ShouldPanic)
+			So(func() { NewPCAWhitening(WithPCAWhiteningComponents(2), WithPCAWhiteningZCA()) }, ShouldPanic)
+			So(func() { NewPCAWhitening(WithPCAWhiteningComponents(4)).Fit(x) }, ShouldPanic)
+		})
+	})
+}
+
+// covariance : 列毎の共分散行列を取得
+func covariance(x mat.Matrix) *mat.Dense {
+	r, c := x.Dims()
+	centered := mat.DenseCopyOf(x)
+	for j := 0; j < c; j++ {
+		mean := mat.Sum(mat.NewVecDense(r, mat.Col(nil, j, x))) / float64(r)
+		for i := 0; i < r; i++ {
+			centered.Set(i, j, centered.At(i, j)-mean)
+		}
+	}
+	var cov mat.Dense
+	cov.Mul(centered.T(), centered)
+	cov.Scale(1/float64(r), &cov)
+	return &cov
+}
+
+// identity : 単位行列を取得
+func identity(n int) *mat.Dense {
+	m := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
@@ -0,0 +1,240 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	"gonum.org/v1/gonum/mat"
+)
+
+// MinMaxScaler : 特徴量毎に、学習データの最小値・最大値が指定した範囲（デフォルトは0〜1）となるように線形変換する
+// 学習データで値が一定の特徴量は、範囲の最小値に変換する
+type MinMaxScaler struct {
+	rangeMin float64
+	rangeMax float64
+	dataMin  *mat.Dense // (1, 特徴量の数)
+	dataMax  *mat.Dense // (1, 特徴量の数)
+}
+
+// MinMaxScalerOption : MinMaxScalerのオプション
+type MinMaxScalerOption func(*MinMaxScaler)
+
+// NewMinMaxScaler : MinMaxScalerを取得
+// 初期化時にオプション指定が可能
+func NewMinMaxScaler(options ...MinMaxScalerOption) *MinMaxScaler {
+	s := MinMaxScaler{rangeMin: 0, rangeMax: 1}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&s)
+	}
+	if s.rangeMin >= s.rangeMax {
+		panic("変換後の範囲の最小値は最大値より小さい値を指定してください")
+	}
+	return &s
+}
+
+// WithMinMaxScalerRange : 変換後の範囲指定のオプションを取得
+func WithMinMaxScalerRange(min float64, max float64) MinMaxScalerOption {
+	return func(s *MinMaxScaler) {
+		s.rangeMin = min
+		s.rangeMax = max
+	}
+}
+
+func (s *MinMaxScaler) Fit(x mat.Matrix) {
+	r, c := x.Dims()
+	s.dataMin = mat.NewDense(1, c, nil)
+	s.dataMax = mat.NewDense(1, c, nil)
+	for j := 0; j < c; j++ {
+		min, max := math.Inf(1), math.Inf(-1)
+		for i := 0; i < r; i++ {
+			min = math.Min(min, x.At(i, j))
+			max = math.Max(max, x.At(i, j))
+		}
+		s.dataMin.Set(0, j, min)
+		s.dataMax.Set(0, j, max)
+	}
+}
+
+func (s *MinMaxScaler) Transform(x mat.Matrix) mat.Matrix {
+	checkFitted(s)
+	r, c := x.Dims()
+	checkColumns(x, s.dataMin.RawMatrix().Cols)
+	dst := mat.NewDense(r, c, nil)
+	dst.Apply(func(i, j int, v float64) float64 {
+		min, max := s.dataMin.At(0, j), s.dataMax.At(0, j)
+		if max == min {
+			return s.rangeMin
+		}
+		return (v-min)/(max-min)*(s.rangeMax-s.rangeMin) + s.rangeMin
+	}, x)
+	return dst
+}
+
+func (s *MinMaxScaler) IsFitted() bool {
+	return s.dataMin != nil
+}
+
+func (s *MinMaxScaler) GetStates() map[string]mat.Matrix {
+	checkFitted(s)
+	return map[string]mat.Matrix{"min": s.dataMin, "max": s.dataMax}
+}
+
+func (s *MinMaxScaler) SetStates(states map[string]mat.Matrix) {
+	s.dataMin = getState(states, "min")
+	s.dataMax = getState(states, "max")
+}
+
+// GetRange : 変換後の範囲を取得
+func (s *MinMaxScaler) GetRange() (min float64, max float64) {
+	return s.rangeMin, s.rangeMax
+}
+
+// StandardScaler : 特徴量毎に、学習データの平均が0・標準偏差が1となるように標準化する
+// 学習データで値が一定の特徴量は、平均を引くのみとする
+type StandardScaler struct {
+	mean *mat.Dense // (1, 特徴量の数)
+	std  *mat.Dense // (1, 特徴量の数)
+}
+
+// NewStandardScaler : StandardScalerを取得
+func NewStandardScaler() *StandardScaler {
+	return &StandardScaler{}
+}
+
+func (s *StandardScaler) Fit(x mat.Matrix) {
+	_, c := x.Dims()
+	s.mean = mat.NewDense(1, c, nil)
+	s.std = mat.NewDense(1, c, nil)
+	for j := 0; j < c; j++ {
+		mean, std := util.MeanStd(mat.Col(nil, j, x))
+		s.mean.Set(0, j, mean)
+		s.std.Set(0, j, std)
+	}
+}
+
+func (s *StandardScaler) Transform(x mat.Matrix) mat.Matrix {
+	checkFitted(s)
+	r, c := x.Dims()
+	checkColumns(x, s.mean.RawMatrix().Cols)
+	dst := mat.NewDense(r, c, nil)
+	dst.Apply(func(i, j int, v float64) float64 {
+		return standardize(v, s.mean.At(0, j), s.std.At(0, j))
+	}, x)
+	return dst
+}
+
+func (s *StandardScaler) IsFitted() bool {
+	return s.mean != nil
+}
+
+func (s *StandardScaler) GetStates() map[string]mat.Matrix {
+	checkFitted(s)
+	return map[string]mat.Matrix{"mean": s.mean, "std": s.std}
+}
+
+func (s *StandardScaler) SetStates(states map[string]mat.Matrix) {
+	s.mean = getState(states, "mean")
+	s.std = getState(states, "std")
+}
+
+// ChannelNormalizer : 画像のチャネル毎に、学習データの平均が0・標準偏差が1となるように標準化する
+// 入力データの各行には、チャネル→高さ→幅の順に画素値が並んでいるものとする
+type ChannelNormalizer struct {
+	shape neuralNetwork.Shape
+	mean  *mat.Dense // (1, チャネル数)
+	std   *mat.Dense // (1, チャネル数)
+}
+
+// ChannelNormalizerOption : ChannelNormalizerのオプション
+type ChannelNormalizerOption func(*ChannelNormalizer)
+
+// NewChannelNormalizer : ChannelNormalizerを取得
+// shape : 1枚の画像の形状（チャネル数・高さ・幅）
+// 初期化時にオプション指定が可能
+func NewChannelNormalizer(shape neuralNetwork.Shape, options ...ChannelNormalizerOption) *ChannelNormalizer {
+	if shape.IsEmpty() {
+		panic("画像の形状を指定してください")
+	}
+	n := ChannelNormalizer{shape: shape}
+
+	// オプションが設定されていれば利用
+	for _, opt := range options {
+		opt(&n)
+	}
+	return &n
+}
+
+// WithChannelNormalizerStats : 学習データでFitせず、指定したチャネル毎の平均・標準偏差を利用するオプションを取得
+func WithChannelNormalizerStats(mean []float64, std []float64) ChannelNormalizerOption {
+	return func(n *ChannelNormalizer) {
+		if len(mean) != n.shape.Channel || len(std) != n.shape.Channel {
+			panic(fmt.Sprintf("平均・標準偏差の要素数はチャネル数%dを指定してください", n.shape.Channel))
+		}
+		n.mean = mat.NewDense(1, len(mean), append([]float64{}, mean...))
+		n.std = mat.NewDense(1, len(std), append([]float64{}, std...))
+	}
+}
+
+func (n *ChannelNormalizer) Fit(x mat.Matrix) {
+	checkColumns(x, n.shape.Size())
+	r, _ := x.Dims()
+	area := n.shape.Height * n.shape.Width
+	n.mean = mat.NewDense(1, n.shape.Channel, nil)
+	n.std = mat.NewDense(1, n.shape.Channel, nil)
+	values := make([]float64, 0, r*area)
+	for ch := 0; ch < n.shape.Channel; ch++ {
+		values = values[:0]
+		for i := 0; i < r; i++ {
+			for j := ch * area; j < (ch+1)*area; j++ {
+				values = append(values, x.At(i, j))
+			}
+		}
+		mean, std := util.MeanStd(values)
+		n.mean.Set(0, ch, mean)
+		n.std.Set(0, ch, std)
+	}
+}
+
+func (n *ChannelNormalizer) Transform(x mat.Matrix) mat.Matrix {
+	checkFitted(n)
+	checkColumns(x, n.shape.Size())
+	r, c := x.Dims()
+	area := n.shape.Height * n.shape.Width
+	dst := mat.NewDense(r, c, nil)
+	dst.Apply(func(i, j int, v float64) float64 {
+		ch := j / area
+		return standardize(v, n.mean.At(0, ch), n.std.At(0, ch))
+	}, x)
+	return dst
+}
+
+func (n *ChannelNormalizer) IsFitted() bool {
+	return n.mean != nil
+}
+
+func (n *ChannelNormalizer) GetStates() map[string]mat.Matrix {
+	checkFitted(n)
+	return map[string]mat.Matrix{"mean": n.mean, "std": n.std}
+}
+
+func (n *ChannelNormalizer) SetStates(states map[string]mat.Matrix) {
+	n.mean = getState(states, "mean")
+	n.std = getState(states, "std")
+}
+
+// GetShape : 1枚の画像の形状を取得
+func (n *ChannelNormalizer) GetShape() neuralNetwork.Shape {
+	return n.shape
+}
+
+// standardize : 平均を引いて標準偏差で割る（標準偏差が0の場合は平均を引くのみ）
+func standardize(v float64, mean float64, std float64) float64 {
+	if std == 0 {
+		return v - mean
+	}
+	return (v - mean) / std
+}
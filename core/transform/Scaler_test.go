@@ -0,0 +1,99 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/util"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMinMaxScaler(t *testing.T) {
+	Convey("Given : 3列目の値が一定の学習データが与えられた時", t, func() {
+		x := mat.NewDense(3, 3, []float64{0, 255, 7, 51, 0, 7, 255, 102, 7})
+		Convey("When : 0〜1の範囲でFitして変換する", func() {
+			s := NewMinMaxScaler()
+			s.Fit(x)
+			y := s.Transform(x)
+			Convey("Then : 各列の最小値が0, 最大値が1となり、一定の列は0となること", func() {
+				So(mat.EqualApprox(y, mat.NewDense(3, 3, []float64{0, 1, 0, 0.2, 0, 0, 1, 0.4, 0}), 1e-12), ShouldBeTrue)
+			})
+			Convey("Then : 学習データの範囲外の値はそのまま線形変換されること", func() {
+				z := s.Transform(mat.NewDense(1, 3, []float64{510, -255, 8}))
+				So(mat.EqualApprox(z, mat.NewDense(1, 3, []float64{2, -1, 0}), 1e-12), ShouldBeTrue)
+			})
+			Convey("Then : 列数が異なるデータの変換はpanicが発生すること", func() {
+				So(func() { s.Transform(mat.NewDense(1, 2, nil)) }, ShouldPanic)
+			})
+		})
+		Convey("When : -1〜1の範囲でFitして変換する", func() {
+			s := NewMinMaxScaler(WithMinMaxScalerRange(-1, 1))
+			s.Fit(x)
+			y := s.Transform(x)
+			Convey("Then : 各列の最小値が-1, 最大値が1となること", func() {
+				So(y.At(0, 0), ShouldEqual, -1)
+				So(y.At(2, 0), ShouldEqual, 1)
+				So(y.At(0, 2), ShouldEqual, -1)
+			})
+		})
+		Convey("Then : 不正な範囲の場合はpanicが発生すること", func() {
+			So(func() { NewMinMaxScaler(WithMinMaxScalerRange(1, 1)) }, ShouldPanic)
+		})
+	})
+}
+
+func TestStandardScaler(t *testing.T) {
+	Convey("Given : 学習データが与えられた時", t, func() {
+		x := mat.NewDense(4, 2, []float64{1, 5, 2, 5, 3, 5, 6, 5})
+		Convey("When : Fitして変換する", func() {
+			s := NewStandardScaler()
+			s.Fit(x)
+			y := s.Transform(x)
+			Convey("Then : 各列の平均が0, 標準偏差が1となり、一定の列は0となること", func() {
+				mean, std := util.MeanStd(mat.Col(nil, 0, y))
+				So(mean, ShouldAlmostEqual, 0, 1e-12)
+				So(std, ShouldAlmostEqual, 1, 1e-12)
+				So(mat.Col(nil, 1, y), ShouldResemble, []float64{0, 0, 0, 0})
+				So(s.GetStates()["mean"].At(0, 0), ShouldEqual, 3)
+				So(s.GetStates()["std"].At(0, 0), ShouldEqual, math.Sqrt(3.5))
+			})
+		})
+	})
+}
+
+func TestChannelNormalizer(t *testing.T) {
+	Convey("Given : 2チャネル・1*2の画像2枚が与えられた時", t, func() {
+		x := mat.NewDense(2, 4, []float64{
+			0, 2, 10, 10,
+			4, 6, 30, 30,
+		})
+		shape := neuralNetwork.NewShape(2, 1, 2)
+		Convey("When : Fitして変換する", func() {
+			n := NewChannelNormalizer(shape)
+			n.Fit(x)
+			y := n.Transform(x)
+			Convey("Then : チャネル毎の平均・標準偏差で標準化されること", func() {
+				So(n.GetStates()["mean"].At(0, 0), ShouldEqual, 3)
+				So(n.GetStates()["mean"].At(0, 1), ShouldEqual, 20)
+				So(n.GetStates()["std"].At(0, 1), ShouldEqual, 10)
+				So(mat.Row(nil, 0, y)[2:], ShouldResemble, []float64{-1, -1})
+				So(y.At(1, 1), ShouldAlmostEqual, 3/math.Sqrt(5), 1e-12)
+			})
+		})
+		Convey("When : 平均・標準偏差を指定して変換する", func() {
+			n := NewChannelNormalizer(shape, WithChannelNormalizerStats([]float64{1, 10}, []float64{2, 5}))
+			y := n.Transform(x)
+			Convey("Then : Fitせずに指定した値で標準化されること", func() {
+				So(n.IsFitted(), ShouldBeTrue)
+				So(mat.Row(nil, 1, y), ShouldResemble, []float64{1.5, 2.5, 4, 4})
+			})
+		})
+		Convey("Then : 不正な指定の場合はpanicが発生すること", func() {
+			So(func() { NewChannelNormalizer(neuralNetwork.Shape{}) }, ShouldPanic)
+			So(func() { NewChannelNormalizer(shape, WithChannelNormalizerStats([]float64{1}, []float64{1})) }, ShouldPanic)
+			So(func() { NewChannelNormalizer(neuralNetwork.NewShape(3, 1, 2)).Fit(x) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,122 @@
+package transform
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Transformer : 入力データの前処理のIF
+// 学習データでFitした統計量を利用して、学習・検証・推論の全てのデータに同じ変換を行う
+type Transformer interface {
+	// Fit : (データ数, 特徴量の数)の学習データから変換に利用する統計量を算出する
+	Fit(x mat.Matrix)
+	// Transform : 入力データを変換した行列を取得する（入力データは変更しない）
+	Transform(x mat.Matrix) mat.Matrix
+	// IsFitted : 統計量が算出・設定済みかどうか
+	IsFitted() bool
+	// GetStates : 変換に利用する統計量を取得（model.WriteNNLayersWithTransformで保存する）
+	GetStates() map[string]mat.Matrix
+	// SetStates : 変換に利用する統計量を設定
+	SetStates(map[string]mat.Matrix)
+}
+
+// Pipeline : 複数のTransformerを順に適用する前処理
+type Pipeline struct {
+	transformers []Transformer
+}
+
+// NewPipeline : 指定した順に適用するPipelineを取得
+func NewPipeline(transformers ...Transformer) *Pipeline {
+	return &Pipeline{transformers: transformers}
+}
+
+// Add : Transformerを末尾に追加する
+func (p *Pipeline) Add(t Transformer) {
+	p.transformers = append(p.transformers, t)
+}
+
+// GetTransformers : 適用する順に並べたTransformerを取得
+func (p *Pipeline) GetTransformers() []Transformer {
+	return p.transformers
+}
+
+// Fit : 各Transformerを順にFitする（後のTransformerは前のTransformerで変換したデータでFitする）
+func (p *Pipeline) Fit(x mat.Matrix) {
+	p.FitTransform(x)
+}
+
+// FitTransform : 各Transformerを順にFitし、変換したデータを取得する
+func (p *Pipeline) FitTransform(x mat.Matrix) mat.Matrix {
+	for _, t := range p.transformers {
+		t.Fit(x)
+		x = t.Transform(x)
+	}
+	return x
+}
+
+// Transform : 各Transformerを順に適用したデータを取得する（Transformerが無い場合はそのまま返す）
+func (p *Pipeline) Transform(x mat.Matrix) mat.Matrix {
+	for _, t := range p.transformers {
+		x = t.Transform(x)
+	}
+	return x
+}
+
+// IsFitted : 全てのTransformerがFit済みかどうか
+func (p *Pipeline) IsFitted() bool {
+	for _, t := range p.transformers {
+		if !t.IsFitted() {
+			return false
+		}
+	}
+	return true
+}
+
+// GetStates : 各Transformerの統計量を"インデックス.キー"のキーで取得
+func (p *Pipeline) GetStates() map[string]mat.Matrix {
+	states := make(map[string]mat.Matrix)
+	for i, t := range p.transformers {
+		for key, state := range t.GetStates() {
+			states[fmt.Sprintf("%d.%s", i, key)] = state
+		}
+	}
+	return states
+}
+
+// SetStates : GetStatesで取得した統計量を各Transformerに設定
+func (p *Pipeline) SetStates(states map[string]mat.Matrix) {
+	for i, t := range p.transformers {
+		prefix := fmt.Sprintf("%d.", i)
+		tStates := make(map[string]mat.Matrix)
+		for key, state := range states {
+			if len(key) > len(prefix) && key[:len(prefix)] == prefix {
+				tStates[key[len(prefix):]] = state
+			}
+		}
+		t.SetStates(tStates)
+	}
+}
+
+// checkFitted : Fit済みでなければpanicを発生させる
+func checkFitted(t Transformer) {
+	if !t.IsFitted() {
+		panic("Fitまたは統計量の設定を行ってから変換してください")
+	}
+}
+
+// checkColumns : 入力データの特徴量の数が一致しなければpanicを発生させる
+func checkColumns(x mat.Matrix, expected int) {
+	if _, c := x.Dims(); c != expected {
+		panic(fmt.Sprintf("入力データの特徴量の数%dがFitしたデータの特徴量の数%dとマッチしてません", c, expected))
+	}
+}
+
+// getState : 統計量をコピーした行列として取得（存在しない場合はpanicが発生する）
+func getState(states map[string]mat.Matrix, key string) *mat.Dense {
+	state, ok := states[key]
+	if !ok {
+		panic(fmt.Sprintf("統計量%sがありません", key))
+	}
+	return mat.DenseCopyOf(state)
+}
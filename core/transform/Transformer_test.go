@@ -0,0 +1,39 @@
+package transform
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPipeline(t *testing.T) {
+	Convey("Given : MinMaxScalerとStandardScalerのPipelineが与えられた時", t, func() {
+		x := mat.NewDense(3, 2, []float64{0, 10, 5, 20, 10, 60})
+		pipeline := NewPipeline(NewMinMaxScaler())
+		pipeline.Add(NewStandardScaler())
+		Convey("Then : Fit前はFit済みではなく、変換するとpanicが発生すること", func() {
+			So(pipeline.IsFitted(), ShouldBeFalse)
+			So(func() { pipeline.Transform(x) }, ShouldPanic)
+		})
+		Convey("When : FitTransformする", func() {
+			y := pipeline.FitTransform(x)
+			Convey("Then : 各Transformerを順に適用した結果となること", func() {
+				So(pipeline.IsFitted(), ShouldBeTrue)
+				scaled := pipeline.GetTransformers()[0].Transform(x)
+				So(mat.Equal(y, pipeline.GetTransformers()[1].Transform(scaled)), ShouldBeTrue)
+				So(mat.Equal(pipeline.Transform(x), y), ShouldBeTrue)
+				So(x.At(2, 1), ShouldEqual, 60)
+			})
+			Convey("Then : 統計量を設定した別のPipelineで同じ変換ができること", func() {
+				other := NewPipeline(NewMinMaxScaler(), NewStandardScaler())
+				other.SetStates(pipeline.GetStates())
+				So(other.IsFitted(), ShouldBeTrue)
+				So(mat.Equal(other.Transform(x), y), ShouldBeTrue)
+			})
+		})
+		Convey("Then : Transformerが無いPipelineはそのまま返すこと", func() {
+			So(mat.Equal(NewPipeline().Transform(x), x), ShouldBeTrue)
+		})
+	})
+}
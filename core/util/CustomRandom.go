@@ -2,9 +2,84 @@ package util
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 )
 
+var (
+	globalRandMutex sync.Mutex
+	globalRand      = rand.New(NewSource(time.Now().UnixNano()))
+)
+
+// SetSeed : ライブラリ全体で利用する乱数のシードを設定する
+// 設定後にNewRandで取得する乱数生成器は、取得した順序に応じて決まったシードで初期化される
+// そのため、同じシードを設定して同じ順序で処理を行えば、同じ結果が得られる
+func SetSeed(seed int64) {
+	globalRandMutex.Lock()
+	defer globalRandMutex.Unlock()
+	globalRand = rand.New(NewSource(seed))
+}
+
+// NewRand : ライブラリ全体のシードから派生させた乱数生成器を取得する
+// SetSeedを呼んでいない場合は、現在時刻をシードとして利用する
+func NewRand() *rand.Rand {
+	rnd, _ := NewRandWithSource()
+	return rnd
+}
+
+// NewRandWithSource : NewRandと同様に乱数生成器を取得し、あわせて状態の保存・復元に利用できるソースを取得する
+func NewRandWithSource() (*rand.Rand, *SplitMix64Source) {
+	source := NewSource(nextSeed())
+	return rand.New(source), source
+}
+
+// nextSeed : ライブラリ全体の乱数生成器から、次に利用するシードを取得する
+func nextSeed() int64 {
+	globalRandMutex.Lock()
+	defer globalRandMutex.Unlock()
+	return globalRand.Int63()
+}
+
+// SplitMix64Source : SplitMix64アルゴリズムによる乱数のソース
+// 内部状態がuint64の値1つのため、状態の保存・復元が可能
+type SplitMix64Source struct {
+	state uint64
+}
+
+// NewSource : 指定したシードで初期化したSplitMix64Sourceを取得する
+func NewSource(seed int64) *SplitMix64Source {
+	return &SplitMix64Source{state: uint64(seed)}
+}
+
+// Seed : シードを設定し直す
+func (s *SplitMix64Source) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Uint64 : 次の乱数を取得する
+func (s *SplitMix64Source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 : 次の乱数を0以上の63bitの値で取得する
+func (s *SplitMix64Source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// GetState : 内部状態を取得する
+func (s *SplitMix64Source) GetState() uint64 {
+	return s.state
+}
+
+// SetState : 内部状態を設定する（GetStateで取得した時点から乱数列を再開できる）
+func (s *SplitMix64Source) SetState(state uint64) {
+	s.state = state
+}
+
 func randomFloat(r *rand.Rand, min, max float64) float64 {
 	return r.Float64()*(max-min) + min
 }
@@ -14,7 +89,7 @@ func randomFloat32(r *rand.Rand, min, max float32) float32 {
 }
 
 func RandomFloatArray(min, max float64, count int) []float64 {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := NewRand()
 	list := make([]float64, 0, count)
 	for i := 0; i < count; i++ {
 		v := randomFloat(r, min, max)
@@ -24,7 +99,7 @@ func RandomFloatArray(min, max float64, count int) []float64 {
 }
 
 func RandomFloatArray32(min, max float32, count int) []float32 {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := NewRand()
 	list := make([]float32, 0, count)
 	for i := 0; i < count; i++ {
 		v := randomFloat32(r, min, max)
@@ -34,7 +109,11 @@ func RandomFloatArray32(min, max float32, count int) []float32 {
 }
 
 func RandomIntArray(max int, count int) []int {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return RandomIntArrayWithRand(NewRand(), max, count)
+}
+
+// RandomIntArrayWithRand : 指定した乱数生成器を利用して、0以上max未満の整数をcount個取得する
+func RandomIntArrayWithRand(r *rand.Rand, max int, count int) []int {
 	list := make([]int, 0, count)
 	for i := 0; i < count; i++ {
 		list = append(list, r.Intn(max))
@@ -43,7 +122,7 @@ func RandomIntArray(max int, count int) []int {
 }
 
 func NormRandomArray(stdenv float64, count int) []float64 {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r := NewRand()
 	list := make([]float64, count)
 	for i, _ := range list {
 		// NormFloat64は平均0, 標準偏差1の正規分布を作成
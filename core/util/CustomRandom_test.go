@@ -0,0 +1,51 @@
+package util
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSetSeed(t *testing.T) {
+	Convey("Given : ライブラリ全体のシードを42に設定した時", t, func() {
+		SetSeed(42)
+		Convey("When : 乱数生成器を2つ取得し、乱数列を作成する", func() {
+			first := NewRand().Int63()
+			second := NewRand().Int63()
+			floats := NormRandomArray(1, 5)
+			Convey("Then : 再度同じシードを設定すると、同じ順序で同じ乱数列が得られること", func() {
+				SetSeed(42)
+				So(NewRand().Int63(), ShouldEqual, first)
+				So(NewRand().Int63(), ShouldEqual, second)
+				So(NormRandomArray(1, 5), ShouldResemble, floats)
+			})
+			Convey("Then : 取得した乱数生成器毎に異なる乱数列になること", func() {
+				So(first, ShouldNotEqual, second)
+			})
+		})
+	})
+}
+
+func TestSplitMix64Source(t *testing.T) {
+	Convey("Given : シード1のSplitMix64Sourceが与えられた時", t, func() {
+		src := NewSource(1)
+		r := rand.New(src)
+		Convey("When : 乱数を生成した途中で内部状態を保存する", func() {
+			r.Float64()
+			state := src.GetState()
+			expected := []float64{r.Float64(), r.NormFloat64(), float64(r.Intn(100))}
+			Convey("Then : 内部状態を復元すると、保存した時点から同じ乱数列が得られること", func() {
+				restored := NewSource(0)
+				restored.SetState(state)
+				r2 := rand.New(restored)
+				So([]float64{r2.Float64(), r2.NormFloat64(), float64(r2.Intn(100))}, ShouldResemble, expected)
+			})
+		})
+		Convey("Then : Int63は0以上の値であること", func() {
+			for i := 0; i < 100; i++ {
+				So(src.Int63(), ShouldBeGreaterThanOrEqualTo, 0)
+			}
+		})
+	})
+}
@@ -13,3 +13,12 @@ func Transpose(a mat.Matrix) mat.Matrix {
 	}
 	return dense
 }
+
+// CopyMatrixMap : パラメーター・勾配・状態などの各行列を*mat.Denseとしてコピーする
+func CopyMatrixMap(src map[string]mat.Matrix) map[string]mat.Matrix {
+	dst := make(map[string]mat.Matrix, len(src))
+	for key, m := range src {
+		dst[key] = mat.DenseCopyOf(m)
+	}
+	return dst
+}
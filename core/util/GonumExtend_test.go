@@ -0,0 +1,27 @@
+package util
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCopyMatrixMap(t *testing.T) {
+	Convey("Given : 行列とベクトルを持つmapが与えられた時", t, func() {
+		src := map[string]mat.Matrix{
+			"w": mat.NewDense(2, 2, []float64{1, 2, 3, 4}),
+			"b": mat.NewVecDense(2, []float64{5, 6}),
+		}
+		Convey("When : コピーを取得し、コピーの値を変更する", func() {
+			dst := CopyMatrixMap(src)
+			dst["w"].(*mat.Dense).Set(0, 0, 100)
+			Convey("Then : 同じ値の*mat.Denseとなり、元の行列は変更されないこと", func() {
+				So(mat.Equal(dst["b"], src["b"]), ShouldBeTrue)
+				So(src["w"].At(0, 0), ShouldEqual, 1)
+				_, ok := dst["b"].(*mat.Dense)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -1,5 +1,7 @@
 package util
 
+import "math"
+
 func MaxValue(vs []float64) (key int, max float64) {
 	max = vs[0]
 	key = 0
@@ -11,3 +13,15 @@ func MaxValue(vs []float64) (key int, max float64) {
 	}
 	return key, max
 }
+
+// MeanStd : 平均と標準偏差（データ数で割る母標準偏差）を取得
+func MeanStd(vs []float64) (mean float64, std float64) {
+	for _, v := range vs {
+		mean += v
+	}
+	mean /= float64(len(vs))
+	for _, v := range vs {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(vs)))
+}
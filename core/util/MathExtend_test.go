@@ -0,0 +1,19 @@
+package util
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMeanStd(t *testing.T) {
+	Convey("Given : 値の配列が与えられた時", t, func() {
+		Convey("When : 平均と標準偏差を算出する", func() {
+			mean, std := MeanStd([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+			Convey("Then : 平均と母標準偏差が得られること", func() {
+				So(mean, ShouldEqual, 5)
+				So(std, ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -1,9 +1,15 @@
 package util
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // DownloadFile : download file from specific url and write file.
@@ -27,3 +33,64 @@ func DownloadFile(filepath string, url string) error {
 	return err
 }
 
+// DownloadFileWithSHA256 : 指定したURLからファイルをダウンロードし、SHA-256を検証してから書き込む
+// 同じフォルダの一時ファイルに書き込み、検証後に名前を変更するため、途中で失敗しても不完全なファイルは残らない
+// client : 利用するHTTPクライアント（nilの場合はhttp.DefaultClient）, checksum : 16進数のSHA-256（空の場合は検証しない）
+func DownloadFileWithSHA256(client *http.Client, filePath string, url string, checksum string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%sのダウンロードに失敗しました : %s", url, resp.Status)
+	}
+
+	// 一時ファイルに書き込みながらハッシュ値を計算する
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := verifySHA256(url, hex.EncodeToString(hash.Sum(nil)), checksum); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// VerifyFileSHA256 : ファイルのSHA-256が指定した値と一致するかを検証する（checksumが空の場合は検証しない）
+func VerifyFileSHA256(filePath string, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	return verifySHA256(filePath, hex.EncodeToString(hash.Sum(nil)), checksum)
+}
+
+func verifySHA256(name string, actual string, expected string) error {
+	if expected != "" && !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%sのSHA-256が一致しません : expected %s, actual %s", name, expected, actual)
+	}
+	return nil
+}
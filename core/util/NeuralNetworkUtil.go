@@ -1,12 +1,90 @@
 package util
 
-// Im2col : 各画像データ（複数チャネルを保持しているため、3次元データ）をベクトルデータに変換する
-// input : 4次元配列の画像データ.
-// - 1次元目 : 幅の分のデータ
-// - 2次元目 : 高さの分のデータ
-// - 3次元目 : チャネルの分のデータ
-// - 4次元目 : データ数
-// ex) input
-/*func Im2col(input [][][][]float64, filterH int, filterW int, stride int, padding int) mat.Matrix {
-
-}*/
+import "gonum.org/v1/gonum/mat"
+
+// ConvOutputSize : 畳み込み（プーリング）処理後の出力サイズを算出
+// inputSize : 入力の幅（高さ）
+// filterSize : フィルターの幅（高さ）
+// stride : ストライド
+// padding : パディング
+func ConvOutputSize(inputSize int, filterSize int, stride int, padding int) int {
+	return (inputSize+2*padding-filterSize)/stride + 1
+}
+
+// Im2col : 各画像データ（複数チャネルを保持しているため、3次元データ）を行列データに変換する
+// input : 画像データ. (データ数, チャネル数*高さ*幅)の行列で、各行はチャネル→高さ→幅の順に並んでいること
+// c : チャネル数
+// h : 高さ
+// w : 幅
+// 戻り値は(データ数*出力の高さ*出力の幅, チャネル数*フィルターの高さ*フィルターの幅)の行列
+func Im2col(input mat.Matrix, c int, h int, w int, filterH int, filterW int, stride int, padding int) mat.Matrix {
+	batch, col := input.Dims()
+	if col != c*h*w {
+		panic("入力された画像データと指定した幅・高さ・チャネル数がマッチしてません")
+	}
+	outH := ConvOutputSize(h, filterH, stride, padding)
+	outW := ConvOutputSize(w, filterW, stride, padding)
+	src := mat.DenseCopyOf(input)
+
+	dense := mat.NewDense(batch*outH*outW, c*filterH*filterW, nil)
+	for n := 0; n < batch; n++ {
+		image := src.RawRowView(n)
+		for oy := 0; oy < outH; oy++ {
+			for ox := 0; ox < outW; ox++ {
+				row := dense.RawRowView((n*outH+oy)*outW + ox)
+				for ch := 0; ch < c; ch++ {
+					for fy := 0; fy < filterH; fy++ {
+						y := oy*stride + fy - padding
+						for fx := 0; fx < filterW; fx++ {
+							x := ox*stride + fx - padding
+							// パディング部分は0のままとする
+							if y < 0 || y >= h || x < 0 || x >= w {
+								continue
+							}
+							row[(ch*filterH+fy)*filterW+fx] = image[(ch*h+y)*w+x]
+						}
+					}
+				}
+			}
+		}
+	}
+	return dense
+}
+
+// Col2im : Im2colで変換した行列データを画像データに戻す（Im2colの逆変換）
+// 同じ画素を参照する要素は加算される
+// input : (データ数*出力の高さ*出力の幅, チャネル数*フィルターの高さ*フィルターの幅)の行列
+// batch : データ数
+// 戻り値は(データ数, チャネル数*高さ*幅)の行列
+func Col2im(input mat.Matrix, batch int, c int, h int, w int, filterH int, filterW int, stride int, padding int) mat.Matrix {
+	outH := ConvOutputSize(h, filterH, stride, padding)
+	outW := ConvOutputSize(w, filterW, stride, padding)
+	r, col := input.Dims()
+	if r != batch*outH*outW || col != c*filterH*filterW {
+		panic("入力された行列と指定した幅・高さ・チャネル数・フィルターサイズがマッチしてません")
+	}
+	src := mat.DenseCopyOf(input)
+
+	dense := mat.NewDense(batch, c*h*w, nil)
+	for n := 0; n < batch; n++ {
+		image := dense.RawRowView(n)
+		for oy := 0; oy < outH; oy++ {
+			for ox := 0; ox < outW; ox++ {
+				row := src.RawRowView((n*outH+oy)*outW + ox)
+				for ch := 0; ch < c; ch++ {
+					for fy := 0; fy < filterH; fy++ {
+						y := oy*stride + fy - padding
+						for fx := 0; fx < filterW; fx++ {
+							x := ox*stride + fx - padding
+							if y < 0 || y >= h || x < 0 || x >= w {
+								continue
+							}
+							image[(ch*h+y)*w+x] += row[(ch*filterH+fy)*filterW+fx]
+						}
+					}
+				}
+			}
+		}
+	}
+	return dense
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestIm2col(t *testing.T) {
+	Convey("Given : 1チャネル・高さ3・幅3の画像が1個与えられた時", t, func() {
+		// [1, 2, 3]
+		// [4, 5, 6]
+		// [7, 8, 9]
+		x := mat.NewDense(1, 9, CreateFloatArrayByStep(9, 1, 1))
+		Convey("When : 2*2のフィルター、ストライド1、パディング0でIm2colを実施", func() {
+			col := Im2col(x, 1, 3, 3, 2, 2, 1, 0)
+			Convey("Then : 4*4の行列が出力されること", func() {
+				expected := mat.NewDense(4, 4, []float64{
+					1, 2, 4, 5,
+					2, 3, 5, 6,
+					4, 5, 7, 8,
+					5, 6, 8, 9,
+				})
+				So(mat.Equal(col, expected), ShouldBeTrue)
+			})
+			Convey("Then : Col2imで戻すと、各画素は参照された回数分だけ加算されること", func() {
+				img := Col2im(col, 1, 1, 3, 3, 2, 2, 1, 0)
+				expected := mat.NewDense(1, 9, []float64{1, 4, 3, 8, 20, 12, 7, 16, 9})
+				So(mat.Equal(img, expected), ShouldBeTrue)
+			})
+		})
+
+		Convey("When : 2*2のフィルター、ストライド2、パディング1でIm2colを実施", func() {
+			col := Im2col(x, 1, 3, 3, 2, 2, 2, 1)
+			Convey("Then : パディング部分が0で埋められた4*4の行列が出力されること", func() {
+				expected := mat.NewDense(4, 4, []float64{
+					0, 0, 0, 1,
+					0, 0, 2, 3,
+					0, 4, 0, 7,
+					5, 6, 8, 9,
+				})
+				So(mat.Equal(col, expected), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestConvOutputSize(t *testing.T) {
+	Convey("Given : 入力サイズ28, フィルターサイズ5が与えられた時", t, func() {
+		Convey("Then : ストライド1, パディング0なら出力サイズは24となること", func() {
+			So(ConvOutputSize(28, 5, 1, 0), ShouldEqual, 24)
+		})
+		Convey("Then : ストライド1, パディング2なら出力サイズは28となること", func() {
+			So(ConvOutputSize(28, 5, 1, 2), ShouldEqual, 28)
+		})
+	})
+}
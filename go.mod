@@ -9,7 +9,6 @@ require (
 	github.com/jtolds/gls v4.20.0+incompatible
 	github.com/jung-kurt/gofpdf v1.0.0
 	github.com/llgcode/draw2d v0.0.0-20180825133448-f52c8a71aff0
-	github.com/petar/GoMNIST v0.0.0-20150320212226-2fbe10d0fa63
 	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d
 	github.com/smartystreets/goconvey v0.0.0-20190710185942-9d28bd7c0945
 	golang.org/x/exp v0.0.0-20180907224206-e88728d35e99
@@ -4,25 +4,39 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/goMLLibrary/core/augment"
+	"github.com/goMLLibrary/core/dataset"
 	"github.com/goMLLibrary/core/graph"
 	"github.com/goMLLibrary/core/mnist"
+	"github.com/goMLLibrary/core/model"
 	"github.com/goMLLibrary/core/neuralNetwork"
+	"github.com/goMLLibrary/core/trainer"
+	"github.com/goMLLibrary/core/transform"
+	"github.com/goMLLibrary/core/util"
 )
 
 func main() {
+	// 学習結果を再現できるように、乱数のシードを固定する
+	util.SetSeed(1)
+
 	// ニューラルネットワーク層をまとめるレイヤーの作成
 	layers := neuralNetwork.NewDefaultNeuralNetworkLayers()
 
-	// 1層目
-	layers.Add(neuralNetwork.NewAffine(28*28, 1000))
+	// 入力データの形状（1チャネル・28*28の画像）
+	layers.SetInputShape(neuralNetwork.NewShape(1, 28, 28))
+
+	// 1層目 : 畳み込み層（活性化関数がReluのため、重みはHeの初期値で初期化）
+	layers.Add(neuralNetwork.NewConv2D(30, 5, 5, neuralNetwork.WithConv2DWeightInitializer(neuralNetwork.NewHeNormalInitializer())))
 	layers.Add(neuralNetwork.NewRelu())
+	layers.Add(neuralNetwork.NewMaxPooling2D(2, 2))
 
 	// 2層目
-	layers.Add(neuralNetwork.NewAffine(1000, 1000))
+	layers.Add(neuralNetwork.NewFlatten())
+	layers.Add(neuralNetwork.NewAffineWithOutputSize(100, neuralNetwork.WithAffineWeightInitializer(neuralNetwork.NewHeNormalInitializer())))
 	layers.Add(neuralNetwork.NewRelu())
 
 	// 3層目
-	layers.Add(neuralNetwork.NewAffine(1000, 10))
+	layers.Add(neuralNetwork.NewAffineWithOutputSize(10))
 
 	// MNISTデータセットを格納するためのフォルダを作成
 	os.Mkdir("data", 0777)
@@ -30,14 +44,33 @@ func main() {
 	// MNISTのデータセットを取得
 	train, test, err := mnist.LoadData("data")
 	if err != nil {
-		fmt.Printf("Can't get mnist train and test data! \n")
+		fmt.Printf("Can't get mnist train and test data! : %v\n", err)
 		os.Exit(-1)
 	}
 
+	// テストデータはモデルの最終評価にのみ利用するため、学習データの1/6（10000件）を検証データとして分割する
+	// 各数字の比率が学習データと同じになるように層化して分割する
+	trainSubset, validationSubset := dataset.TrainValidationSplit(train, 1.0/6)
+
+	// 0〜255の画素値を、学習データの平均0・標準偏差1となるように標準化する
+	// 統計量はメモリ使用量を抑えるため、学習データから層化して抽出した1/5（10000件）で算出する
+	pipeline := transform.NewPipeline(transform.NewChannelNormalizer(neuralNetwork.NewShape(1, 28, 28)))
+	transform.FitDataset(pipeline, dataset.StratifiedSplit(trainSubset, []float64{1, 4})[0])
+
+	// 学習データはミニバッチの作成時に、上下左右に最大2画素ランダムに平行移動してから標準化する
+	augmented := augment.NewDataset(trainSubset, neuralNetwork.NewShape(1, 28, 28), augment.NewRandomShift(2, 2))
+	trainSet := transform.NewDataset(augmented, pipeline)
+	validationSet := transform.NewDataset(validationSubset, pipeline)
+	testSet := transform.NewDataset(test, pipeline)
+
 	// 学習時の各種パラメーターの設定
+	// 最大epoch数（検証データの損失が2epoch改善しない場合は途中で終了する）
 	batchSize := 100
-	iterationCount := 1800
-	iteracionCountPerEpoch := int(train.Count() / batchSize)
+	epochs := 20
+	patience := 2
+
+	// 1epoch毎に学習率を0.5倍する
+	scheduler := neuralNetwork.NewStepDecay(layers.GetOptimizer(), 1, neuralNetwork.WithStepDecayGamma(0.5))
 
 	// 学習時の様子をグラフに描画するための準備
 	graphCreater, err := graph.NewGraphCreater("output")
@@ -50,48 +83,36 @@ func main() {
 	param.YLabel = "accuracy"
 	trainPoints := graph.NewGraphPoints("train")
 
-	// 学習の実行
-	for i := 0; i < iterationCount; i++ {
-		// 入力用のデータを取得
-		rawSet := mnist.ExtractRandomDataSet(train, batchSize)
-		x, t := mnist.ConvertMatrixFromDataSet(rawSet)
-
-		// forward処理の実施
-		loss, acc := layers.Forward(x, t)
-
-		// backward処理の実施
-		layers.Backward()
-
-		// 1epoch事にloss,accuracyを出力
-		if (i % iteracionCountPerEpoch) == 0 {
-			fmt.Printf("train %d iteration : loss is %f, accuracy is %f\n", i, loss, acc)
-		}
+	// 検証データの損失が最良のNNを、推論時に同じ前処理を適用できるように前処理とあわせて保存する
+	checkpoint := trainer.NewModelCheckpoint("output/mnist_best.db", trainer.MonitorValLoss,
+		trainer.WithModelCheckpointTransform(pipeline))
+
+	// 学習の実行（epoch毎に全データを1回ずつ利用し、検証データで評価する）
+	// 次のミニバッチは学習中に別のgoroutineで作成しておく
+	tr := trainer.NewTrainer(layers, trainSet, batchSize, epochs,
+		trainer.WithTrainerValidationData(validationSet),
+		trainer.WithTrainerPrefetch(),
+		trainer.WithTrainerCallbacks(
+			trainer.NewLoggingCallback(trainer.WithLoggingCallbackBatchInterval(100)),
+			trainer.NewGraphPointsCallback(&trainPoints, trainer.MonitorAccuracy, trainer.WithGraphPointsCallbackPerBatch()),
+			trainer.NewSchedulerCallback(scheduler),
+			trainer.NewEarlyStopping(trainer.MonitorValLoss, patience, trainer.WithEarlyStoppingRestoreBestWeights()),
+			checkpoint,
+		))
+	tr.Fit()
+	if err := checkpoint.GetError(); err != nil {
+		fmt.Printf("Can't save the best model! : %v\n", err)
+	}
 
-		// 勾配のupdate処理の実施
-		layers.Update()
+	// 予測の実行（メモリ使用量を抑えるため、バッチサイズ毎に分割して実施）
+	testLoss, testAcc := tr.Evaluate(testSet)
+	fmt.Printf("test : loss is %f, accuracy is %f\n", testLoss, testAcc)
 
-		// グラフへ追加
-		trainPoints.AddPoint(graph.NewPoint(float64(i), acc))
+	// 推論時にも同じ前処理を行えるように、前処理の統計量とあわせて保存する
+	if err := model.WriteNNLayersWithTransform("output/mnist.db", layers, pipeline); err != nil {
+		fmt.Printf("Can't save the model! : %v\n", err)
 	}
 
-	/*
-		iteracionCountPerEpoch = int(test.Count() / batchSize)
-		iterationCount = 100
-		for i := 0; i < iterationCount; i++ {
-			rawSet := mnist.ExtractRandomDataSet(test, batchSize)
-			x, t := mnist.ConvertMatrixFromDataSet(rawSet)
-
-			// Forward処理の実施
-			loss, acc := layers.Forward(x, t)
-
-			fmt.Printf("test %d iteration : loss is %f, accuracy is %f\n", i, loss, acc)
-		}
-	*/
-	// 予測の実行
-	x, t := mnist.ConvertMatrixFromDataSet(test)
-	loss, acc := layers.Forward(x, t)
-	fmt.Printf("test : loss is %f, accuracy is %f\n", loss, acc)
-
 	// グラフの作成
 	graphCreater.SaveLineGraph(param, []graph.GraphPoints{trainPoints})
 